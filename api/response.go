@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iec104/iec_client"
+	"io"
+	"net/http"
+)
+
+// maxBodySize limits request bodies, which only hold small JSON objects
+const maxBodySize = 64 << 10
+
+// requestError is an error caused by the request itself
+type requestError struct {
+	err error
+}
+
+func (e requestError) Error() string { return e.err.Error() }
+func (e requestError) Unwrap() error { return e.err }
+
+func badRequest(err error) error {
+	return requestError{err}
+}
+
+// errorBody is the JSON body of error responses
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+}
+
+// writeError answers with the status matching the error
+func writeError(w http.ResponseWriter, err error) {
+	var reqErr requestError
+	status := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &reqErr), errors.Is(err, iec_client.ErrorUnknownDataType):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrorUnknownProfile), errors.Is(err, ErrorPointNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrorNoClients):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, ErrorUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, ErrorForeignOrigin):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrorContentType):
+		status = http.StatusUnsupportedMediaType
+	}
+	writeJSON(w, status, errorBody{err.Error()})
+}
+
+// resultStatus is the status of a command result: 200 once confirmed, 502
+// when the server rejected it, 504 on timeout, 503 without a link and 409
+// while another command to the address is pending
+func resultStatus(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, iec_client.ErrorNegativeConfirmation):
+		return http.StatusBadGateway
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, iec_client.ErrorNoConnection):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, iec_client.ErrorCommandPending):
+		return http.StatusConflict
+	case errors.Is(err, iec_client.ErrorInvalidGroup):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// allow answers 405 unless the request uses the method
+func allow(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeJSON(w, http.StatusMethodNotAllowed, errorBody{fmt.Sprintf("method %s not allowed", r.Method)})
+	return false
+}
+
+// decodeBody reads a JSON body into v; an empty body leaves v unchanged
+func decodeBody(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return badRequest(fmt.Errorf("invalid body: %w", err))
+	}
+	return nil
+}
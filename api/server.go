@@ -0,0 +1,470 @@
+// Package api serves an HTTP JSON API to read the points of IEC104Client
+// instances and to send them interrogations and commands.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"iec104/iec_client"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefix is the path all endpoints are served under
+const Prefix = "/api/v1"
+
+var (
+	ErrorNoClients      = fmt.Errorf("no connection is served")
+	ErrorUnknownProfile = fmt.Errorf("unknown profile")
+	ErrorPointNotFound  = fmt.Errorf("point not found")
+	ErrorUnauthorized   = fmt.Errorf("missing or wrong bearer token")
+	ErrorForeignOrigin  = fmt.Errorf("origin not allowed")
+	ErrorContentType    = fmt.Errorf("content type must be application/json")
+)
+
+// Server serves the API for a changing set of clients. Requests select a
+// client with the profile query parameter and default to the first one.
+type Server struct {
+	// CommandTimeout bounds the wait for command confirmations
+	CommandTimeout time.Duration
+	// Token, if set, must be sent as a bearer token with interrogations
+	// and commands
+	Token string
+	// Origins are the origins, e.g. https://hmi.example.com, whose pages
+	// may open the WebSocket and send interrogations and commands besides
+	// those of the API's own host; "*" allows all
+	Origins []string
+
+	mu      sync.Mutex
+	clients []*iec_client.IEC104Client
+	mux     *http.ServeMux
+}
+
+// NewServer creates a server without clients
+func NewServer() *Server {
+	s := &Server{
+		CommandTimeout: iec_client.DefaultCommandTimeout,
+		mux:            http.NewServeMux(),
+	}
+	s.mux.HandleFunc(Prefix+"/profiles", s.handleProfiles)
+	s.mux.HandleFunc(Prefix+"/status", s.handleStatus)
+	s.mux.HandleFunc(Prefix+"/points", s.handlePoints)
+	s.mux.HandleFunc(Prefix+"/points/", s.handlePoint)
+	s.mux.HandleFunc(Prefix+"/history/", s.handleHistory)
+	s.mux.HandleFunc(Prefix+"/interrogation", s.handleInterrogation)
+	s.mux.HandleFunc(Prefix+"/commands", s.handleCommand)
+	s.mux.HandleFunc(WebSocketPath, s.handleWebSocket)
+	return s
+}
+
+// Add starts serving a client
+func (s *Server) Add(c *iec_client.IEC104Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.clients {
+		if existing == c {
+			return
+		}
+	}
+	s.clients = append(s.clients, c)
+}
+
+// Remove stops serving a client
+func (s *Server) Remove(c *iec_client.IEC104Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.clients {
+		if existing == c {
+			s.clients = append(s.clients[:i], s.clients[i+1:]...)
+			return
+		}
+	}
+}
+
+// Serve listens on addr and serves the API in the background until the
+// returned server is closed
+func (s *Server) Serve(addr string) (*http.Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	return srv, nil
+}
+
+// ServeHTTP routes a request to its endpoint
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// client returns the client selected by the profile query parameter
+func (s *Server) client(r *http.Request) (*iec_client.IEC104Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.clients) == 0 {
+		return nil, ErrorNoClients
+	}
+	name := r.URL.Query().Get("profile")
+	if name == "" {
+		return s.clients[0], nil
+	}
+	for _, c := range s.clients {
+		if c.Profile().Name == name {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrorUnknownProfile, name)
+}
+
+// Profile describes a served connection
+type Profile struct {
+	Name      string `json:"name"`
+	Server    string `json:"server"`
+	Connected bool   `json:"connected"`
+}
+
+func (s *Server) handleProfiles(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	s.mu.Lock()
+	clients := append([]*iec_client.IEC104Client(nil), s.clients...)
+	s.mu.Unlock()
+
+	profiles := make([]Profile, 0, len(clients))
+	for _, c := range clients {
+		profiles = append(profiles, Profile{
+			Name:      c.Profile().Name,
+			Server:    serverAddr(c),
+			Connected: c.Connected.Load(),
+		})
+	}
+	writeJSON(w, http.StatusOK, profiles)
+}
+
+// Status is the state and link counters of a connection
+type Status struct {
+	Profile        string     `json:"profile"`
+	Server         string     `json:"server"`
+	CommonAddr     int        `json:"ca"`
+	Connected      bool       `json:"connected"`
+	ConnectedSince *time.Time `json:"connected_since,omitempty"`
+	LastReceived   *time.Time `json:"last_received,omitempty"`
+	LastSent       *time.Time `json:"last_sent,omitempty"`
+	Connects       uint64     `json:"connects"`
+	Reconnects     uint64     `json:"reconnects"`
+	Disconnects    uint64     `json:"disconnects"`
+	FramesReceived uint64     `json:"frames_received"`
+	FramesSent     uint64     `json:"frames_sent"`
+	Interrogations uint64     `json:"interrogations"`
+	Negative       uint64     `json:"negative_confirmations"`
+	Points         int        `json:"points"`
+}
+
+// NewStatus describes the state of a client
+func NewStatus(c *iec_client.IEC104Client) Status {
+	stats := c.Stats()
+	return Status{
+		Profile:        c.Profile().Name,
+		Server:         serverAddr(c),
+		CommonAddr:     c.Profile().CommonAddress,
+		Connected:      c.Connected.Load(),
+		ConnectedSince: optionalTime(stats.ConnectedSince),
+		LastReceived:   optionalTime(stats.LastReceived),
+		LastSent:       optionalTime(stats.LastSent),
+		Connects:       stats.Connects,
+		Reconnects:     stats.Reconnects,
+		Disconnects:    stats.Disconnects,
+		FramesReceived: stats.Received.Total(),
+		FramesSent:     stats.Sent.Total(),
+		Interrogations: stats.Interrogations,
+		Negative:       stats.NegativeConfirmations,
+		Points:         len(c.Snapshot()),
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	c, err := s.client(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, NewStatus(c))
+}
+
+// handlePoints lists the points passing the type, min_ioa and max_ioa
+// query parameters
+func (s *Server) handlePoints(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	c, err := s.client(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	filter, err := ParseFilter(r.URL.Query().Get)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	records := []iec_client.Record{}
+	for _, u := range c.Snapshot() {
+		if filter.Match(u) {
+			records = append(records, iec_client.NewRecord(c.Profile(), u))
+		}
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+// handlePoint returns one point, addressed as /points/{type}/{ioa}
+func (s *Server) handlePoint(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	c, err := s.client(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, Prefix+"/points/"), "/")
+	if len(parts) != 2 {
+		writeError(w, ErrorPointNotFound)
+		return
+	}
+	typ, err := iec_client.ParseDataType(parts[0])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ioa, err := strconv.Atoi(parts[1])
+	if err != nil {
+		writeError(w, badRequest(fmt.Errorf("invalid ioa %q", parts[1])))
+		return
+	}
+	u, ok := c.Point(typ, ioa)
+	if !ok {
+		writeError(w, ErrorPointNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, iec_client.NewRecord(c.Profile(), u))
+}
+
+// Sample is one recorded telemetry value
+type Sample struct {
+	Time    time.Time `json:"time"`
+	Value   float64   `json:"value"`
+	Raw     float64   `json:"raw"`
+	Quality string    `json:"quality"`
+}
+
+// History is the recorded values of a telemetry point
+type History struct {
+	Address int      `json:"ioa"`
+	Name    string   `json:"name,omitempty"`
+	Unit    string   `json:"unit,omitempty"`
+	Samples []Sample `json:"samples"`
+}
+
+// handleHistory returns the samples of /history/{ioa}, limited by the
+// since query parameter: a time in RFC 3339 or a duration back from now
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	c, err := s.client(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ioa, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, Prefix+"/history/"))
+	if err != nil {
+		writeError(w, badRequest(fmt.Errorf("invalid ioa in %s", r.URL.Path)))
+		return
+	}
+	since, err := parseSince(r.URL.Query().Get("since"), time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h := History{Address: ioa, Samples: []Sample{}}
+	if point := c.Profile().FindPoint(iec_client.Telemetry.PointType(), ioa); point != nil {
+		h.Name = point.Name
+		h.Unit = point.Unit
+	}
+	for _, sample := range c.History(ioa, since) {
+		h.Samples = append(h.Samples, Sample{
+			Time:    sample.Time,
+			Value:   sample.Value,
+			Raw:     sample.Raw,
+			Quality: iec_client.QualityString(sample.Quality),
+		})
+	}
+	writeJSON(w, http.StatusOK, h)
+}
+
+// acceptPost checks a request that sends to the server: it must be a POST
+// from an allowed origin with a JSON body and the bearer token. Requiring
+// JSON keeps web pages from sending it as a simple cross-site request.
+func (s *Server) acceptPost(w http.ResponseWriter, r *http.Request) bool {
+	if !allow(w, r, http.MethodPost) {
+		return false
+	}
+	if !s.checkOrigin(r) {
+		writeError(w, ErrorForeignOrigin)
+		return false
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeError(w, ErrorContentType)
+		return false
+	}
+	return s.authorized(w, r)
+}
+
+// authorized checks the bearer token of a request that sends to the
+// server and answers 401 when it does not match
+func (s *Server) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if s.Token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) == 1 {
+		return true
+	}
+	w.Header().Set("WWW-Authenticate", `Bearer realm="iec104"`)
+	writeError(w, ErrorUnauthorized)
+	return false
+}
+
+// handleInterrogation runs an interrogation; the body is optional
+func (s *Server) handleInterrogation(w http.ResponseWriter, r *http.Request) {
+	if !s.acceptPost(w, r) {
+		return
+	}
+	c, err := s.client(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var req iec_client.InterrogationRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.CommandTimeout)
+	defer cancel()
+	result, err := c.Interrogate(ctx, req)
+	writeJSON(w, resultStatus(err), result)
+}
+
+// handleCommand sends a command and answers with its result once it is
+// confirmed, rejected or timed out
+func (s *Server) handleCommand(w http.ResponseWriter, r *http.Request) {
+	if !s.acceptPost(w, r) {
+		return
+	}
+	c, err := s.client(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var req iec_client.CommandRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if _, _, err := req.Parse(); err != nil {
+		writeError(w, badRequest(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.CommandTimeout)
+	defer cancel()
+	result, err := c.Execute(ctx, req)
+	writeJSON(w, resultStatus(err), result)
+}
+
+// ParseFilter reads a point filter from the type, min_ioa, max_ioa and ca
+// parameters. type and ca may list several values separated by commas.
+func ParseFilter(get func(string) string) (iec_client.Filter, error) {
+	var f iec_client.Filter
+	for _, name := range splitList(get("type")) {
+		t, err := iec_client.ParseDataType(name)
+		if err != nil {
+			return f, badRequest(err)
+		}
+		f.Types = append(f.Types, t)
+	}
+	for _, p := range []struct {
+		name  string
+		value *int
+	}{{"min_ioa", &f.MinAddress}, {"max_ioa", &f.MaxAddress}} {
+		if v := get(p.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return f, badRequest(fmt.Errorf("invalid %s %q", p.name, v))
+			}
+			*p.value = n
+		}
+	}
+	for _, v := range splitList(get("ca")) {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return f, badRequest(fmt.Errorf("invalid ca %q", v))
+		}
+		f.CommonAddrs = append(f.CommonAddrs, n)
+	}
+	return f, nil
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// parseSince reads a time in RFC 3339 or a duration back from now
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return t, badRequest(fmt.Errorf("invalid since %q: a time in RFC 3339 or a duration", s))
+	}
+	return t, nil
+}
+
+func serverAddr(c *iec_client.IEC104Client) string {
+	profile := c.Profile()
+	return net.JoinHostPort(profile.IPAddress, strconv.Itoa(profile.Port))
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
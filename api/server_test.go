@@ -0,0 +1,309 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"iec104/config"
+	"iec104/iec_client"
+	"iec104/simulator"
+	"iec104/simulator/simtest"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// connectStation starts a simulated station and a client connected to it
+// that has run a general interrogation
+func connectStation(t *testing.T) (*simulator.Server, *iec_client.IEC104Client) {
+	t.Helper()
+	points := []simulator.Point{
+		{Address: config.TeleindBaseAddress, Kind: simulator.KindSingle, Value: 1},
+		{Address: config.TelemetryBaseAddress, Kind: simulator.KindFloat, Value: 12.5},
+		{Address: config.TelecontrolBaseAddress, Kind: simulator.KindCommand, Feedback: config.TeleindBaseAddress},
+		{Address: config.TelecontrolBaseAddress + 1, Kind: simulator.KindCommand, Reject: true},
+	}
+	sim := simtest.Station(t, points...)
+	client := iec_client.NewIEC104Client(simtest.Profile(t, sim.Addr()))
+	client.Logger = simtest.NopLogger{}
+	t.Cleanup(client.Close)
+	simtest.Connect(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.InterrogateContext(ctx, asdu.QOIStation); err != nil {
+		t.Fatal(err)
+	}
+	return sim, client
+}
+
+// request sends a request to the server and decodes the JSON answer into v
+func request(t *testing.T, s *Server, method, target, token, body string, v interface{}) int {
+	t.Helper()
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if method == http.MethodPost {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if v != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s %s: %v in %q", method, target, err, w.Body.String())
+		}
+	}
+	return w.Code
+}
+
+func TestNoClients(t *testing.T) {
+	s := NewServer()
+	var body errorBody
+	if code := request(t, s, http.MethodGet, Prefix+"/status", "", "", &body); code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+	if code := request(t, s, http.MethodPost, Prefix+"/profiles", "", "", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("POST profiles = %d, want %d", code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestPoints(t *testing.T) {
+	_, client := connectStation(t)
+	s := NewServer()
+	s.Add(client)
+
+	var profiles []Profile
+	if code := request(t, s, http.MethodGet, Prefix+"/profiles", "", "", &profiles); code != http.StatusOK {
+		t.Fatalf("profiles = %d", code)
+	}
+	if len(profiles) != 1 || profiles[0].Name != "test" || !profiles[0].Connected {
+		t.Errorf("profiles = %+v", profiles)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		code   int
+		points int
+	}{
+		{"all", "/points", http.StatusOK, 2},
+		{"type", "/points?type=telemetry", http.StatusOK, 1},
+		{"types", "/points?type=telemetry,teleindication", http.StatusOK, 2},
+		{"range", "/points?min_ioa=2", http.StatusOK, 1},
+		{"invalid type", "/points?type=foo", http.StatusBadRequest, -1},
+		{"invalid range", "/points?min_ioa=x", http.StatusBadRequest, -1},
+		{"unknown profile", "/points?profile=other", http.StatusNotFound, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var records []iec_client.Record
+			var v interface{} = &records
+			if tt.points < 0 {
+				v = &errorBody{}
+			}
+			if code := request(t, s, http.MethodGet, Prefix+tt.target, "", "", v); code != tt.code {
+				t.Fatalf("status = %d, want %d", code, tt.code)
+			}
+			if tt.points >= 0 && len(records) != tt.points {
+				t.Errorf("got %d points, want %d: %+v", len(records), tt.points, records)
+			}
+		})
+	}
+
+	var record iec_client.Record
+	if code := request(t, s, http.MethodGet, Prefix+"/points/telemetry/16385", "", "", &record); code != http.StatusOK {
+		t.Fatalf("point = %d", code)
+	}
+	if record.Value != 12.5 {
+		t.Errorf("telemetry 16385 = %v, want 12.5", record.Value)
+	}
+	if code := request(t, s, http.MethodGet, Prefix+"/points/telemetry/999", "", "", &errorBody{}); code != http.StatusNotFound {
+		t.Errorf("missing point = %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	_, client := connectStation(t)
+	s := NewServer()
+	s.CommandTimeout = 5 * time.Second
+	s.Add(client)
+
+	tests := []struct {
+		name    string
+		body    string
+		code    int
+		outcome iec_client.CommandOutcome
+	}{
+		{"confirmed", `{"kind":"sc","ioa":24577,"value":"off"}`, http.StatusOK, iec_client.CommandConfirmed},
+		{"offset", `{"kind":"sc","offset":0,"value":true,"select":false}`, http.StatusOK, iec_client.CommandConfirmed},
+		{"rejected", `{"kind":"sc","ioa":24578,"value":"on"}`, http.StatusBadGateway, iec_client.CommandNegative},
+		{"unknown point", `{"kind":"sc","ioa":24999,"value":"on"}`, http.StatusBadGateway, iec_client.CommandNegative},
+		{"invalid kind", `{"kind":"xx","ioa":24577,"value":"on"}`, http.StatusBadRequest, ""},
+		{"no address", `{"kind":"sc","value":"on"}`, http.StatusBadRequest, ""},
+		{"unknown field", `{"kind":"sc","ioa":24577,"value":"on","foo":1}`, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result iec_client.CommandResult
+			if code := request(t, s, http.MethodPost, Prefix+"/commands", "", tt.body, &result); code != tt.code {
+				t.Fatalf("status = %d, want %d: %+v", code, tt.code, result)
+			}
+			if result.Outcome != tt.outcome {
+				t.Errorf("outcome = %q, want %q", result.Outcome, tt.outcome)
+			}
+		})
+	}
+
+	var result iec_client.CommandResult
+	if code := request(t, s, http.MethodPost, Prefix+"/interrogation", "", "", &result); code != http.StatusOK {
+		t.Errorf("interrogation = %d, want %d: %+v", code, http.StatusOK, result)
+	}
+	if code := request(t, s, http.MethodPost, Prefix+"/interrogation", "", `{"group":17}`, &result); code != http.StatusBadRequest {
+		t.Errorf("interrogation of group 17 = %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestCrossSiteRequests(t *testing.T) {
+	sim, client := connectStation(t)
+	s := NewServer()
+	s.Add(client)
+
+	command := `{"kind":"sc","ioa":24577,"value":"off"}`
+	tests := []struct {
+		name        string
+		origin      string
+		contentType string
+		code        int
+	}{
+		{"foreign origin", "http://evil.example.com", "application/json", http.StatusForbidden},
+		{"text body", "", "text/plain", http.StatusUnsupportedMediaType},
+		{"form body", "", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"no content type", "", "", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, Prefix+"/commands", strings.NewReader(command))
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+			if w.Code != tt.code {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.code, w.Body)
+			}
+		})
+	}
+	if v, _, _ := sim.Value(config.TeleindBaseAddress); v != 1 {
+		t.Errorf("breaker = %v after refused commands, want 1", v)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, Prefix+"/commands", strings.NewReader(command))
+	r.Header.Set("Origin", "http://example.com")
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("same origin command = %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestToken(t *testing.T) {
+	_, client := connectStation(t)
+	s := NewServer()
+	s.Token = "secret"
+	s.Add(client)
+
+	command := `{"kind":"sc","ioa":24577,"value":"on"}`
+	tests := []struct {
+		name   string
+		method string
+		target string
+		token  string
+		code   int
+	}{
+		{"command without token", http.MethodPost, "/commands", "", http.StatusUnauthorized},
+		{"command with wrong token", http.MethodPost, "/commands", "wrong", http.StatusUnauthorized},
+		{"command with token", http.MethodPost, "/commands", "secret", http.StatusOK},
+		{"interrogation without token", http.MethodPost, "/interrogation", "", http.StatusUnauthorized},
+		{"interrogation with token", http.MethodPost, "/interrogation", "secret", http.StatusOK},
+		{"reading without token", http.MethodGet, "/points", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := ""
+			if tt.target == "/commands" {
+				body = command
+			}
+			r := httptest.NewRequest(tt.method, Prefix+tt.target, strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+			if w.Code != tt.code {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.code, w.Body.String())
+			}
+			if tt.code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without WWW-Authenticate")
+			}
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    iec_client.Filter
+		wantErr bool
+	}{
+		{"", iec_client.Filter{}, false},
+		{"type=telemetry,Teleindication", iec_client.Filter{Types: []iec_client.DataType{iec_client.Telemetry, iec_client.Teleindication}}, false},
+		{"min_ioa=16385&max_ioa=16400", iec_client.Filter{MinAddress: 16385, MaxAddress: 16400}, false},
+		{"ca=2", iec_client.Filter{CommonAddrs: []int{2}}, false},
+		{"ca=1,2", iec_client.Filter{CommonAddrs: []int{1, 2}}, false},
+		{"ca=x", iec_client.Filter{}, true},
+		{"type=foo", iec_client.Filter{}, true},
+		{"max_ioa=x", iec_client.Filter{}, true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/points?"+tt.query, nil)
+		got, err := ParseFilter(r.URL.Query().Get)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFilter(%q) error = %v, want error %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFilter(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"10m", now.Add(-10 * time.Minute), false},
+		{"2024-05-01T11:00:00Z", now.Add(-time.Hour), false},
+		{"yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.in, now)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
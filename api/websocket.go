@@ -0,0 +1,312 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"iec104/iec_client"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrorNotSubscribe = fmt.Errorf(`expected a message of type "subscribe"`)
+)
+
+// WebSocketPath is where the live update stream is served
+const WebSocketPath = Prefix + "/ws"
+
+const (
+	wsWriteTimeout = 10 * time.Second
+	wsPongTimeout  = 60 * time.Second
+	wsPingInterval = 30 * time.Second
+)
+
+// Message is pushed to WebSocket clients. Type is point, connection,
+// event, command, subscribed or error.
+type Message struct {
+	Type    string      `json:"type"`
+	Profile string      `json:"profile"`
+	Time    time.Time   `json:"time"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Subscription is sent by WebSocket clients to change the points and
+// events they receive; zero values match everything. Snapshot asks for the
+// current values of the matching points.
+type Subscription struct {
+	Type        string   `json:"type"`
+	Types       []string `json:"types,omitempty"`
+	MinAddress  int      `json:"min_ioa,omitempty"`
+	MaxAddress  int      `json:"max_ioa,omitempty"`
+	CommonAddrs []int    `json:"ca,omitempty"`
+	Snapshot    bool     `json:"snapshot,omitempty"`
+}
+
+// newSubscription describes a point filter
+func newSubscription(f iec_client.Filter) Subscription {
+	s := Subscription{
+		Type:        "subscribe",
+		MinAddress:  f.MinAddress,
+		MaxAddress:  f.MaxAddress,
+		CommonAddrs: f.CommonAddrs,
+	}
+	for _, t := range f.Types {
+		s.Types = append(s.Types, strings.ToLower(t.String()))
+	}
+	return s
+}
+
+// filter converts the subscription into a point filter
+func (s Subscription) filter() (iec_client.Filter, error) {
+	if s.Type != "subscribe" {
+		return iec_client.Filter{}, ErrorNotSubscribe
+	}
+	f := iec_client.Filter{
+		MinAddress:  s.MinAddress,
+		MaxAddress:  s.MaxAddress,
+		CommonAddrs: s.CommonAddrs,
+	}
+	for _, name := range s.Types {
+		t, err := iec_client.ParseDataType(name)
+		if err != nil {
+			return f, err
+		}
+		f.Types = append(f.Types, t)
+	}
+	return f, nil
+}
+
+// ConnectionState is the data of connection messages
+type ConnectionState struct {
+	Connected bool `json:"connected"`
+}
+
+// EventRecord is the data of event messages
+type EventRecord struct {
+	Kind    string            `json:"kind"`
+	Message string            `json:"message"`
+	Point   iec_client.Record `json:"point"`
+}
+
+// CommandRecord is the data of command messages, one per command sent by
+// the client, whoever asked for it
+type CommandRecord struct {
+	Command string                    `json:"command"`
+	Address int                       `json:"ioa,omitempty"`
+	Outcome iec_client.CommandOutcome `json:"outcome"`
+	Error   string                    `json:"error,omitempty"`
+	Elapsed float64                   `json:"elapsed_ms"`
+}
+
+// ParseOrigins reads a comma separated list of allowed origins
+func ParseOrigins(s string) []string {
+	var origins []string
+	for _, origin := range splitList(s) {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// checkOrigin lets browsers open the stream and send interrogations and
+// commands from pages of the API's own host and of the allowed origins;
+// clients that send no Origin are not browsers and are let through
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range s.Origins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleWebSocket streams point updates, connection changes, events and
+// command outcomes. The query takes the filter parameters of /points and
+// snapshot=false to skip the initial values.
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	c, err := s.client(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	filter, err := ParseFilter(r.URL.Query().Get)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	upgrader := websocket.Upgrader{CheckOrigin: s.checkOrigin}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	st := &stream{
+		conn:         conn,
+		client:       c,
+		profile:      c.Profile().Name,
+		filter:       filter,
+		subscription: newSubscription(filter),
+	}
+	st.run(r.URL.Query().Get("snapshot") != "false")
+}
+
+// stream is one WebSocket connection. All writes happen in run.
+type stream struct {
+	conn    *websocket.Conn
+	client  *iec_client.IEC104Client
+	profile string
+	filter  iec_client.Filter
+	// subscription is the filter as the client sees it
+	subscription Subscription
+}
+
+func (st *stream) run(snapshot bool) {
+	sub := st.client.Subscribe(iec_client.SubscribeOptions{
+		Buffer:  1024,
+		Policy:  iec_client.DropOldest,
+		Notices: true,
+	})
+	defer sub.Close()
+
+	requests := make(chan Subscription)
+	done := make(chan struct{})
+	go st.read(requests, done)
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	err := st.subscribed(snapshot)
+	for err == nil {
+		select {
+		case u, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if st.filter.Match(u) {
+				err = st.write("point", u.Received, iec_client.NewRecord(st.client.Profile(), u))
+			}
+		case n, ok := <-sub.N:
+			if !ok {
+				return
+			}
+			err = st.notice(n)
+		case req := <-requests:
+			f, ferr := req.filter()
+			if ferr != nil {
+				err = st.write("error", time.Now(), errorBody{ferr.Error()})
+				continue
+			}
+			st.filter = f
+			st.subscription = newSubscription(f)
+			err = st.subscribed(req.Snapshot)
+		case <-ping.C:
+			err = st.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+		case <-done:
+			return
+		}
+	}
+}
+
+// read handles the messages of the client until the connection closes
+func (st *stream) read(requests chan<- Subscription, done chan<- struct{}) {
+	defer close(done)
+
+	st.conn.SetReadLimit(maxBodySize)
+	_ = st.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	st.conn.SetPongHandler(func(string) error {
+		return st.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+	for {
+		_, data, err := st.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req Subscription
+		if err := json.Unmarshal(data, &req); err != nil || req.Type != "subscribe" {
+			req = Subscription{Type: "invalid"}
+		}
+		select {
+		case requests <- req:
+		case <-time.After(wsWriteTimeout):
+			return
+		}
+	}
+}
+
+// subscribed confirms the filter and sends the matching points if asked to
+func (st *stream) subscribed(snapshot bool) error {
+	if err := st.write("subscribed", time.Now(), st.subscription); err != nil {
+		return err
+	}
+	if !snapshot {
+		return nil
+	}
+	for _, u := range st.client.Snapshot() {
+		if st.filter.Match(u) {
+			if err := st.write("point", u.Received, iec_client.NewRecord(st.client.Profile(), u)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// notice writes a connection change, event or command outcome. Events of
+// points outside the filter are skipped.
+func (st *stream) notice(n iec_client.Notice) error {
+	switch n.Kind {
+	case iec_client.NoticeConnection:
+		return st.write("connection", n.Time, ConnectionState{n.Connected})
+	case iec_client.NoticeEvent:
+		if !st.filter.Match(n.Event.Update) {
+			return nil
+		}
+		return st.write("event", n.Time, EventRecord{
+			Kind:    n.Event.Kind.String(),
+			Message: n.Event.Message,
+			Point:   iec_client.NewRecord(st.client.Profile(), n.Event.Update),
+		})
+	case iec_client.NoticeCommand:
+		cmd := n.Command
+		return st.write("command", n.Time, CommandRecord{
+			Command: strings.TrimSuffix(strings.TrimPrefix(cmd.Type.String(), "TID<"), ">"),
+			Address: cmd.Address,
+			Outcome: cmd.Outcome,
+			Error:   cmd.Error,
+			Elapsed: iec_client.Milliseconds(cmd.Elapsed),
+		})
+	}
+	return nil
+}
+
+func (st *stream) write(typ string, t time.Time, data interface{}) error {
+	_ = st.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	w, err := st.conn.NextWriter(websocket.TextMessage)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(Message{Type: typ, Profile: st.profile, Time: t, Data: data}); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"iec104/config"
+	"iec104/iec_client"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		want    bool
+	}{
+		{"no origin", nil, "", true},
+		{"same host", nil, "http://api.example.com:8104", true},
+		{"same host other case", nil, "http://API.example.com:8104", true},
+		{"other host", nil, "http://evil.example.com", false},
+		{"other port", nil, "http://api.example.com:9000", false},
+		{"allowed", []string{"https://hmi.example.com"}, "https://hmi.example.com", true},
+		{"allowed with slash", []string{"https://hmi.example.com/"}, "https://hmi.example.com", true},
+		{"allowed other scheme", []string{"https://hmi.example.com"}, "http://hmi.example.com", false},
+		{"any", []string{"*"}, "http://evil.example.com", true},
+		{"invalid", nil, "://", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer()
+			s.Origins = tt.origins
+			r := httptest.NewRequest(http.MethodGet, "http://api.example.com:8104"+WebSocketPath, nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := s.checkOrigin(r); got != tt.want {
+				t.Errorf("checkOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOrigins(t *testing.T) {
+	got := ParseOrigins(" https://a.example.com, ,https://b.example.com ")
+	if len(got) != 2 || got[0] != "https://a.example.com" || got[1] != "https://b.example.com" {
+		t.Errorf("ParseOrigins = %q", got)
+	}
+	if got := ParseOrigins(""); got != nil {
+		t.Errorf("ParseOrigins(\"\") = %q, want nil", got)
+	}
+}
+
+func TestWebSocket(t *testing.T) {
+	sim, client := connectStation(t)
+	s := NewServer()
+	s.Add(client)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + WebSocketPath + "?type=teleindication"
+	header := http.Header{"Origin": {"http://evil.example.com"}}
+	if _, resp, err := websocket.DefaultDialer.Dial(url, header); err == nil {
+		t.Fatal("foreign origin was accepted")
+	} else if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("foreign origin: %v", err)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {srv.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	read := func(typ string) Message {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			var msg Message
+			if err := conn.ReadJSON(&msg); err != nil {
+				t.Fatalf("waiting for %s: %v", typ, err)
+			}
+			if msg.Type == typ {
+				return msg
+			}
+		}
+	}
+	record := func(msg Message) iec_client.Record {
+		t.Helper()
+		var r iec_client.Record
+		data, _ := json.Marshal(msg.Data)
+		if err := json.Unmarshal(data, &r); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	read("subscribed")
+	if r := record(read("point")); r.Address != config.TeleindBaseAddress || r.Value != 1 {
+		t.Errorf("snapshot = %+v, want teleindication 1 on", r)
+	}
+
+	if err := sim.Set(config.TelemetryBaseAddress, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.Set(config.TeleindBaseAddress, 0); err != nil {
+		t.Fatal(err)
+	}
+	if r := record(read("point")); r.Address != config.TeleindBaseAddress || r.Value != 0 {
+		t.Errorf("update = %+v, want teleindication 1 off; telemetry is filtered", r)
+	}
+
+	if err := conn.WriteJSON(map[string]interface{}{"type": "nonsense"}); err != nil {
+		t.Fatal(err)
+	}
+	read("error")
+}
@@ -0,0 +1,465 @@
+package capture
+
+import (
+	"context"
+	"iec104/config"
+	"iec104/iec_client"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// Default protocol timers, used to judge the timing of a conversation
+const (
+	DefaultT1 = 15 * time.Second
+	DefaultT3 = 20 * time.Second
+)
+
+// seqModulo is the range of the 15 bit send and receive sequence numbers
+const seqModulo = 1 << 15
+
+// Report summarizes one conversation of a capture. Directions are from the
+// client's point of view, durations are in nanoseconds in JSON.
+type Report struct {
+	Client    string    `json:"client,omitempty"`
+	Server    string    `json:"server,omitempty"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Frames    int       `json:"frames"`
+	IFrames   int       `json:"i_frames"`
+	SFrames   int       `json:"s_frames"`
+	UFrames   int       `json:"u_frames"`
+	Malformed int       `json:"malformed"`
+	LostBytes int       `json:"lost_bytes"`
+
+	Types    []TypeCount    `json:"types"`
+	Gaps     []SequenceGap  `json:"sequence_gaps"`
+	Timing   Timing         `json:"timing"`
+	Commands []CommandPair  `json:"commands"`
+	Points   []PointSummary `json:"points"`
+}
+
+// TypeCount is the number of ASDUs of a type sent in one direction
+type TypeCount struct {
+	Direction string      `json:"dir"`
+	Type      asdu.TypeID `json:"-"`
+	Name      string      `json:"type"`
+	Count     int         `json:"count"`
+}
+
+// SequenceGap is an I-frame whose send sequence number was not the expected one
+type SequenceGap struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"dir"`
+	Expected  int       `json:"expected"`
+	Received  int       `json:"received"`
+}
+
+// Timing holds acknowledgement and idle times measured against t1 and t3
+type Timing struct {
+	T1 time.Duration `json:"t1"`
+	T3 time.Duration `json:"t3"`
+	// Ack is the time the I-frames of each direction waited for acknowledgement
+	Ack []AckTiming `json:"ack"`
+	// TestFrames counts TESTFR act frames, TestMax is the longest wait for
+	// their confirmation and TestUnanswered those never confirmed
+	TestFrames     int           `json:"test_frames"`
+	TestMax        time.Duration `json:"test_max"`
+	TestUnanswered int           `json:"test_unanswered"`
+	// UnconfirmedU counts STARTDT and STOPDT act frames without confirmation
+	UnconfirmedU int `json:"unconfirmed_u"`
+	// IdleMax is the longest time without any frame; IdleOverT3 counts idle
+	// periods longer than t3 that did not end with a test frame
+	IdleMax    time.Duration `json:"idle_max"`
+	IdleOverT3 int           `json:"idle_over_t3"`
+}
+
+// AckTiming is the acknowledgement delay of the I-frames sent in one direction
+type AckTiming struct {
+	Direction string        `json:"dir"`
+	Frames    int           `json:"frames"`
+	Average   time.Duration `json:"average"`
+	Max       time.Duration `json:"max"`
+	// OverT1 counts frames acknowledged after t1, Unacknowledged those
+	// still outstanding at the end of the capture
+	OverT1         int `json:"over_t1"`
+	Unacknowledged int `json:"unacknowledged"`
+}
+
+// CommandPair is a command sent by the client with the server's answers
+type CommandPair struct {
+	Time       time.Time `json:"time"`
+	Type       string    `json:"type"`
+	Cause      string    `json:"cause"`
+	CommonAddr int       `json:"ca"`
+	Address    int       `json:"ioa"`
+	Value      string    `json:"value"`
+	Select     bool      `json:"select,omitempty"`
+	// Confirmation is the answering cause, empty if the server never answered
+	Confirmation string        `json:"confirmation"`
+	Negative     bool          `json:"negative,omitempty"`
+	Delay        time.Duration `json:"delay,omitempty"`
+	// Terminated is the delay until the activation termination, if any
+	Terminated time.Duration `json:"terminated,omitempty"`
+}
+
+// PointSummary is the final value of a monitored point
+type PointSummary struct {
+	Type       string  `json:"type"`
+	CommonAddr int     `json:"ca"`
+	Address    int     `json:"ioa"`
+	Name       string  `json:"name,omitempty"`
+	Value      float64 `json:"value"`
+	// Text is the value formatted with the point's unit or state labels
+	Text      string     `json:"text"`
+	Quality   string     `json:"quality"`
+	Cause     string     `json:"cause"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	// Updated is the capture time of the last update, Updates their number
+	Updated time.Time `json:"updated"`
+	Updates int       `json:"updates"`
+}
+
+// Analyze reports the traffic of a conversation. The points' final values
+// are decoded by replaying the received ASDUs through an IEC104Client with
+// the profile, once for every common address in the capture.
+func Analyze(c *Conversation, profile *config.Profile, t1, t3 time.Duration) *Report {
+	r := &Report{
+		Frames:    len(c.Frames),
+		LostBytes: c.Lost,
+		Timing:    Timing{T1: t1, T3: t3},
+	}
+	// JSON lines captures do not record the addresses
+	if c.Client.IsValid() {
+		r.Client, r.Server = c.Client.String(), c.Server.String()
+	}
+	if len(c.Frames) > 0 {
+		r.Start, r.End = c.Frames[0].Time, c.Frames[len(c.Frames)-1].Time
+	}
+
+	r.countFrames(c.Frames)
+	r.checkSequence(c.Frames)
+	r.measureTiming(c.Frames)
+	r.pairCommands(c.Frames)
+	r.finalValues(c.Frames, profile)
+	return r
+}
+
+// countFrames counts the frame formats and builds the type histogram
+func (r *Report) countFrames(frames []iec_client.Frame) {
+	type key struct {
+		dir iec_client.FrameDirection
+		typ asdu.TypeID
+	}
+	counts := make(map[key]int)
+	for _, f := range frames {
+		if f.Error != "" {
+			r.Malformed++
+		}
+		if !validAPCI(f) {
+			continue
+		}
+		switch f.Format {
+		case iec_client.FrameI:
+			r.IFrames++
+			if f.Error == "" {
+				counts[key{f.Direction, f.Identifier.Type}]++
+			}
+		case iec_client.FrameS:
+			r.SFrames++
+		case iec_client.FrameU:
+			r.UFrames++
+		}
+	}
+
+	for k, n := range counts {
+		r.Types = append(r.Types, TypeCount{Direction: k.dir.String(), Type: k.typ, Name: k.typ.String(), Count: n})
+	}
+	sort.Slice(r.Types, func(i, j int) bool {
+		if r.Types[i].Direction != r.Types[j].Direction {
+			return r.Types[i].Direction > r.Types[j].Direction
+		}
+		return r.Types[i].Type < r.Types[j].Type
+	})
+}
+
+// checkSequence finds I-frames whose N(S) skips or repeats a number
+func (r *Report) checkSequence(frames []iec_client.Frame) {
+	expected := make(map[iec_client.FrameDirection]int)
+	for _, f := range frames {
+		if f.Format != iec_client.FrameI || !validAPCI(f) {
+			continue
+		}
+		if want, ok := expected[f.Direction]; ok && int(f.SendSeq) != want {
+			r.Gaps = append(r.Gaps, SequenceGap{Time: f.Time, Direction: f.Direction.String(), Expected: want, Received: int(f.SendSeq)})
+		}
+		expected[f.Direction] = (int(f.SendSeq) + 1) % seqModulo
+	}
+}
+
+// measureTiming measures acknowledgement, test frame and idle times
+func (r *Report) measureTiming(frames []iec_client.Frame) {
+	type sent struct {
+		seq  int
+		time time.Time
+	}
+	type ackStats struct {
+		AckTiming
+		total   time.Duration
+		pending []sent
+	}
+	acks := map[iec_client.FrameDirection]*ackStats{
+		iec_client.FrameSent:     {AckTiming: AckTiming{Direction: iec_client.FrameSent.String()}},
+		iec_client.FrameReceived: {AckTiming: AckTiming{Direction: iec_client.FrameReceived.String()}},
+	}
+	// act holds the time of unconfirmed U-frame activations by direction and function
+	type uKey struct {
+		dir iec_client.FrameDirection
+		fn  string
+	}
+	act := make(map[uKey]time.Time)
+	other := func(d iec_client.FrameDirection) iec_client.FrameDirection {
+		if d == iec_client.FrameSent {
+			return iec_client.FrameReceived
+		}
+		return iec_client.FrameSent
+	}
+
+	var prev time.Time
+	for _, f := range frames {
+		if !validAPCI(f) {
+			continue
+		}
+		if !prev.IsZero() {
+			idle := f.Time.Sub(prev)
+			if idle > r.Timing.IdleMax {
+				r.Timing.IdleMax = idle
+			}
+			if idle > r.Timing.T3 && !(f.Format == iec_client.FrameU && f.Function == "TESTFR act") {
+				r.Timing.IdleOverT3++
+			}
+		}
+		prev = f.Time
+
+		switch f.Format {
+		case iec_client.FrameI:
+			s := acks[f.Direction]
+			s.Frames++
+			s.pending = append(s.pending, sent{seq: int(f.SendSeq), time: f.Time})
+		case iec_client.FrameU:
+			switch f.Function {
+			case "STARTDT act", "STOPDT act", "TESTFR act":
+				k := uKey{f.Direction, strings.TrimSuffix(f.Function, " act")}
+				if k.fn == "TESTFR" {
+					r.Timing.TestFrames++
+					if _, ok := act[k]; ok {
+						r.Timing.TestUnanswered++
+					}
+				}
+				act[k] = f.Time
+			case "STARTDT con", "STOPDT con", "TESTFR con":
+				k := uKey{other(f.Direction), strings.TrimSuffix(f.Function, " con")}
+				if t, ok := act[k]; ok {
+					delete(act, k)
+					if k.fn == "TESTFR" && f.Time.Sub(t) > r.Timing.TestMax {
+						r.Timing.TestMax = f.Time.Sub(t)
+					}
+				}
+			}
+			continue
+		}
+
+		// I and S frames acknowledge the other side's I-frames below N(R)
+		s := acks[other(f.Direction)]
+		for len(s.pending) > 0 {
+			outstanding := (int(f.RecvSeq) - s.pending[0].seq + seqModulo) % seqModulo
+			if outstanding == 0 || outstanding > seqModulo/2 {
+				break
+			}
+			delay := f.Time.Sub(s.pending[0].time)
+			s.total += delay
+			if delay > s.Max {
+				s.Max = delay
+			}
+			if delay > r.Timing.T1 {
+				s.OverT1++
+			}
+			s.pending = s.pending[1:]
+		}
+	}
+
+	for k := range act {
+		if k.fn == "TESTFR" {
+			r.Timing.TestUnanswered++
+		} else {
+			r.Timing.UnconfirmedU++
+		}
+	}
+	for _, d := range []iec_client.FrameDirection{iec_client.FrameSent, iec_client.FrameReceived} {
+		s := acks[d]
+		s.Unacknowledged = len(s.pending)
+		if acked := s.Frames - s.Unacknowledged; acked > 0 {
+			s.Average = s.total / time.Duration(acked)
+		}
+		r.Timing.Ack = append(r.Timing.Ack, s.AckTiming)
+	}
+}
+
+// validAPCI reports whether a frame has a well-formed APCI, so that its
+// format and sequence numbers are known
+func validAPCI(f iec_client.Frame) bool {
+	return len(f.Raw) >= 6 && f.Raw[0] == 0x68 && int(f.Raw[1])+2 == len(f.Raw)
+}
+
+// isControlType reports whether the type is sent in the control direction
+func isControlType(t asdu.TypeID) bool {
+	return (t >= asdu.C_SC_NA_1 && t <= asdu.C_BO_NA_1) || (t >= asdu.C_SC_TA_1 && t <= asdu.C_BO_TA_1) ||
+		(t >= asdu.C_IC_NA_1 && t <= asdu.C_TS_TA_1)
+}
+
+// pairCommands matches the client's commands with the server's confirmations
+// and terminations of the same type, common address and address
+func (r *Report) pairCommands(frames []iec_client.Frame) {
+	type key struct {
+		typ asdu.TypeID
+		ca  asdu.CommonAddr
+		ioa int
+	}
+	open := make(map[key]int)
+	for _, f := range frames {
+		if f.Format != iec_client.FrameI || f.Error != "" || !isControlType(f.Identifier.Type) {
+			continue
+		}
+		var obj iec_client.InfoObject
+		if len(f.Objects) > 0 {
+			obj = f.Objects[0]
+		}
+		k := key{f.Identifier.Type, f.Identifier.CommonAddr, int(obj.Address)}
+		cause := f.Identifier.Coa.Cause
+
+		if f.Direction == iec_client.FrameSent {
+			if cause != asdu.Activation && cause != asdu.Deactivation {
+				continue
+			}
+			open[k] = len(r.Commands)
+			r.Commands = append(r.Commands, CommandPair{
+				Time:       f.Time,
+				Type:       f.Identifier.Type.String(),
+				Cause:      iec_client.CauseString(cause),
+				CommonAddr: int(f.Identifier.CommonAddr),
+				Address:    int(obj.Address),
+				Value:      obj.Text,
+				Select:     f.Identifier.Coa.Cause == asdu.Activation && isSelect(f),
+			})
+			continue
+		}
+
+		i, ok := open[k]
+		if !ok {
+			continue
+		}
+		cmd := &r.Commands[i]
+		switch cause {
+		case asdu.ActivationTerm:
+			cmd.Terminated = f.Time.Sub(cmd.Time)
+			delete(open, k)
+		case asdu.ActivationCon, asdu.DeactivationCon, asdu.UnknownTypeID, asdu.UnknownCOT, asdu.UnknownCA, asdu.UnknownIOA:
+			if cmd.Confirmation != "" {
+				continue
+			}
+			cmd.Confirmation = iec_client.CauseString(cause)
+			cmd.Negative = f.Identifier.Coa.IsNegative || cause >= asdu.UnknownTypeID
+			cmd.Delay = f.Time.Sub(cmd.Time)
+			if cmd.Negative || cmd.Select || cause == asdu.DeactivationCon {
+				delete(open, k)
+			}
+		}
+	}
+}
+
+// isSelect reports whether a command frame is the select of a select-execute
+func isSelect(f iec_client.Frame) bool {
+	for _, o := range f.Objects {
+		if strings.HasSuffix(o.Text, " select") {
+			return true
+		}
+	}
+	return false
+}
+
+// finalValues replays the received frames to get the last value of every point
+func (r *Report) finalValues(frames []iec_client.Frame, profile *config.Profile) {
+	type key struct {
+		ca  int
+		ioa int
+	}
+	type seen struct {
+		time  time.Time
+		count int
+	}
+	updates := make(map[key]seen)
+	addrs := make(map[int]bool)
+	for _, f := range frames {
+		if f.Direction != iec_client.FrameReceived || f.Format != iec_client.FrameI || f.Error != "" || isControlType(f.Identifier.Type) {
+			continue
+		}
+		ca := int(f.Identifier.CommonAddr)
+		addrs[ca] = true
+		for _, o := range f.Objects {
+			k := key{ca, o.Address}
+			updates[k] = seen{time: f.Time, count: updates[k].count + 1}
+		}
+	}
+	cas := make([]int, 0, len(addrs))
+	for ca := range addrs {
+		cas = append(cas, ca)
+	}
+	sort.Ints(cas)
+
+	for _, ca := range cas {
+		p := *profile
+		p.CommonAddress = ca
+		client := iec_client.NewIEC104Client(&p)
+		client.Logger = nopLogger{}
+		_ = client.Replay(context.Background(), frames, 0)
+		for _, u := range client.Snapshot() {
+			point := p.FindPoint(u.Type.PointType(), u.Address)
+			text := point.FormatValue(u.Value)
+			switch {
+			case u.Double:
+				text, _ = point.DoubleStateLabel(int(u.DoubleState))
+			case u.Type == iec_client.Teleindication:
+				text, _ = point.StateLabel(u.State)
+			}
+			s := PointSummary{
+				Type:       u.Type.String(),
+				CommonAddr: ca,
+				Address:    u.Address,
+				Value:      u.Value,
+				Text:       text,
+				Quality:    iec_client.QualityString(u.Quality),
+				Cause:      iec_client.CauseString(u.Cause),
+				Updated:    updates[key{ca, u.Address}].time,
+				Updates:    updates[key{ca, u.Address}].count,
+			}
+			if point != nil {
+				s.Name = point.Name
+			}
+			if !u.Timestamp.IsZero() {
+				t := u.Timestamp
+				s.Timestamp = &t
+			}
+			r.Points = append(r.Points, s)
+		}
+		client.Close()
+	}
+}
+
+// nopLogger discards the replay client's log
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
@@ -0,0 +1,189 @@
+// Package capture writes the frames of an IEC 104 connection to capture
+// files and reads them back from our own captures and from Wireshark.
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"iec104/iec_client"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// DefaultPort is the IEC 104 TCP port used to tell the server from the client
+const DefaultPort = 2404
+
+var (
+	ErrorUnknownFormat = fmt.Errorf("unknown capture format, use .pcap, .pcapng or .jsonl")
+	ErrorNoFrames      = fmt.Errorf("no IEC 104 frames in capture")
+)
+
+// Writer writes frames to a capture file
+type Writer interface {
+	WriteFrame(f iec_client.Frame) error
+	Close() error
+}
+
+// Conversation is the traffic of one TCP connection, from the client's
+// point of view: frames sent by the client are iec_client.FrameSent
+type Conversation struct {
+	Client netip.AddrPort
+	Server netip.AddrPort
+	Frames []iec_client.Frame
+	// Lost is the number of payload bytes missing from the capture
+	Lost int
+}
+
+// Create creates a capture file whose format is chosen by the extension:
+// .pcap and .pcapng with synthesized Ethernet, IPv4 and TCP headers between
+// a made-up client address and server, or .jsonl with one frame per line
+func Create(path string, server netip.AddrPort) (Writer, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".pcap" && ext != ".pcapng" && ext != ".jsonl" {
+		return nil, ErrorUnknownFormat
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var w Writer
+	switch ext {
+	case ".jsonl":
+		w = &jsonWriter{f: f, enc: json.NewEncoder(f)}
+	default:
+		w, err = newPcapWriter(f, server, ext == ".pcapng")
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// ServerAddr returns the address a capture uses for the server: the
+// configured IPv4 address, or a documentation address for host names
+func ServerAddr(host string, port int) netip.AddrPort {
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !addr.Is4() {
+		addr = netip.AddrFrom4([4]byte{192, 0, 2, 2})
+	}
+	return netip.AddrPortFrom(addr, uint16(port))
+}
+
+// record is a line of the JSON lines capture format
+type record struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"dir"`
+	Raw       string    `json:"raw"`
+}
+
+// jsonWriter writes one JSON record per frame
+type jsonWriter struct {
+	f   *os.File
+	enc *json.Encoder
+}
+
+// WriteFrame appends a frame
+func (w *jsonWriter) WriteFrame(f iec_client.Frame) error {
+	return w.enc.Encode(record{Time: f.Time, Direction: f.Direction.String(), Raw: hex.EncodeToString(f.Raw)})
+}
+
+// Close closes the file
+func (w *jsonWriter) Close() error {
+	return w.f.Close()
+}
+
+// ReadFile reads the IEC 104 conversations of a pcap, pcapng or JSON
+// lines capture. In packet captures the server is the side using port.
+func ReadFile(path string, port uint16) ([]*Conversation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	conversations, err := Read(f, port)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return conversations, nil
+}
+
+// Read reads the IEC 104 conversations of a capture, see ReadFile
+func Read(r io.Reader, port uint16) ([]*Conversation, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil {
+		return nil, ErrorUnknownFormat
+	}
+
+	var conversations []*Conversation
+	if bytes.HasPrefix(bytes.TrimLeft(magic, " \t\r\n"), []byte("{")) {
+		c, err := readJSON(br)
+		if err != nil {
+			return nil, err
+		}
+		conversations = []*Conversation{c}
+	} else {
+		segments, err := readSegments(br, magic)
+		if err != nil {
+			return nil, err
+		}
+		conversations = reassemble(segments, port)
+	}
+
+	for _, c := range conversations {
+		if len(c.Frames) > 0 {
+			return conversations, nil
+		}
+	}
+	return nil, ErrorNoFrames
+}
+
+// readJSON reads the JSON lines format
+func readJSON(r io.Reader) (*Conversation, error) {
+	c := &Conversation{}
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var rec record
+		if err := dec.Decode(&rec); err == io.EOF {
+			return c, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("record %d: %w", line, err)
+		}
+		raw, err := hex.DecodeString(rec.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", line, err)
+		}
+		f, err := iec_client.ParseFrame(raw, asdu.ParamsWide)
+		if err != nil {
+			f.Error = err.Error()
+		}
+		f.Time = rec.Time
+		if rec.Direction == iec_client.FrameSent.String() {
+			f.Direction = iec_client.FrameSent
+		}
+		c.Frames = append(c.Frames, f)
+	}
+}
+
+// Frames returns the frames of all conversations in time order, e.g. to
+// replay a capture with several connections
+func Frames(conversations []*Conversation) []iec_client.Frame {
+	var frames []iec_client.Frame
+	for _, c := range conversations {
+		frames = append(frames, c.Frames...)
+	}
+	sortFrames(frames)
+	return frames
+}
@@ -0,0 +1,163 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"iec104/iec_client"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// testFrames is a link start followed by an interrogation and its confirmation
+func testFrames(t *testing.T) []iec_client.Frame {
+	t.Helper()
+	start := time.Unix(1700000000, 123456000)
+	raws := []struct {
+		dir iec_client.FrameDirection
+		raw []byte
+	}{
+		{iec_client.FrameSent, []byte{0x68, 0x04, 0x07, 0x00, 0x00, 0x00}},
+		{iec_client.FrameReceived, []byte{0x68, 0x04, 0x0b, 0x00, 0x00, 0x00}},
+		{iec_client.FrameSent, []byte{0x68, 0x0e, 0x00, 0x00, 0x00, 0x00, 0x64, 0x01, 0x06, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x14}},
+		{iec_client.FrameReceived, []byte{0x68, 0x0e, 0x00, 0x00, 0x02, 0x00, 0x64, 0x01, 0x07, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x14}},
+	}
+	var frames []iec_client.Frame
+	for i, r := range raws {
+		f, err := iec_client.ParseFrame(r.raw, asdu.ParamsWide)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Direction = r.dir
+		f.Time = start.Add(time.Duration(i) * 10 * time.Millisecond)
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+// writeCapture writes the frames to a new capture file in dir
+func writeCapture(t *testing.T, name string, frames []iec_client.Frame) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	w, err := Create(path, ServerAddr("10.0.0.5", DefaultPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range frames {
+		if err := w.WriteFrame(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRoundTrip(t *testing.T) {
+	frames := testFrames(t)
+	for _, name := range []string{"test.pcap", "test.pcapng", "test.jsonl"} {
+		t.Run(name, func(t *testing.T) {
+			conversations, err := ReadFile(writeCapture(t, name, frames), DefaultPort)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Frames(conversations)
+			if len(got) != len(frames) {
+				t.Fatalf("read %d frames, want %d", len(got), len(frames))
+			}
+			for i, f := range got {
+				want := frames[i]
+				if !bytes.Equal(f.Raw, want.Raw) || f.Direction != want.Direction || !f.Time.Equal(want.Time) {
+					t.Errorf("frame %d = %s % x at %v, want %s % x at %v", i, f.Direction, f.Raw, f.Time, want.Direction, want.Raw, want.Time)
+				}
+			}
+			if got[3].Identifier.Type != asdu.C_IC_NA_1 || got[3].Identifier.Coa.Cause != asdu.ActivationCon {
+				t.Errorf("confirmation decoded as %+v", got[3].Identifier)
+			}
+		})
+	}
+}
+
+func TestCreateUnknownFormat(t *testing.T) {
+	_, err := Create(filepath.Join(t.TempDir(), "test.txt"), ServerAddr("10.0.0.5", DefaultPort))
+	if !errors.Is(err, ErrorUnknownFormat) {
+		t.Errorf("Create .txt = %v, want %v", err, ErrorUnknownFormat)
+	}
+}
+
+func TestServerAddr(t *testing.T) {
+	if got := ServerAddr("10.0.0.5", 2404).String(); got != "10.0.0.5:2404" {
+		t.Errorf("ServerAddr of an IPv4 address = %s", got)
+	}
+	if got := ServerAddr("rtu.example.com", 2405).String(); got != "192.0.2.2:2405" {
+		t.Errorf("ServerAddr of a host name = %s", got)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	frames := testFrames(t)
+	pcap, err := os.ReadFile(writeCapture(t, "test.pcap", frames))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcapng, err := os.ReadFile(writeCapture(t, "test.pcapng", frames))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// pcap with a header claiming a capture of maxRecordSize+1 bytes
+	oversized := append([]byte(nil), pcap[:24]...)
+	binary.LittleEndian.PutUint32(oversized[16:], 0)
+	header := make([]byte, 16)
+	binary.LittleEndian.PutUint32(header[8:], maxRecordSize+1)
+	oversized = append(oversized, header...)
+
+	// pcap whose first packet exceeds the snapshot length
+	snapped := append([]byte(nil), pcap...)
+	binary.LittleEndian.PutUint32(snapped[16:], 40)
+
+	// pcapng whose first block after the section header claims to be huge
+	sectionLength := int(binary.LittleEndian.Uint32(pcapng[4:]))
+	hugeBlock := append([]byte(nil), pcapng...)
+	binary.LittleEndian.PutUint32(hugeBlock[sectionLength+4:], maxRecordSize+1)
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, ErrorUnknownFormat},
+		{"garbage", []byte("not a capture"), ErrorUnknownFormat},
+		{"pcap truncated", pcap[:len(pcap)-5], ErrorTruncated},
+		{"pcapng truncated", pcapng[:len(pcapng)-5], ErrorTruncated},
+		{"pcap oversized", oversized, ErrorRecordTooLarge},
+		{"pcap beyond snaplen", snapped, ErrorRecordTooLarge},
+		{"pcapng oversized", hugeBlock, ErrorRecordTooLarge},
+		{"pcap without frames", pcap[:24], ErrorNoFrames},
+		{"jsonl bad hex", []byte(`{"time":"2024-01-01T00:00:00Z","dir":"TX","raw":"zz"}`), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(tt.data), DefaultPort)
+			if err == nil {
+				t.Fatal("invalid capture was read")
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Read = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestReadOtherPort(t *testing.T) {
+	path := writeCapture(t, "test.pcap", testFrames(t))
+	if _, err := ReadFile(path, 2405); !errors.Is(err, ErrorNoFrames) {
+		t.Errorf("ReadFile with another server port = %v, want %v", err, ErrorNoFrames)
+	}
+}
@@ -0,0 +1,220 @@
+package capture
+
+import (
+	"encoding/binary"
+	"iec104/iec_client"
+	"net/netip"
+	"os"
+	"time"
+)
+
+// Link types and block types of the pcap and pcapng formats
+const (
+	linkNull     = 0
+	linkEthernet = 1
+	linkRaw      = 101
+	linkLinuxSLL = 113
+	linkIPv4     = 228
+	linkIPv6     = 229
+	linkSLL2     = 276
+
+	pcapMagic      = 0xa1b2c3d4
+	pcapMagicNanos = 0xa1b23c4d
+	blockSection   = 0x0a0d0d0a
+	blockInterface = 1
+	blockSimple    = 3
+	blockEnhanced  = 6
+	byteOrderMagic = 0x1a2b3c4d
+)
+
+// TCP flags
+const (
+	tcpFIN = 0x01
+	tcpSYN = 0x02
+	tcpPSH = 0x08
+	tcpACK = 0x10
+)
+
+// pcapWriter writes frames as TCP segments of a synthesized connection
+type pcapWriter struct {
+	f      *os.File
+	ng     bool
+	client netip.AddrPort
+	server netip.AddrPort
+
+	started   bool
+	clientSeq uint32
+	serverSeq uint32
+	ipID      uint16
+}
+
+func newPcapWriter(f *os.File, server netip.AddrPort, ng bool) (*pcapWriter, error) {
+	w := &pcapWriter{
+		f:         f,
+		ng:        ng,
+		client:    netip.AddrPortFrom(netip.AddrFrom4([4]byte{192, 0, 2, 1}), 50000),
+		server:    server,
+		clientSeq: 1000,
+		serverSeq: 5000,
+	}
+
+	var header []byte
+	if ng {
+		shb := make([]byte, 28)
+		binary.LittleEndian.PutUint32(shb[0:], blockSection)
+		binary.LittleEndian.PutUint32(shb[4:], 28)
+		binary.LittleEndian.PutUint32(shb[8:], byteOrderMagic)
+		binary.LittleEndian.PutUint16(shb[12:], 1)
+		binary.LittleEndian.PutUint64(shb[16:], 0xffffffffffffffff)
+		binary.LittleEndian.PutUint32(shb[24:], 28)
+		idb := make([]byte, 20)
+		binary.LittleEndian.PutUint32(idb[0:], blockInterface)
+		binary.LittleEndian.PutUint32(idb[4:], 20)
+		binary.LittleEndian.PutUint16(idb[8:], linkEthernet)
+		binary.LittleEndian.PutUint32(idb[12:], 65535)
+		binary.LittleEndian.PutUint32(idb[16:], 20)
+		header = append(shb, idb...)
+	} else {
+		header = make([]byte, 24)
+		binary.LittleEndian.PutUint32(header[0:], pcapMagic)
+		binary.LittleEndian.PutUint16(header[4:], 2)
+		binary.LittleEndian.PutUint16(header[6:], 4)
+		binary.LittleEndian.PutUint32(header[16:], 65535)
+		binary.LittleEndian.PutUint32(header[20:], linkEthernet)
+	}
+	if _, err := f.Write(header); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// WriteFrame writes a frame as a TCP segment, preceded by a handshake for
+// the first frame
+func (w *pcapWriter) WriteFrame(f iec_client.Frame) error {
+	if !w.started {
+		w.started = true
+		for _, flags := range []byte{tcpSYN, tcpSYN | tcpACK, tcpACK} {
+			toServer := flags != tcpSYN|tcpACK
+			if err := w.segment(f.Time, toServer, flags, nil); err != nil {
+				return err
+			}
+			if flags&tcpSYN != 0 && toServer {
+				w.clientSeq++
+			} else if flags&tcpSYN != 0 {
+				w.serverSeq++
+			}
+		}
+	}
+	return w.segment(f.Time, f.Direction == iec_client.FrameSent, tcpPSH|tcpACK, f.Raw)
+}
+
+// segment writes one packet and advances the sequence number
+func (w *pcapWriter) segment(t time.Time, toServer bool, flags byte, payload []byte) error {
+	src, dst, seq, ack := w.client, w.server, w.clientSeq, w.serverSeq
+	if !toServer {
+		src, dst, seq, ack = w.server, w.client, w.serverSeq, w.clientSeq
+	}
+	if flags&tcpACK == 0 {
+		ack = 0
+	}
+	w.ipID++
+	packet := buildPacket(src, dst, seq, ack, flags, w.ipID, toServer, payload)
+	if toServer {
+		w.clientSeq += uint32(len(payload))
+	} else {
+		w.serverSeq += uint32(len(payload))
+	}
+
+	var record []byte
+	if w.ng {
+		padded := (len(packet) + 3) &^ 3
+		total := 32 + padded
+		record = make([]byte, total)
+		micros := uint64(t.UnixMicro())
+		binary.LittleEndian.PutUint32(record[0:], blockEnhanced)
+		binary.LittleEndian.PutUint32(record[4:], uint32(total))
+		binary.LittleEndian.PutUint32(record[12:], uint32(micros>>32))
+		binary.LittleEndian.PutUint32(record[16:], uint32(micros))
+		binary.LittleEndian.PutUint32(record[20:], uint32(len(packet)))
+		binary.LittleEndian.PutUint32(record[24:], uint32(len(packet)))
+		copy(record[28:], packet)
+		binary.LittleEndian.PutUint32(record[total-4:], uint32(total))
+	} else {
+		record = make([]byte, 16+len(packet))
+		binary.LittleEndian.PutUint32(record[0:], uint32(t.Unix()))
+		binary.LittleEndian.PutUint32(record[4:], uint32(t.Nanosecond()/1000))
+		binary.LittleEndian.PutUint32(record[8:], uint32(len(packet)))
+		binary.LittleEndian.PutUint32(record[12:], uint32(len(packet)))
+		copy(record[16:], packet)
+	}
+	_, err := w.f.Write(record)
+	return err
+}
+
+// Close closes the file
+func (w *pcapWriter) Close() error {
+	return w.f.Close()
+}
+
+// buildPacket builds an Ethernet frame with IPv4 and TCP headers
+func buildPacket(src, dst netip.AddrPort, seq, ack uint32, flags byte, id uint16, toServer bool, payload []byte) []byte {
+	packet := make([]byte, 14+20+20+len(payload))
+
+	// Ethernet with locally administered addresses
+	eth := packet[:14]
+	clientMAC := []byte{0x02, 0, 0, 0, 0, 0x01}
+	serverMAC := []byte{0x02, 0, 0, 0, 0, 0x02}
+	if toServer {
+		copy(eth[0:], serverMAC)
+		copy(eth[6:], clientMAC)
+	} else {
+		copy(eth[0:], clientMAC)
+		copy(eth[6:], serverMAC)
+	}
+	binary.BigEndian.PutUint16(eth[12:], 0x0800)
+
+	ip := packet[14:34]
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:], uint16(20+20+len(payload)))
+	binary.BigEndian.PutUint16(ip[4:], id)
+	ip[6] = 0x40 // don't fragment
+	ip[8] = 64
+	ip[9] = 6
+	srcIP, dstIP := src.Addr().As4(), dst.Addr().As4()
+	copy(ip[12:], srcIP[:])
+	copy(ip[16:], dstIP[:])
+	binary.BigEndian.PutUint16(ip[10:], checksum(ip, 0))
+
+	tcp := packet[34:]
+	binary.BigEndian.PutUint16(tcp[0:], src.Port())
+	binary.BigEndian.PutUint16(tcp[2:], dst.Port())
+	binary.BigEndian.PutUint32(tcp[4:], seq)
+	binary.BigEndian.PutUint32(tcp[8:], ack)
+	tcp[12] = 5 << 4
+	tcp[13] = flags
+	binary.BigEndian.PutUint16(tcp[14:], 65535)
+	copy(tcp[20:], payload)
+
+	// The TCP checksum covers a pseudo header of addresses, protocol and length
+	var pseudo uint32
+	for i := 0; i < 4; i += 2 {
+		pseudo += uint32(binary.BigEndian.Uint16(srcIP[i:])) + uint32(binary.BigEndian.Uint16(dstIP[i:]))
+	}
+	pseudo += 6 + uint32(len(tcp))
+	binary.BigEndian.PutUint16(tcp[16:], checksum(tcp, pseudo))
+	return packet
+}
+
+// checksum computes the internet checksum of data plus an initial sum
+func checksum(data []byte, sum uint32) uint16 {
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(data[i:]))
+	}
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+	for sum > 0xffff {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
@@ -0,0 +1,321 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/netip"
+	"time"
+)
+
+// maxRecordSize bounds the packets and blocks read from a capture file, so
+// a corrupt length does not allocate gigabytes
+const maxRecordSize = 256 << 10
+
+var (
+	ErrorTruncated       = fmt.Errorf("capture file is truncated")
+	ErrorUnsupportedLink = fmt.Errorf("unsupported link type")
+	ErrorRecordTooLarge  = fmt.Errorf("capture record larger than the snapshot length or %d bytes", maxRecordSize)
+)
+
+// segment is a TCP segment read from a packet capture
+type segment struct {
+	time    time.Time
+	src     netip.AddrPort
+	dst     netip.AddrPort
+	seq     uint32
+	flags   byte
+	payload []byte
+}
+
+// readSegments reads the TCP segments of a pcap or pcapng file
+func readSegments(r *bufio.Reader, magic []byte) ([]segment, error) {
+	if binary.LittleEndian.Uint32(magic) == blockSection {
+		return readPcapng(r)
+	}
+	return readPcap(r)
+}
+
+// readPcap reads a classic pcap file in either byte order and time resolution
+func readPcap(r io.Reader) ([]segment, error) {
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrorUnknownFormat
+	}
+
+	var order binary.ByteOrder
+	var nanos bool
+	switch {
+	case binary.LittleEndian.Uint32(header) == pcapMagic:
+		order = binary.LittleEndian
+	case binary.BigEndian.Uint32(header) == pcapMagic:
+		order = binary.BigEndian
+	case binary.LittleEndian.Uint32(header) == pcapMagicNanos:
+		order, nanos = binary.LittleEndian, true
+	case binary.BigEndian.Uint32(header) == pcapMagicNanos:
+		order, nanos = binary.BigEndian, true
+	default:
+		return nil, ErrorUnknownFormat
+	}
+	link := int(order.Uint32(header[20:]) & 0xffff)
+	snaplen := order.Uint32(header[16:])
+	if snaplen == 0 || snaplen > maxRecordSize {
+		snaplen = maxRecordSize
+	}
+
+	var segments []segment
+	record := make([]byte, 16)
+	for {
+		if _, err := io.ReadFull(r, record); err == io.EOF {
+			return segments, nil
+		} else if err != nil {
+			return segments, ErrorTruncated
+		}
+		captured := order.Uint32(record[8:])
+		if captured > snaplen {
+			return segments, ErrorRecordTooLarge
+		}
+		data := make([]byte, captured)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return segments, ErrorTruncated
+		}
+
+		frac := time.Duration(order.Uint32(record[4:]))
+		if !nanos {
+			frac *= time.Microsecond
+		}
+		t := time.Unix(int64(order.Uint32(record[0:])), int64(frac))
+		s, ok, err := parsePacket(link, data)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			s.time = t
+			segments = append(segments, s)
+		}
+	}
+}
+
+// pcapngInterface is the link type and time resolution of an interface
+type pcapngInterface struct {
+	link int
+	unit time.Duration
+}
+
+// readPcapng reads the enhanced packet blocks of a pcapng file
+func readPcapng(r io.Reader) ([]segment, error) {
+	var (
+		segments   []segment
+		order      binary.ByteOrder = binary.LittleEndian
+		interfaces []pcapngInterface
+	)
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err == io.EOF {
+			return segments, nil
+		} else if err != nil {
+			return segments, ErrorTruncated
+		}
+
+		blockType := order.Uint32(header)
+		if blockType == blockSection {
+			// The byte order magic follows the length of a section header
+			bom := make([]byte, 4)
+			if _, err := io.ReadFull(r, bom); err != nil {
+				return segments, ErrorTruncated
+			}
+			if binary.BigEndian.Uint32(bom) == byteOrderMagic {
+				order = binary.BigEndian
+			} else {
+				order = binary.LittleEndian
+			}
+			interfaces = nil
+			length := int(order.Uint32(header[4:]))
+			if length < 16 {
+				return segments, ErrorTruncated
+			}
+			if _, err := io.CopyN(io.Discard, r, int64(length-12)); err != nil {
+				return segments, ErrorTruncated
+			}
+			continue
+		}
+
+		length := int(order.Uint32(header[4:]))
+		if length < 12 {
+			return segments, ErrorTruncated
+		}
+		if length > maxRecordSize {
+			return segments, ErrorRecordTooLarge
+		}
+		body := make([]byte, length-8)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return segments, ErrorTruncated
+		}
+		body = body[:len(body)-4]
+
+		switch blockType {
+		case blockInterface:
+			if len(body) < 8 {
+				return segments, ErrorTruncated
+			}
+			interfaces = append(interfaces, pcapngInterface{
+				link: int(order.Uint16(body)),
+				unit: interfaceResolution(body[8:], order),
+			})
+		case blockEnhanced:
+			if len(body) < 20 {
+				return segments, ErrorTruncated
+			}
+			id := int(order.Uint32(body))
+			if id >= len(interfaces) {
+				return segments, fmt.Errorf("packet of undeclared interface %d", id)
+			}
+			captured := int(order.Uint32(body[12:]))
+			if 20+captured > len(body) {
+				return segments, ErrorTruncated
+			}
+			ticks := uint64(order.Uint32(body[4:]))<<32 | uint64(order.Uint32(body[8:]))
+			iface := interfaces[id]
+			s, ok, err := parsePacket(iface.link, body[20:20+captured])
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				s.time = ticksTime(ticks, iface.unit)
+				segments = append(segments, s)
+			}
+		}
+	}
+}
+
+// interfaceResolution reads the if_tsresol option, microseconds by default
+func interfaceResolution(options []byte, order binary.ByteOrder) time.Duration {
+	for len(options) >= 4 {
+		code, length := order.Uint16(options), int(order.Uint16(options[2:]))
+		if code == 0 || 4+length > len(options) {
+			break
+		}
+		if code == 9 && length >= 1 {
+			v := options[4]
+			unit := time.Second
+			if v&0x80 != 0 {
+				return unit >> (v & 0x7f)
+			}
+			for i := byte(0); i < v; i++ {
+				unit /= 10
+			}
+			if unit == 0 {
+				unit = time.Nanosecond
+			}
+			return unit
+		}
+		options = options[4+(length+3)&^3:]
+	}
+	return time.Microsecond
+}
+
+func ticksTime(ticks uint64, unit time.Duration) time.Time {
+	perSecond := uint64(time.Second / unit)
+	return time.Unix(int64(ticks/perSecond), int64(ticks%perSecond)*int64(unit))
+}
+
+// parsePacket extracts the TCP segment of a packet, reporting false for
+// anything else
+func parsePacket(link int, data []byte) (segment, bool, error) {
+	var etherType uint16
+	switch link {
+	case linkEthernet:
+		if len(data) < 14 {
+			return segment{}, false, nil
+		}
+		etherType, data = binary.BigEndian.Uint16(data[12:]), data[14:]
+		for (etherType == 0x8100 || etherType == 0x88a8) && len(data) >= 4 {
+			etherType, data = binary.BigEndian.Uint16(data[2:]), data[4:]
+		}
+	case linkLinuxSLL:
+		if len(data) < 16 {
+			return segment{}, false, nil
+		}
+		etherType, data = binary.BigEndian.Uint16(data[14:]), data[16:]
+	case linkSLL2:
+		if len(data) < 20 {
+			return segment{}, false, nil
+		}
+		etherType, data = binary.BigEndian.Uint16(data), data[20:]
+	case linkNull:
+		if len(data) < 4 {
+			return segment{}, false, nil
+		}
+		// The address family is in host byte order: 2 is IPv4, 24, 28 or 30 IPv6
+		family := binary.LittleEndian.Uint32(data)
+		if family > 0xffff {
+			family = binary.BigEndian.Uint32(data)
+		}
+		etherType, data = 0x86dd, data[4:]
+		if family == 2 {
+			etherType = 0x0800
+		}
+	case linkRaw, linkIPv4, linkIPv6:
+		if len(data) == 0 {
+			return segment{}, false, nil
+		}
+		etherType = 0x0800
+		if data[0]>>4 == 6 {
+			etherType = 0x86dd
+		}
+	default:
+		return segment{}, false, fmt.Errorf("%w %d", ErrorUnsupportedLink, link)
+	}
+
+	var (
+		src, dst netip.Addr
+		proto    byte
+	)
+	switch etherType {
+	case 0x0800:
+		if len(data) < 20 || data[0]>>4 != 4 {
+			return segment{}, false, nil
+		}
+		headerLen := int(data[0]&0x0f) * 4
+		total := int(binary.BigEndian.Uint16(data[2:]))
+		if headerLen < 20 || total < headerLen || total > len(data) {
+			total = len(data)
+		}
+		if binary.BigEndian.Uint16(data[6:])&0x3fff != 0 {
+			// Fragments do not occur with IEC 104's small APDUs
+			return segment{}, false, nil
+		}
+		src = netip.AddrFrom4([4]byte(data[12:16]))
+		dst = netip.AddrFrom4([4]byte(data[16:20]))
+		proto, data = data[9], data[headerLen:total]
+	case 0x86dd:
+		if len(data) < 40 {
+			return segment{}, false, nil
+		}
+		payloadLen := int(binary.BigEndian.Uint16(data[4:]))
+		src = netip.AddrFrom16([16]byte(data[8:24]))
+		dst = netip.AddrFrom16([16]byte(data[24:40]))
+		proto, data = data[6], data[40:]
+		if payloadLen > 0 && payloadLen <= len(data) {
+			data = data[:payloadLen]
+		}
+	default:
+		return segment{}, false, nil
+	}
+
+	if proto != 6 || len(data) < 20 {
+		return segment{}, false, nil
+	}
+	offset := int(data[12]>>4) * 4
+	if offset < 20 || offset > len(data) {
+		return segment{}, false, nil
+	}
+	return segment{
+		src:     netip.AddrPortFrom(src, binary.BigEndian.Uint16(data[0:])),
+		dst:     netip.AddrPortFrom(dst, binary.BigEndian.Uint16(data[2:])),
+		seq:     binary.BigEndian.Uint32(data[4:]),
+		flags:   data[13],
+		payload: data[offset:],
+	}, true, nil
+}
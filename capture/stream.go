@@ -0,0 +1,190 @@
+package capture
+
+import (
+	"iec104/iec_client"
+	"net/netip"
+	"sort"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// maxPending is the number of out-of-order segments buffered before the
+// missing data is given up as lost
+const maxPending = 64
+
+// flow reassembles one direction of a TCP connection into APDUs
+type flow struct {
+	conv      *Conversation
+	direction iec_client.FrameDirection
+	synced    bool
+	next      uint32
+	buf       []byte
+	pending   map[uint32]segment
+}
+
+// add appends a segment, buffering it if it arrived early
+func (f *flow) add(s segment) {
+	if s.flags&tcpSYN != 0 {
+		f.synced, f.next, f.buf = true, s.seq+1, nil
+		f.pending = make(map[uint32]segment)
+		return
+	}
+	if len(s.payload) == 0 {
+		return
+	}
+	if !f.synced {
+		// Capture started mid-connection: take the first segment as is
+		f.synced, f.next = true, s.seq
+		f.pending = make(map[uint32]segment)
+	}
+
+	switch diff := int32(s.seq - f.next); {
+	case diff > 0:
+		f.pending[s.seq] = s
+		if len(f.pending) > maxPending {
+			f.skipGap()
+		}
+		return
+	case diff < 0:
+		// Retransmission, possibly with new data at the end
+		if -int(diff) >= len(s.payload) {
+			return
+		}
+		s.payload = s.payload[-diff:]
+	}
+	f.append(s)
+	f.drainPending()
+}
+
+// append adds in-order payload and emits the APDUs it completes
+func (f *flow) append(s segment) {
+	f.buf = append(f.buf, s.payload...)
+	f.next += uint32(len(s.payload))
+
+	for len(f.buf) >= 2 {
+		if f.buf[0] != 0x68 {
+			// Resynchronise on the next start byte
+			i := 1
+			for i < len(f.buf) && f.buf[i] != 0x68 {
+				i++
+			}
+			f.buf = f.buf[i:]
+			continue
+		}
+		n := int(f.buf[1]) + 2
+		if len(f.buf) < n {
+			break
+		}
+		f.emit(s.time, append([]byte(nil), f.buf[:n]...))
+		f.buf = f.buf[n:]
+	}
+}
+
+// drainPending appends buffered segments that have become in order
+func (f *flow) drainPending() {
+	for {
+		progressed := false
+		for seq, s := range f.pending {
+			diff := int32(seq - f.next)
+			if diff > 0 {
+				continue
+			}
+			delete(f.pending, seq)
+			if -int(diff) < len(s.payload) {
+				s.payload = s.payload[-diff:]
+				f.append(s)
+			}
+			progressed = true
+		}
+		if !progressed {
+			return
+		}
+	}
+}
+
+// skipGap gives up on missing data and continues at the earliest buffered segment
+func (f *flow) skipGap() {
+	first := true
+	var lowest uint32
+	for seq := range f.pending {
+		if first || int32(seq-lowest) < 0 {
+			lowest, first = seq, false
+		}
+	}
+	if first {
+		return
+	}
+	f.conv.Lost += int(int32(lowest - f.next))
+	f.next, f.buf = lowest, nil
+	f.drainPending()
+}
+
+// emit decodes an APDU and adds it to the conversation
+func (f *flow) emit(t time.Time, raw []byte) {
+	frame, err := iec_client.ParseFrame(raw, asdu.ParamsWide)
+	if err != nil {
+		frame.Error = err.Error()
+	}
+	frame.Time = t
+	frame.Direction = f.direction
+	f.conv.Frames = append(f.conv.Frames, frame)
+}
+
+// reassemble groups the segments to and from port into conversations
+func reassemble(segments []segment, port uint16) []*Conversation {
+	type key struct {
+		client, server netip.AddrPort
+	}
+	var conversations []*Conversation
+	flows := make(map[key][2]*flow)
+
+	for _, s := range segments {
+		k := key{s.src, s.dst}
+		toServer := true
+		switch {
+		case s.dst.Port() == port:
+		case s.src.Port() == port:
+			k, toServer = key{s.dst, s.src}, false
+		default:
+			continue
+		}
+
+		pair, ok := flows[k]
+		// A new connection between the same ports starts a new conversation
+		newConnection := ok && toServer && s.flags&tcpSYN != 0 && s.flags&tcpACK == 0 && len(pair[0].conv.Frames) > 0
+		if !ok || newConnection {
+			conv := &Conversation{Client: k.client, Server: k.server}
+			conversations = append(conversations, conv)
+			pair = [2]*flow{
+				{conv: conv, direction: iec_client.FrameSent},
+				{conv: conv, direction: iec_client.FrameReceived},
+			}
+			flows[k] = pair
+		}
+		if toServer {
+			pair[0].add(s)
+		} else {
+			pair[1].add(s)
+		}
+	}
+
+	for _, pair := range flows {
+		for _, f := range pair {
+			for len(f.pending) > 0 {
+				f.skipGap()
+			}
+		}
+	}
+	for _, c := range conversations {
+		sortFrames(c.Frames)
+	}
+	return conversations
+}
+
+// sortFrames orders frames by time, keeping the capture order for ties
+func sortFrames(frames []iec_client.Frame) {
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].Time.Before(frames[j].Time)
+	})
+}
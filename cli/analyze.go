@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"iec104/capture"
+	"iec104/config"
+	"os"
+	"time"
+)
+
+func runAnalyze(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: iec104 analyze [flags] capture...")
+		fs.PrintDefaults()
+	}
+	port := fs.Int("port", capture.DefaultPort, "TCP port of the IEC 104 server in the capture")
+	format := fs.String("format", "text", "output format: text or json")
+	t1 := fs.Duration("t1", capture.DefaultT1, "acknowledgement timeout t1 to check against")
+	t3 := fs.Duration("t3", capture.DefaultT3, "idle timeout t3 to check against")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() == 0 || (*format != "text" && *format != "json") {
+		fs.Usage()
+		return ExitUsage
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	for _, path := range fs.Args() {
+		conversations, err := capture.ReadFile(path, uint16(*port))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "analyze: %v\n", err)
+			return ExitError
+		}
+		for _, c := range conversations {
+			report := capture.Analyze(c, cfg.Profile, *t1, *t3)
+			if *format == "json" {
+				_ = enc.Encode(report)
+			} else {
+				printReport(report)
+			}
+		}
+	}
+	return ExitOK
+}
+
+// printReport prints a conversation report as text
+func printReport(r *capture.Report) {
+	if r.Client != "" {
+		fmt.Printf("connection %s -> %s\n", r.Client, r.Server)
+	} else {
+		fmt.Println("connection")
+	}
+	fmt.Printf("  %s - %s (%s), %d frames: %d I, %d S, %d U, %d malformed, %d bytes lost\n",
+		r.Start.Format("2006-01-02 15:04:05.000"), r.End.Format("15:04:05.000"), duration(r.End.Sub(r.Start)),
+		r.Frames, r.IFrames, r.SFrames, r.UFrames, r.Malformed, r.LostBytes)
+
+	fmt.Println("ASDU types")
+	for _, t := range r.Types {
+		fmt.Printf("  %-2s %-16s %8d\n", t.Direction, t.Name, t.Count)
+	}
+
+	if len(r.Gaps) == 0 {
+		fmt.Println("sequence gaps: none")
+	} else {
+		fmt.Println("sequence gaps")
+		for _, g := range r.Gaps {
+			fmt.Printf("  %s %-2s N(S) %d, expected %d\n", g.Time.Format("15:04:05.000"), g.Direction, g.Received, g.Expected)
+		}
+	}
+
+	tm := r.Timing
+	fmt.Printf("timing (t1 %s, t3 %s)\n", tm.T1, tm.T3)
+	for _, a := range tm.Ack {
+		fmt.Printf("  %-2s I-frames %d: ack avg %s max %s, %d over t1, %d unacknowledged\n",
+			a.Direction, a.Frames, duration(a.Average), duration(a.Max), a.OverT1, a.Unacknowledged)
+	}
+	fmt.Printf("  test frames %d: confirmation max %s, %d unanswered\n", tm.TestFrames, duration(tm.TestMax), tm.TestUnanswered)
+	fmt.Printf("  idle max %s, %d idle periods over t3 without test frame\n", duration(tm.IdleMax), tm.IdleOverT3)
+	if tm.UnconfirmedU > 0 {
+		fmt.Printf("  %d STARTDT/STOPDT without confirmation\n", tm.UnconfirmedU)
+	}
+
+	fmt.Println("commands")
+	for _, c := range r.Commands {
+		answer := "no confirmation"
+		if c.Confirmation != "" {
+			answer = fmt.Sprintf("%s after %s", c.Confirmation, duration(c.Delay))
+			if c.Negative {
+				answer = "negative " + answer
+			}
+		}
+		if c.Terminated > 0 {
+			answer += fmt.Sprintf(", terminated after %s", duration(c.Terminated))
+		}
+		fmt.Printf("  %s %-16s %-12s ca %d ioa %-6d %-16s -> %s\n",
+			c.Time.Format("15:04:05.000"), c.Type, c.Cause, c.CommonAddr, c.Address, c.Value, answer)
+	}
+
+	fmt.Println("points")
+	for _, p := range r.Points {
+		fmt.Printf("  %-14s ca %d %6d %-20s %12s %-8s updated %s (%d)\n",
+			p.Type, p.CommonAddr, p.Address, p.Name, p.Text, p.Quality, p.Updated.Format("15:04:05.000"), p.Updates)
+	}
+	fmt.Println()
+}
+
+// duration rounds a duration for display
+func duration(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(time.Microsecond)
+}
@@ -0,0 +1,345 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"iec104/capture"
+	"iec104/config"
+	"iec104/iec_client"
+	"iec104/metrics"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitNegative = 3
+	ExitTimeout  = 4
+)
+
+// command is a headless subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(cfg *config.Config, args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"connect", "connect to the server and wait for the link to come up", runConnect},
+		{"dump", "run a general interrogation and print the resulting snapshot", runDump},
+		{"stream", "print every point update until interrupted", runStream},
+		{"send", "send a single command and wait for its confirmation", runSend},
+		{"points", "export or import the point list as CSV", runPoints},
+		{"simulate", "run a simulated controlled station", runSimulate},
+		{"analyze", "report on the IEC 104 traffic in capture files", runAnalyze},
+		{"mqtt", "publish point updates to an MQTT broker and accept commands", runMQTT},
+		{"serve", "serve the HTTP JSON API once the link is up", runServe},
+		{"grpc", "serve the gRPC service once the link is up", runGRPC},
+	}
+}
+
+// Run executes the subcommand in args[0] and returns the process exit code
+func Run(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return ExitUsage
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(cfg, args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	usage(os.Stderr)
+	return ExitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: iec104 <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run without a command to start the terminal UI.")
+}
+
+// options are the flags shared by every subcommand
+type options struct {
+	timeout time.Duration
+	format  string
+	verbose bool
+	capture string
+}
+
+func newFlagSet(name string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.DurationVar(&opts.timeout, "timeout", 10*time.Second, "time to wait for the link and confirmations")
+	fs.StringVar(&opts.format, "format", "text", "output format: text or json")
+	fs.BoolVar(&opts.verbose, "v", false, "log protocol events to stderr")
+	fs.StringVar(&opts.capture, "capture", "", "record the frames to a .pcap, .pcapng or .jsonl file")
+	return fs
+}
+
+// serverUsage describes how serve and grpc handle the link
+const serverUsage = "Connects first and exits if the link is not up within -timeout, then serves\n" +
+	"until interrupted. A lost link is reestablished automatically."
+
+// setUsage makes the help of a command describe it before its flags
+func setUsage(fs *flag.FlagSet, description string) {
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: iec104 %s [flags]\n\n%s\n\nFlags:\n", fs.Name(), description)
+		fs.PrintDefaults()
+	}
+}
+
+// connect creates a client and waits for the link to come up. The returned
+// func closes the client and the capture file.
+func connect(cfg *config.Config, opts options) (*iec_client.IEC104Client, func(), int) {
+	client, done, code := newClient(cfg, opts)
+	if client == nil {
+		return nil, nil, code
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	if err := client.ConnectContext(ctx); err != nil {
+		done()
+		fmt.Fprintf(os.Stderr, "connect %s:%d: %v\n", cfg.IPAddress, cfg.Port, err)
+		return nil, nil, exitCode(err)
+	}
+	return client, done, ExitOK
+}
+
+// newClient creates a client without connecting, recording its frames if
+// asked to. The returned func closes the client and the capture file.
+func newClient(cfg *config.Config, opts options) (*iec_client.IEC104Client, func(), int) {
+	client := iec_client.NewIEC104Client(cfg.Profile)
+	client.Logger = newStderrLogger(opts.verbose)
+	client.LibraryDebug = opts.verbose
+
+	var recorder capture.Writer
+	if opts.capture != "" {
+		var err error
+		recorder, err = capture.Create(opts.capture, capture.ServerAddr(cfg.IPAddress, cfg.Port))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "capture: %v\n", err)
+			return nil, nil, ExitUsage
+		}
+		client.SetRecorder(recorder)
+	}
+	done := func() {
+		client.Close()
+		if recorder != nil {
+			client.SetRecorder(nil)
+			if err := recorder.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "capture: %v\n", err)
+			}
+		}
+	}
+	return client, done, ExitOK
+}
+
+func runConnect(cfg *config.Config, args []string) int {
+	var opts options
+	fs := newFlagSet("connect", &opts)
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	client, done, code := connect(cfg, opts)
+	if client == nil {
+		return code
+	}
+	defer done()
+
+	fmt.Printf("connected to %s:%d\n", cfg.IPAddress, cfg.Port)
+	return ExitOK
+}
+
+func runDump(cfg *config.Config, args []string) int {
+	var opts options
+	fs := newFlagSet("dump", &opts)
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	client, done, code := connect(cfg, opts)
+	if client == nil {
+		return code
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	if err := client.InterrogateContext(ctx, asdu.QOIStation); err != nil {
+		fmt.Fprintf(os.Stderr, "interrogation: %v\n", err)
+		return exitCode(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	for _, u := range client.Snapshot() {
+		printUpdate(enc, opts.format, cfg.Profile, u)
+	}
+	return ExitOK
+}
+
+func runStream(cfg *config.Config, args []string) int {
+	var opts options
+	fs := newFlagSet("stream", &opts)
+	typ := fs.String("type", "", "only stream this type: telemetry or teleindication")
+	minAddr := fs.Int("min-ioa", 0, "lowest information object address to stream")
+	maxAddr := fs.Int("max-ioa", 0, "highest information object address to stream")
+	interrogate := fs.Bool("gi", true, "run a general interrogation after connecting")
+	metricsAddr := fs.String("metrics", "", "serve Prometheus metrics on this address, e.g. :9104")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	filter := iec_client.Filter{MinAddress: *minAddr, MaxAddress: *maxAddr}
+	if *typ != "" {
+		t, err := iec_client.ParseDataType(*typ)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return ExitUsage
+		}
+		filter.Types = []iec_client.DataType{t}
+	}
+
+	client, done, code := connect(cfg, opts)
+	if client == nil {
+		return code
+	}
+	defer done()
+
+	sub := client.Subscribe(iec_client.SubscribeOptions{
+		Filter: filter,
+		Buffer: 4096,
+		Policy: iec_client.DropOldest,
+	})
+	defer sub.Close()
+
+	if *metricsAddr != "" {
+		exporter := metrics.NewExporter()
+		exporter.Add(client)
+		srv, err := exporter.Serve(*metricsAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "metrics: %v\n", err)
+			return ExitUsage
+		}
+		defer srv.Close()
+	}
+
+	if *interrogate {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+			defer cancel()
+			if err := client.InterrogateContext(ctx, asdu.QOIStation); err != nil {
+				fmt.Fprintf(os.Stderr, "interrogation: %v\n", err)
+			}
+		}()
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	enc := json.NewEncoder(os.Stdout)
+	for {
+		select {
+		case u, ok := <-sub.C:
+			if !ok {
+				return ExitOK
+			}
+			printUpdate(enc, opts.format, cfg.Profile, u)
+		case <-interrupt:
+			if n := sub.Dropped(); n > 0 {
+				fmt.Fprintf(os.Stderr, "%d updates dropped\n", n)
+			}
+			return ExitOK
+		}
+	}
+}
+
+func runSend(cfg *config.Config, args []string) int {
+	var opts options
+	fs := newFlagSet("send", &opts)
+	kind := fs.String("kind", "sc", "command kind: sc (single), dc (double), se-nc (float setpoint), se-nb (scaled setpoint), se-na (normalized setpoint)")
+	ioa := fs.Int("ioa", 0, "information object address")
+	value := fs.String("value", "", "command value: on/off for sc and dc, a number for setpoints")
+	selectFirst := fs.Bool("select", true, "use select before operate for sc and dc")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if *ioa <= 0 || *value == "" {
+		fmt.Fprintln(os.Stderr, "send: -ioa and -value are required")
+		fs.Usage()
+		return ExitUsage
+	}
+
+	send, err := iec_client.ParseCommand(*kind, *ioa, *value, *selectFirst)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "send: %v\n", err)
+		return ExitUsage
+	}
+
+	client, done, code := connect(cfg, opts)
+	if client == nil {
+		return code
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	if err := send(ctx, client); err != nil {
+		fmt.Fprintf(os.Stderr, "send %s to %d: %v\n", *kind, *ioa, err)
+		return exitCode(err)
+	}
+	fmt.Printf("%s to %d confirmed\n", *kind, *ioa)
+	return ExitOK
+}
+
+// exitCode maps client errors to process exit codes
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, iec_client.ErrorNegativeConfirmation):
+		return ExitNegative
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitTimeout
+	default:
+		return ExitError
+	}
+}
+
+func printUpdate(enc *json.Encoder, format string, profile *config.Profile, u iec_client.Update) {
+	if format == "json" {
+		_ = enc.Encode(iec_client.NewRecord(profile, u))
+		return
+	}
+
+	point := profile.FindPoint(u.Type.PointType(), u.Address)
+	value := point.FormatValue(u.Value)
+	switch {
+	case u.Double:
+		value, _ = point.DoubleStateLabel(int(u.DoubleState))
+	case u.Type == iec_client.Teleindication:
+		value, _ = point.StateLabel(u.State)
+	}
+	fmt.Printf("%-14s %6d %12s %s\n", u.Type, u.Address, value, iec_client.QualityString(u.Quality))
+}
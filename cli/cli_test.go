@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"iec104/config"
+	"iec104/simulator/simtest"
+)
+
+// testConfig returns a config saved to a temporary file whose active
+// profile is the station at addr
+func testConfig(t *testing.T, addr string) *config.Config {
+	t.Helper()
+	cfg, err := config.Load(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Profiles = []*config.Profile{simtest.Profile(t, addr)}
+	if err := cfg.Select("test"); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// run runs a subcommand and returns its exit code and what it printed to
+// stdout and stderr
+func run(t *testing.T, cfg *config.Config, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	restore := redirect(t, &os.Stdout, &stdout)
+	restoreErr := redirect(t, &os.Stderr, &stderr)
+	code := Run(cfg, args)
+	restore()
+	restoreErr()
+	return code, stdout.String(), stderr.String()
+}
+
+// redirect points *f at a pipe copied into buf until the returned func
+// restores it
+func redirect(t *testing.T, f **os.File, buf *bytes.Buffer) func() {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := *f
+	*f = w
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		io.Copy(buf, r)
+	}()
+	return func() {
+		*f = saved
+		w.Close()
+		wg.Wait()
+		r.Close()
+	}
+}
+
+func TestRun(t *testing.T) {
+	sim := simtest.Station(t)
+	cfg := testConfig(t, sim.Addr())
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout []string
+		stderr string
+	}{
+		{"no command", nil, ExitUsage, nil, "Usage: iec104 <command>"},
+		{"unknown command", []string{"reset"}, ExitUsage, nil, `unknown command "reset"`},
+		{"unknown flag", []string{"connect", "-retries", "3"}, ExitUsage, nil, "flag provided but not defined"},
+		{"connect", []string{"connect"}, ExitOK, []string{"connected to " + sim.Addr()}, ""},
+		{"dump", []string{"dump"}, ExitOK, []string{"Telemetry       16385        12.50 OK", "Teleindication      1           ON OK"}, ""},
+		{"dump json", []string{"dump", "-format", "json"}, ExitOK, []string{`"ioa":16385`}, ""},
+		{"send", []string{"send", "-ioa", "24577", "-value", "off"}, ExitOK, []string{"sc to 24577 confirmed"}, ""},
+		{"send rejected", []string{"send", "-ioa", "24578", "-value", "on"}, ExitNegative, nil, "negative confirmation"},
+		{"send setpoint", []string{"send", "-kind", "se-nc", "-ioa", "25089", "-value", "42.5"}, ExitOK, []string{"se-nc to 25089 confirmed"}, ""},
+		{"send without address", []string{"send", "-value", "on"}, ExitUsage, nil, "-ioa and -value are required"},
+		{"send unknown kind", []string{"send", "-kind", "xx", "-ioa", "24577", "-value", "on"}, ExitUsage, nil, "unknown command kind"},
+		{"send invalid value", []string{"send", "-ioa", "24577", "-value", "maybe"}, ExitUsage, nil, "send:"},
+		{"stream unknown type", []string{"stream", "-type", "counter"}, ExitUsage, nil, `unknown data type "counter"`},
+		{"points without command", []string{"points"}, ExitUsage, nil, "Usage: iec104 points"},
+		{"points unknown command", []string{"points", "list"}, ExitUsage, nil, `unknown points command "list"`},
+		{"points import without file", []string{"points", "import"}, ExitUsage, nil, "exactly one file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := run(t, cfg, tt.args...)
+			if code != tt.code {
+				t.Errorf("exit code %d, want %d; stderr:\n%s", code, tt.code, stderr)
+			}
+			for _, want := range tt.stdout {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("stdout does not contain %q:\n%s", want, stdout)
+				}
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("stderr does not contain %q:\n%s", tt.stderr, stderr)
+			}
+		})
+	}
+}
+
+func TestRunUnreachable(t *testing.T) {
+	sim := simtest.Station(t)
+	cfg := testConfig(t, sim.Addr())
+	sim.Close()
+
+	for _, args := range [][]string{
+		{"connect", "-timeout", "300ms"},
+		{"send", "-timeout", "300ms", "-ioa", "24577", "-value", "on"},
+		// both servers give up before serving
+		{"serve", "-timeout", "300ms", "-listen", "127.0.0.1:0"},
+		{"grpc", "-timeout", "300ms", "-listen", "127.0.0.1:0"},
+	} {
+		code, stdout, stderr := run(t, cfg, args...)
+		if code != ExitTimeout && code != ExitError {
+			t.Errorf("%s: exit code %d, want a timeout or error", args[0], code)
+		}
+		if stdout != "" || !strings.Contains(stderr, "connect "+sim.Addr()) {
+			t.Errorf("%s: stdout %q, stderr %q", args[0], stdout, stderr)
+		}
+	}
+}
+
+func TestRunPoints(t *testing.T) {
+	cfg := testConfig(t, "127.0.0.1:1")
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "points.csv")
+	os.WriteFile(valid, []byte("ioa,type,name,unit\n16385,telemetry,Voltage,kV\n1,teleindication,Breaker,\n"), 0600)
+	invalid := filepath.Join(dir, "invalid.csv")
+	os.WriteFile(invalid, []byte("ioa,type,name\nx,telemetry,Voltage\n"), 0600)
+
+	if code, _, stderr := run(t, cfg, "points", "import", invalid); code != ExitError || !strings.Contains(stderr, "invalid ioa") {
+		t.Errorf("import of an invalid file: exit code %d, stderr %q", code, stderr)
+	}
+	if code, _, _ := run(t, cfg, "points", "import", "-dry-run", valid); code != ExitOK || len(cfg.Points) != 0 {
+		t.Errorf("dry run: exit code %d, %d points configured", code, len(cfg.Points))
+	}
+	code, stdout, _ := run(t, cfg, "points", "import", valid)
+	if code != ExitOK || !strings.Contains(stdout, "imported 2 points into profile test") {
+		t.Fatalf("import: exit code %d, stdout %q", code, stdout)
+	}
+	saved, err := config.Load(cfg.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := saved.PointName(config.PointTelemetry, 16385); name != "Voltage" {
+		t.Errorf("saved point name %q, want Voltage", name)
+	}
+
+	code, stdout, _ = run(t, cfg, "points", "export")
+	if code != ExitOK || !strings.Contains(stdout, "16385,telemetry,Voltage,kV") {
+		t.Errorf("export: exit code %d, stdout:\n%s", code, stdout)
+	}
+	out := filepath.Join(dir, "export.csv")
+	if code, _, _ := run(t, cfg, "points", "export", "-o", out); code != ExitOK {
+		t.Errorf("export to a file: exit code %d", code)
+	}
+	if data, _ := os.ReadFile(out); string(data) != stdout {
+		t.Errorf("exported file differs from stdout:\n%s", data)
+	}
+}
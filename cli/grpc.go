@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"iec104/config"
+	"iec104/rpc"
+	"os"
+	"os/signal"
+)
+
+func runGRPC(cfg *config.Config, args []string) int {
+	var opts options
+	fs := newFlagSet("grpc", &opts)
+	listen := fs.String("listen", "127.0.0.1:50051", "address to serve the gRPC service on")
+	reflect := fs.Bool("reflection", false, "enable server reflection for tools like grpcurl")
+	setUsage(fs, serverUsage+"\nDisconnect and Connect calls take the link down and up again.")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	client, done, code := connect(cfg, opts)
+	if client == nil {
+		return code
+	}
+	defer done()
+
+	server := rpc.NewServer()
+	server.CommandTimeout = opts.timeout
+	server.Reflection = *reflect
+	server.Add(client)
+	srv, err := server.Serve(*listen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grpc: %v\n", err)
+		return ExitUsage
+	}
+	defer srv.Stop()
+	fmt.Fprintf(os.Stderr, "serving gRPC on %s\n", *listen)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	<-interrupt
+	return ExitOK
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"log"
+	"os"
+)
+
+// stderrLogger implements iec_client.Logger for headless use
+type stderrLogger struct {
+	log     *log.Logger
+	verbose bool
+}
+
+func newStderrLogger(verbose bool) *stderrLogger {
+	return &stderrLogger{
+		log:     log.New(os.Stderr, "", log.LstdFlags),
+		verbose: verbose,
+	}
+}
+
+// Debugf logs only in verbose mode
+func (l *stderrLogger) Debugf(format string, args ...interface{}) {
+	if l.verbose {
+		l.log.Printf("Debug: "+format, args...)
+	}
+}
+
+// Infof logs only in verbose mode to keep stdout/stderr clean for scripts
+func (l *stderrLogger) Infof(format string, args ...interface{}) {
+	if l.verbose {
+		l.log.Printf("Info: "+format, args...)
+	}
+}
+
+// Errorf always logs
+func (l *stderrLogger) Errorf(format string, args ...interface{}) {
+	l.log.Printf("Error: "+format, args...)
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"iec104/config"
+	"iec104/mqtt"
+	"os"
+	"os/signal"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// EnvMQTTPassword is read when -password is not given, to keep it off the command line
+const EnvMQTTPassword = "IEC104_MQTT_PASSWORD"
+
+func runMQTT(cfg *config.Config, args []string) int {
+	var opts options
+	fs := newFlagSet("mqtt", &opts)
+	var gw mqtt.Options
+	fs.StringVar(&gw.Broker, "broker", "", "MQTT broker URL, e.g. tcp://localhost:1883")
+	fs.StringVar(&gw.ClientID, "client-id", "", "MQTT client ID (default iec104-<profile>)")
+	fs.StringVar(&gw.Username, "username", "", "MQTT user name")
+	fs.StringVar(&gw.Password, "password", "", "MQTT password, $"+EnvMQTTPassword+" if not given")
+	fs.StringVar(&gw.Topic, "topic", mqtt.DefaultTopic, "topic template with {profile}, {ca}, {type}, {ioa} and {name}")
+	qos := fs.Uint("qos", 0, "QoS of the published messages and the command subscription: 0, 1 or 2")
+	fs.BoolVar(&gw.Retain, "retain", false, "publish the point values as retained messages")
+	fs.StringVar(&gw.CommandTopic, "command-topic", "", "accept commands on this topic; results go to <topic>"+mqtt.ResultSuffix)
+	fs.StringVar(&gw.StatusTopic, "status-topic", "", "topic holding a retained online/offline status of the gateway")
+	interrogate := fs.Bool("gi", true, "run a general interrogation after connecting")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if gw.Broker == "" {
+		fmt.Fprintf(os.Stderr, "mqtt: %v\n", mqtt.ErrorNoBroker)
+		fs.Usage()
+		return ExitUsage
+	}
+	if gw.Password == "" {
+		gw.Password = os.Getenv(EnvMQTTPassword)
+	}
+	if *qos > 2 {
+		fmt.Fprintf(os.Stderr, "mqtt: %v\n", mqtt.ErrorInvalidQoS)
+		return ExitUsage
+	}
+	gw.QoS = byte(*qos)
+	gw.CommandTimeout = opts.timeout
+
+	client, done, code := connect(cfg, opts)
+	if client == nil {
+		return code
+	}
+	defer done()
+
+	gateway, err := mqtt.New(client, gw)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mqtt: %v\n", err)
+		return ExitUsage
+	}
+	gateway.Logger = client.Logger
+	if err := gateway.Start(opts.timeout); err != nil {
+		fmt.Fprintf(os.Stderr, "mqtt %s: %v\n", gw.Broker, err)
+		return ExitError
+	}
+	defer gateway.Close()
+
+	if *interrogate {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+			defer cancel()
+			if err := client.InterrogateContext(ctx, asdu.QOIStation); err != nil {
+				fmt.Fprintf(os.Stderr, "interrogation: %v\n", err)
+			}
+		}()
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	<-interrupt
+
+	if n := gateway.Dropped(); n > 0 {
+		fmt.Fprintf(os.Stderr, "%d updates dropped\n", n)
+	}
+	return ExitOK
+}
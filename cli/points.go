@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"iec104/config"
+	"os"
+)
+
+func runPoints(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: iec104 points export [-o file] | import [-merge] [-dry-run] file")
+		return ExitUsage
+	}
+	switch args[0] {
+	case "export":
+		return runPointsExport(cfg, args[1:])
+	case "import":
+		return runPointsImport(cfg, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown points command %q\n", args[0])
+		return ExitUsage
+	}
+}
+
+func runPointsExport(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("points export", flag.ContinueOnError)
+	output := fs.String("o", "", "write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	w := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return ExitError
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := cfg.ExportPointsCSV(w); err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+func runPointsImport(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("points import", flag.ContinueOnError)
+	merge := fs.Bool("merge", false, "merge into the existing points instead of replacing them")
+	dryRun := fs.Bool("dry-run", false, "validate the file without saving")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "import needs exactly one file")
+		return ExitUsage
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitError
+	}
+	defer f.Close()
+
+	points, report, err := cfg.ReadPointsCSV(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import: %v\n", err)
+		return ExitError
+	}
+	fmt.Fprint(os.Stderr, report)
+	if report.HasErrors() {
+		return ExitError
+	}
+	if *dryRun {
+		return ExitOK
+	}
+
+	cfg.ReplacePoints(points, *merge)
+	if err := cfg.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "save: %v\n", err)
+		return ExitError
+	}
+	fmt.Printf("imported %d points into profile %s\n", len(points), cfg.Name)
+	return ExitOK
+}
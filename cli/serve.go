@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"iec104/api"
+	"iec104/config"
+	"iec104/metrics"
+	"os"
+	"os/signal"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+func runServe(cfg *config.Config, args []string) int {
+	var opts options
+	fs := newFlagSet("serve", &opts)
+	listen := fs.String("listen", "127.0.0.1:8104", "address to serve the HTTP JSON API on")
+	origins := fs.String("origins", os.Getenv(config.EnvAPIOrigins), "other origins allowed to open the WebSocket and send commands, comma separated, or * (env "+config.EnvAPIOrigins+")")
+	token := fs.String("token", os.Getenv(config.EnvAPIToken), "bearer token required for interrogations and commands (env "+config.EnvAPIToken+")")
+	metricsAddr := fs.String("metrics", "", "serve Prometheus metrics on this address, e.g. :9104")
+	interrogate := fs.Bool("gi", true, "run a general interrogation after connecting")
+	setUsage(fs, serverUsage)
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	client, done, code := connect(cfg, opts)
+	if client == nil {
+		return code
+	}
+	defer done()
+
+	server := api.NewServer()
+	server.CommandTimeout = opts.timeout
+	server.Token = *token
+	server.Origins = api.ParseOrigins(*origins)
+	server.Add(client)
+	srv, err := server.Serve(*listen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "api: %v\n", err)
+		return ExitUsage
+	}
+	defer srv.Close()
+	fmt.Fprintf(os.Stderr, "serving API on http://%s%s\n", *listen, api.Prefix)
+
+	if *metricsAddr != "" {
+		exporter := metrics.NewExporter()
+		exporter.Add(client)
+		srv, err := exporter.Serve(*metricsAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "metrics: %v\n", err)
+			return ExitUsage
+		}
+		defer srv.Close()
+	}
+
+	if *interrogate {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+			defer cancel()
+			if err := client.InterrogateContext(ctx, asdu.QOIStation); err != nil {
+				fmt.Fprintf(os.Stderr, "interrogation: %v\n", err)
+			}
+		}()
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	<-interrupt
+	return ExitOK
+}
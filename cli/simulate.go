@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"iec104/config"
+	"iec104/simulator"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func runSimulate(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	listen := fs.String("listen", fmt.Sprintf(":%d", cfg.Port), "address to listen on")
+	dbPath := fs.String("db", "", "point database JSON file; defaults to one matching the profile")
+	genPath := fs.String("generators", "", "value generator JSON file")
+	changes := fs.Duration("changes", 0, "interval between random spontaneous changes, 0 to disable")
+	exportDB := fs.String("export-db", "", "write the point database to this file and exit")
+	verbose := fs.Bool("v", false, "log protocol events to stderr")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	db := simulator.DatabaseFromProfile(cfg.Profile)
+	if *dbPath != "" {
+		loaded, err := simulator.LoadDatabase(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "simulate: %v\n", err)
+			return ExitError
+		}
+		db = loaded
+	}
+
+	if *exportDB != "" {
+		data, err := json.MarshalIndent(db, "", "  ")
+		if err == nil {
+			err = os.WriteFile(*exportDB, append(data, '\n'), 0644)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export: %v\n", err)
+			return ExitError
+		}
+		return ExitOK
+	}
+
+	server, err := simulator.NewServer(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "simulate: %v\n", err)
+		return ExitError
+	}
+	server.Logger = newStderrLogger(*verbose)
+	server.RegisterCommandHandler(func(cmd simulator.Command) {
+		phase := "execute"
+		if cmd.Select {
+			phase = "select"
+		}
+		fmt.Printf("%s %s %s ioa %d value %g\n", cmd.Received.Format("15:04:05.000"), cmd.Type, phase, cmd.Address, cmd.Value)
+	})
+	if *genPath != "" {
+		generators, err := simulator.LoadGenerators(*genPath)
+		if err == nil {
+			err = server.StartGenerators(generators)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "simulate: %v\n", err)
+			return ExitError
+		}
+	}
+	if err := server.Start(*listen); err != nil {
+		fmt.Fprintf(os.Stderr, "listen %s: %v\n", *listen, err)
+		return ExitError
+	}
+	defer server.Close()
+	fmt.Fprintf(os.Stderr, "simulating common address %d with %d points on %s\n", db.CommonAddress, len(db.Points), server.Addr())
+
+	var tick <-chan time.Time
+	if *changes > 0 {
+		ticker := time.NewTicker(*changes)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	for {
+		select {
+		case <-tick:
+			if _, err := server.RandomChange(); err != nil {
+				fmt.Fprintf(os.Stderr, "change: %v\n", err)
+			}
+		case <-interrupt:
+			return ExitOK
+		}
+	}
+}
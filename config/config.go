@@ -2,51 +2,65 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 )
 
-const filePath = "config.json"
+// DefaultPath is the config file used when no path is given
+const DefaultPath = "config.json"
 
-// Config holds the application configuration
+// DefaultProfileName names the profile created for new or legacy config files
+const DefaultProfileName = "default"
+
+// Config holds the application configuration. The embedded Profile is the
+// active connection profile, so its fields can be used directly.
 type Config struct {
-	IPAddress             string
-	Port                  int
-	CommonAddress         int
-	TelemetryCount        int
-	TeleindCount          int
-	InterrogationInterval int // in seconds
-
-	TelemetryDescriptions map[int]string `json:"telemetry_descriptions"`
-	TeleindDescriptions   map[int]string `json:"teleind_descriptions"`
+	*Profile `json:"-"`
+
+	ActiveProfile string     `json:"active_profile"`
+	Profiles      []*Profile `json:"profiles"`
+
+	path string
+	// override holds the settings replaced by command line options
+	override *override
 }
 
 // NewConfig creates a new configuration with default values
 func NewConfig() *Config {
+	profile := NewProfile(DefaultProfileName)
 	return &Config{
-		IPAddress:             "127.0.0.1",
-		Port:                  2404,
-		CommonAddress:         1,
-		TelemetryCount:        100,
-		TeleindCount:          100,
-		InterrogationInterval: 15,
-
-		TelemetryDescriptions: make(map[int]string),
-		TeleindDescriptions:   make(map[int]string),
+		Profile:       profile,
+		ActiveProfile: profile.Name,
+		Profiles:      []*Profile{profile},
+
+		path: DefaultPath,
 	}
 }
 
+// Path returns the file the configuration is saved to
+func (c *Config) Path() string {
+	return c.path
+}
+
 // Save persists the configuration
 func (c *Config) Save() error {
-	fd, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	fd, err := os.OpenFile(c.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
 
 	defer fd.Close()
 
+	saved := *c
+	saved.Profiles = make([]*Profile, len(c.Profiles))
+	for i, p := range c.Profiles {
+		saved.Profiles[i] = c.override.saveForm(p)
+	}
+
 	encoder := json.NewEncoder(fd)
 	encoder.SetIndent("", "  ")
-	err = encoder.Encode(c)
+	err = encoder.Encode(&saved)
 	if err != nil {
 		return err
 	}
@@ -54,18 +68,50 @@ func (c *Config) Save() error {
 	return nil
 }
 
+// LoadFromDisk reads the default config file, falling back to defaults on any error
 func LoadFromDisk() (cfg *Config) {
-	cfg = NewConfig()
-	fd, err := os.Open(filePath)
+	cfg, err := Load(DefaultPath)
 	if err != nil {
-		return
+		return NewConfig()
 	}
+	return cfg
+}
 
-	defer fd.Close()
-	decoder := json.NewDecoder(fd)
-	err = decoder.Decode(&cfg)
+// Load reads the config file at path. A missing file yields the default
+// configuration, which will be saved to path. Files written before profiles
+// existed are loaded as a single default profile.
+func Load(path string) (*Config, error) {
+	cfg := NewConfig()
+	cfg.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
 	if err != nil {
-		return
+		return nil, err
+	}
+
+	cfg.Profiles = nil
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %v", path, err)
+	}
+
+	if len(cfg.Profiles) == 0 {
+		legacy := NewProfile(DefaultProfileName)
+		if err := json.Unmarshal(data, legacy); err != nil {
+			return nil, fmt.Errorf("parse %s: %v", path, err)
+		}
+		legacy.Name = DefaultProfileName
+		cfg.Profiles = []*Profile{legacy}
+	}
+
+	for _, p := range cfg.Profiles {
+		p.normalize()
+	}
+	if err := cfg.Select(cfg.ActiveProfile); err != nil {
+		cfg.Profile = cfg.Profiles[0]
+		cfg.ActiveProfile = cfg.Profile.Name
 	}
-	return
+	return cfg, nil
 }
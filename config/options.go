@@ -0,0 +1,223 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables that override the config file. Command line flags
+// take precedence over them.
+const (
+	EnvConfigPath     = "IEC104_CONFIG"
+	EnvProfile        = "IEC104_PROFILE"
+	EnvHost           = "IEC104_HOST"
+	EnvPort           = "IEC104_PORT"
+	EnvCommonAddress  = "IEC104_CA"
+	EnvLogLevel       = "IEC104_LOG_LEVEL"
+	EnvStartConnected = "IEC104_CONNECT"
+	EnvReplay         = "IEC104_REPLAY"
+	EnvMetrics        = "IEC104_METRICS"
+	EnvAPI            = "IEC104_API"
+	EnvAPIToken       = "IEC104_API_TOKEN"
+	EnvAPIOrigins     = "IEC104_API_ORIGINS"
+	EnvGRPC           = "IEC104_GRPC"
+	EnvGRPCReflection = "IEC104_GRPC_REFLECTION"
+)
+
+// Options holds the settings given on the command line or in the environment
+type Options struct {
+	ConfigPath     string
+	Profile        string
+	Host           string
+	Port           int
+	CommonAddress  int
+	LogLevel       string
+	StartConnected bool
+	// Replay is a capture file to replay instead of connecting
+	Replay string
+	// Metrics is the address of the Prometheus metrics listener, if any
+	Metrics string
+	// API is the address of the HTTP JSON API listener, if any
+	API string
+	// APIToken, if set, is required by the API to send commands
+	APIToken string
+	// APIOrigins lists other origins whose pages may open the WebSocket and
+	// send commands, separated by commas
+	APIOrigins string
+	// GRPC is the address of the gRPC service listener, if any
+	GRPC string
+	// GRPCReflection enables server reflection on the gRPC service
+	GRPCReflection bool
+
+	// Args are the remaining arguments after the flags, e.g. a headless command
+	Args []string
+}
+
+// uiFlags are the global flags only the terminal UI honours; headless
+// commands have flags of their own
+var uiFlags = []string{"log-level", "connect", "replay", "metrics", "api", "api-origins", "api-token", "grpc", "grpc-reflection"}
+
+// ParseOptions parses the global flags with environment variables as
+// defaults. Flags of the terminal UI given with a command are an error.
+func ParseOptions(args []string, output io.Writer) (*Options, error) {
+	opts := &Options{
+		ConfigPath: envString(EnvConfigPath, DefaultPath),
+		Profile:    envString(EnvProfile, ""),
+		Host:       envString(EnvHost, ""),
+		LogLevel:   envString(EnvLogLevel, "info"),
+		Replay:     envString(EnvReplay, ""),
+		Metrics:    envString(EnvMetrics, ""),
+		API:        envString(EnvAPI, ""),
+		APIToken:   envString(EnvAPIToken, ""),
+		APIOrigins: envString(EnvAPIOrigins, ""),
+		GRPC:       envString(EnvGRPC, ""),
+	}
+
+	var err error
+	if opts.Port, err = envInt(EnvPort); err != nil {
+		return nil, err
+	}
+	if opts.CommonAddress, err = envInt(EnvCommonAddress); err != nil {
+		return nil, err
+	}
+	if v := os.Getenv(EnvStartConnected); v != "" {
+		if opts.StartConnected, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("%s: %v", EnvStartConnected, err)
+		}
+	}
+	if v := os.Getenv(EnvGRPCReflection); v != "" {
+		if opts.GRPCReflection, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("%s: %v", EnvGRPCReflection, err)
+		}
+	}
+
+	fs := flag.NewFlagSet("iec104", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintln(output, "Usage: iec104 [flags] [command [command flags]]")
+		fmt.Fprintln(output)
+		fmt.Fprintln(output, "Flags:")
+		fs.PrintDefaults()
+		fmt.Fprintln(output)
+		fmt.Fprintf(output, "-%s only apply to the terminal UI, not to commands.\n", strings.Join(uiFlags, ", -"))
+	}
+	fs.StringVar(&opts.ConfigPath, "config", opts.ConfigPath, "config file path (env "+EnvConfigPath+")")
+	fs.StringVar(&opts.Profile, "profile", opts.Profile, "connection profile to use (env "+EnvProfile+")")
+	fs.StringVar(&opts.Host, "host", opts.Host, "override the server IP address (env "+EnvHost+")")
+	fs.IntVar(&opts.Port, "port", opts.Port, "override the server port (env "+EnvPort+")")
+	fs.IntVar(&opts.CommonAddress, "ca", opts.CommonAddress, "override the common address (env "+EnvCommonAddress+")")
+	fs.StringVar(&opts.LogLevel, "log-level", opts.LogLevel, "log level: info or debug (env "+EnvLogLevel+")")
+	fs.BoolVar(&opts.StartConnected, "connect", opts.StartConnected, "connect on startup (env "+EnvStartConnected+")")
+	fs.StringVar(&opts.Replay, "replay", opts.Replay, "replay a capture file offline instead of connecting (env "+EnvReplay+")")
+	fs.StringVar(&opts.Metrics, "metrics", opts.Metrics, "serve Prometheus metrics on this address, e.g. :9104 (env "+EnvMetrics+")")
+	fs.StringVar(&opts.API, "api", opts.API, "serve the HTTP JSON API on this address, e.g. 127.0.0.1:8104 (env "+EnvAPI+")")
+	fs.StringVar(&opts.APIOrigins, "api-origins", opts.APIOrigins, "other origins allowed to open the API's WebSocket and send commands, comma separated, or * (env "+EnvAPIOrigins+")")
+	fs.StringVar(&opts.APIToken, "api-token", opts.APIToken, "bearer token the API requires for interrogations and commands (env "+EnvAPIToken+")")
+	fs.StringVar(&opts.GRPC, "grpc", opts.GRPC, "serve the gRPC service on this address, e.g. 127.0.0.1:50051 (env "+EnvGRPC+")")
+	fs.BoolVar(&opts.GRPCReflection, "grpc-reflection", opts.GRPCReflection, "enable server reflection on the gRPC service (env "+EnvGRPCReflection+")")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if opts.LogLevel != "info" && opts.LogLevel != "debug" {
+		return nil, fmt.Errorf("invalid log level %q", opts.LogLevel)
+	}
+	opts.Args = fs.Args()
+	if len(opts.Args) > 0 {
+		var err error
+		fs.Visit(func(f *flag.Flag) {
+			for _, name := range uiFlags {
+				if f.Name == name && err == nil {
+					err = fmt.Errorf("-%s only applies to the terminal UI, not to the %s command", name, opts.Args[0])
+				}
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return opts, nil
+}
+
+// Apply selects the requested profile and overrides it with any non-zero
+// option. The overrides only last for this run: Save writes the values
+// they replaced unless the settings were changed since.
+func (o *Options) Apply(cfg *Config) error {
+	if o.Profile != "" {
+		if err := cfg.Select(o.Profile); err != nil {
+			return err
+		}
+	}
+	if o.Host == "" && o.Port <= 0 && o.CommonAddress <= 0 {
+		return nil
+	}
+
+	ov := &override{
+		profile: cfg.Profile,
+		saved:   endpointOf(cfg.Profile),
+	}
+	if o.Host != "" {
+		cfg.IPAddress = o.Host
+	}
+	if o.Port > 0 {
+		cfg.Port = o.Port
+	}
+	if o.CommonAddress > 0 {
+		cfg.CommonAddress = o.CommonAddress
+	}
+	ov.applied = endpointOf(cfg.Profile)
+	cfg.override = ov
+	return nil
+}
+
+// endpoint is the part of a profile the options override
+type endpoint struct {
+	host string
+	port int
+	ca   int
+}
+
+func endpointOf(p *Profile) endpoint {
+	return endpoint{p.IPAddress, p.Port, p.CommonAddress}
+}
+
+// override remembers the settings a profile had before Apply
+type override struct {
+	profile *Profile
+	saved   endpoint
+	applied endpoint
+}
+
+// saveForm returns the profile to save in place of p: a copy with the
+// values before Apply if p still has the overridden ones
+func (ov *override) saveForm(p *Profile) *Profile {
+	if ov == nil || p != ov.profile || endpointOf(p) != ov.applied {
+		return p
+	}
+	saved := *p
+	saved.IPAddress, saved.Port, saved.CommonAddress = ov.saved.host, ov.saved.port, ov.saved.ca
+	return &saved
+}
+
+func envString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func envInt(key string) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", key, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// clearEnv unsets the environment variables read by ParseOptions
+func clearEnv(t *testing.T) {
+	for _, key := range []string{EnvConfigPath, EnvProfile, EnvHost, EnvPort, EnvCommonAddress, EnvLogLevel,
+		EnvStartConnected, EnvReplay, EnvMetrics, EnvAPI, EnvAPIToken, EnvAPIOrigins, EnvGRPC, EnvGRPCReflection} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want Options
+		err  bool
+	}{
+		{
+			name: "defaults",
+			want: Options{ConfigPath: DefaultPath, LogLevel: "info"},
+		},
+		{
+			name: "environment",
+			env:  map[string]string{EnvConfigPath: "env.json", EnvHost: "10.0.0.1", EnvPort: "2405", EnvCommonAddress: "7", EnvStartConnected: "true"},
+			want: Options{ConfigPath: "env.json", Host: "10.0.0.1", Port: 2405, CommonAddress: 7, LogLevel: "info", StartConnected: true},
+		},
+		{
+			name: "flags override the environment",
+			env:  map[string]string{EnvHost: "10.0.0.1", EnvPort: "2405", EnvLogLevel: "debug", EnvStartConnected: "true"},
+			args: []string{"-host", "10.0.0.2", "-port", "2406", "-log-level", "info", "-connect=false"},
+			want: Options{ConfigPath: DefaultPath, Host: "10.0.0.2", Port: 2406, LogLevel: "info"},
+		},
+		{
+			name: "terminal UI flag with a command",
+			args: []string{"-api", "127.0.0.1:8104", "serve"},
+			err:  true,
+		},
+		{
+			name: "terminal UI environment with a command",
+			env:  map[string]string{EnvAPI: "127.0.0.1:8104", EnvMetrics: ":9104"},
+			args: []string{"-profile", "station", "dump"},
+			want: Options{ConfigPath: DefaultPath, Profile: "station", LogLevel: "info", API: "127.0.0.1:8104", Metrics: ":9104", Args: []string{"dump"}},
+		},
+		{
+			name: "invalid port in the environment",
+			env:  map[string]string{EnvPort: "x"},
+			err:  true,
+		},
+		{
+			name: "invalid boolean in the environment",
+			env:  map[string]string{EnvStartConnected: "maybe"},
+			err:  true,
+		},
+		{
+			name: "invalid log level",
+			args: []string{"-log-level", "trace"},
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			got, err := ParseOptions(tt.args, io.Discard)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("no error, options %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Args) == 0 {
+				got.Args = nil
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("options = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+// testConfig saves a config with two profiles to a temporary file and
+// loads it again
+func testConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg := NewConfig()
+	cfg.path = filepath.Join(t.TempDir(), "config.json")
+	other := NewProfile("other")
+	other.IPAddress, other.Port, other.CommonAddress = "10.0.0.9", 2409, 9
+	cfg.Profiles = append(cfg.Profiles, other)
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(cfg.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want endpoint
+		err  bool
+	}{
+		{"none", Options{}, endpoint{"127.0.0.1", 2404, 1}, false},
+		{"profile", Options{Profile: "other"}, endpoint{"10.0.0.9", 2409, 9}, false},
+		{"host only", Options{Profile: "other", Host: "10.0.0.1"}, endpoint{"10.0.0.1", 2409, 9}, false},
+		{"all", Options{Host: "10.0.0.1", Port: 2405, CommonAddress: 7}, endpoint{"10.0.0.1", 2405, 7}, false},
+		{"unknown profile", Options{Profile: "missing"}, endpoint{}, true},
+	}
+	for _, tt := range tests {
+		cfg := testConfig(t)
+		err := tt.opts.Apply(cfg)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: error %v", tt.name, err)
+			continue
+		}
+		if !tt.err && endpointOf(cfg.Profile) != tt.want {
+			t.Errorf("%s: endpoint %+v, want %+v", tt.name, endpointOf(cfg.Profile), tt.want)
+		}
+	}
+}
+
+func TestSaveKeepsOverridesOut(t *testing.T) {
+	saved := func(t *testing.T, cfg *Config, name string) endpoint {
+		t.Helper()
+		if err := cfg.Save(); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := Load(cfg.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return endpointOf(loaded.FindProfile(name))
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		cfg := testConfig(t)
+		(&Options{Profile: "other", Host: "10.0.0.1", Port: 2405}).Apply(cfg)
+		cfg.InterrogationInterval = 30
+		if got := saved(t, cfg, "other"); got != (endpoint{"10.0.0.9", 2409, 9}) {
+			t.Errorf("saved %+v, want the values from the file", got)
+		}
+		// the running profile keeps the overrides
+		if got := endpointOf(cfg.Profile); got != (endpoint{"10.0.0.1", 2405, 9}) {
+			t.Errorf("profile changed to %+v by saving", got)
+		}
+	})
+
+	t.Run("changed since", func(t *testing.T) {
+		cfg := testConfig(t)
+		(&Options{Host: "10.0.0.1"}).Apply(cfg)
+		cfg.IPAddress = "10.0.0.2"
+		if got := saved(t, cfg, DefaultProfileName); got != (endpoint{"10.0.0.2", 2404, 1}) {
+			t.Errorf("saved %+v, want the values changed after Apply", got)
+		}
+	})
+
+	t.Run("other profiles", func(t *testing.T) {
+		cfg := testConfig(t)
+		(&Options{Port: 2405}).Apply(cfg)
+		cfg.FindProfile("other").Port = 2410
+		if got := saved(t, cfg, "other"); got.port != 2410 {
+			t.Errorf("saved %+v for a profile without overrides", got)
+		}
+	})
+}
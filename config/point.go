@@ -0,0 +1,402 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PointType names the kind of a configured point
+type PointType string
+
+const (
+	PointTelemetry      PointType = "telemetry"
+	PointTeleindication PointType = "teleindication"
+	PointTelecontrol    PointType = "telecontrol"
+	PointTeleregulation PointType = "teleregulation"
+)
+
+// PointTypes lists all point types in display order
+var PointTypes = []PointType{PointTelemetry, PointTeleindication, PointTelecontrol, PointTeleregulation}
+
+// Default information object base addresses for each point type
+const (
+	TelemetryBaseAddress      = 0x4001
+	TeleindBaseAddress        = 0x0001
+	TelecontrolBaseAddress    = 0x6001
+	TeleregulationBaseAddress = 0x6201
+)
+
+// Valid address ranges
+const (
+	MaxInfoObjAddr   = 0xFFFFFF
+	MaxCommonAddress = 0xFFFE
+)
+
+// ParsePointType parses a point type name, case-insensitively
+func ParsePointType(s string) (PointType, error) {
+	t := PointType(strings.ToLower(strings.TrimSpace(s)))
+	for _, v := range PointTypes {
+		if v == t {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown point type %q", s)
+}
+
+// BaseAddress returns the information object address of offset 0
+func (t PointType) BaseAddress() int {
+	switch t {
+	case PointTelemetry:
+		return TelemetryBaseAddress
+	case PointTeleindication:
+		return TeleindBaseAddress
+	case PointTelecontrol:
+		return TelecontrolBaseAddress
+	case PointTeleregulation:
+		return TeleregulationBaseAddress
+	default:
+		return 0
+	}
+}
+
+// Point is the configuration of a single information object
+type Point struct {
+	Address int       `json:"ioa"`
+	Type    PointType `json:"type"`
+	Name    string    `json:"name,omitempty"`
+	Unit    string    `json:"unit,omitempty"`
+	// Scale and Offset convert raw values into engineering values:
+	// value = raw*Scale + Offset. A zero Scale means 1.
+	Scale  float64 `json:"scale,omitempty"`
+	Offset float64 `json:"offset,omitempty"`
+	// Decimals is the number of decimal places shown, nil for DefaultDecimals
+	Decimals *int `json:"decimals,omitempty"`
+	// Min and Max are the expected engineering range, unbounded when equal
+	Min float64 `json:"min,omitempty"`
+	Max float64 `json:"max,omitempty"`
+	// OnText and OffText label the states of digital points, e.g.
+	// CLOSED/OPEN for a breaker; OnColor and OffColor are color names
+	OnText   string `json:"on_text,omitempty"`
+	OffText  string `json:"off_text,omitempty"`
+	OnColor  string `json:"on_color,omitempty"`
+	OffColor string `json:"off_color,omitempty"`
+	// Invert swaps the states of digital points wired with inverted logic
+	Invert bool `json:"invert,omitempty"`
+	// HighHigh, High, Low and LowLow are the alarm limits of telemetry
+	// points in engineering units, nil when unused. An alarm clears once
+	// the value is back inside its limit by more than Deadband.
+	HighHigh *float64 `json:"hh,omitempty"`
+	High     *float64 `json:"h,omitempty"`
+	Low      *float64 `json:"l,omitempty"`
+	LowLow   *float64 `json:"ll,omitempty"`
+	Deadband float64  `json:"deadband,omitempty"`
+	// AlarmState is the state of a digital point that raises an alarm,
+	// AlarmOn or AlarmOff after Invert is applied; empty for none
+	AlarmState string `json:"alarm,omitempty"`
+}
+
+// clone returns a copy of the point that shares none of its optional values
+func (pt Point) clone() Point {
+	pt.Decimals = cloneValue(pt.Decimals)
+	pt.HighHigh = cloneValue(pt.HighHigh)
+	pt.High = cloneValue(pt.High)
+	pt.Low = cloneValue(pt.Low)
+	pt.LowLow = cloneValue(pt.LowLow)
+	return pt
+}
+
+// cloneValue copies the value behind an optional setting
+func cloneValue[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	clone := *v
+	return &clone
+}
+
+// Default labels of digital states
+const (
+	DefaultOnText           = "ON"
+	DefaultOffText          = "OFF"
+	DefaultIntermediateText = "INTER"
+	DefaultFaultyText       = "FAULT"
+)
+
+// Alarm states of digital points
+const (
+	AlarmOn  = "on"
+	AlarmOff = "off"
+)
+
+// ParseAlarmState parses the alarm state of a digital point, case-insensitively
+func ParseAlarmState(s string) (string, error) {
+	switch state := strings.ToLower(strings.TrimSpace(s)); state {
+	case "", AlarmOn, AlarmOff:
+		return state, nil
+	default:
+		return "", fmt.Errorf("invalid alarm state %q, expected on or off", s)
+	}
+}
+
+// DefaultDecimals is the number of decimal places of points without Decimals
+const DefaultDecimals = 2
+
+// Engineering converts a raw value into engineering units.
+// A nil point leaves the value unchanged.
+func (pt *Point) Engineering(raw float64) float64 {
+	if pt == nil {
+		return raw
+	}
+	scale := pt.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	return raw*scale + pt.Offset
+}
+
+// Raw converts an engineering value back into the raw value sent on the wire
+func (pt *Point) Raw(value float64) float64 {
+	if pt == nil {
+		return value
+	}
+	scale := pt.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	return (value - pt.Offset) / scale
+}
+
+// Precision returns the number of decimal places to display
+func (pt *Point) Precision() int {
+	if pt == nil || pt.Decimals == nil || *pt.Decimals < 0 {
+		return DefaultDecimals
+	}
+	return *pt.Decimals
+}
+
+// HasRange reports whether Min and Max bound the display range
+func (pt *Point) HasRange() bool {
+	return pt != nil && pt.Min != pt.Max
+}
+
+// InRange reports whether an engineering value lies inside Min and Max
+func (pt *Point) InRange(value float64) bool {
+	if !pt.HasRange() {
+		return true
+	}
+	return value >= pt.Min && value <= pt.Max
+}
+
+// HasLimits reports whether any alarm limit is set
+func (pt *Point) HasLimits() bool {
+	return pt != nil && (pt.HighHigh != nil || pt.High != nil || pt.Low != nil || pt.LowLow != nil)
+}
+
+// IsAlarmState reports whether a digital state raises an alarm
+func (pt *Point) IsAlarmState(on bool) bool {
+	if pt == nil || pt.AlarmState == "" {
+		return false
+	}
+	if pt.Invert {
+		on = !on
+	}
+	return on == (pt.AlarmState == AlarmOn)
+}
+
+// ValidateLimits checks that the alarm limits are ordered LL <= L <= H <= HH
+func (pt *Point) ValidateLimits() error {
+	var last *float64
+	var lastName string
+	for _, limit := range []struct {
+		name  string
+		value *float64
+	}{{"ll", pt.LowLow}, {"l", pt.Low}, {"h", pt.High}, {"hh", pt.HighHigh}} {
+		if limit.value == nil {
+			continue
+		}
+		if last != nil && *limit.value < *last {
+			return fmt.Errorf("limit %s %g is below %s %g", limit.name, *limit.value, lastName, *last)
+		}
+		last, lastName = limit.value, limit.name
+	}
+	if pt.Deadband < 0 {
+		return fmt.Errorf("negative deadband %g", pt.Deadband)
+	}
+	return nil
+}
+
+// FormatValue formats an engineering value with the point's precision and unit
+func (pt *Point) FormatValue(value float64) string {
+	s := strconv.FormatFloat(value, 'f', pt.Precision(), 64)
+	if pt != nil && pt.Unit != "" {
+		s += " " + pt.Unit
+	}
+	return s
+}
+
+// StateLabel returns the text and color shown for a digital state.
+// The color is empty unless configured.
+func (pt *Point) StateLabel(on bool) (string, string) {
+	if pt == nil {
+		pt = &Point{}
+	}
+	if pt.Invert {
+		on = !on
+	}
+	if on {
+		return orDefault(pt.OnText, DefaultOnText), pt.OnColor
+	}
+	return orDefault(pt.OffText, DefaultOffText), pt.OffColor
+}
+
+// DoubleStateLabel returns the text and color shown for a double point
+// state: 0 intermediate, 1 off, 2 on and 3 faulty
+func (pt *Point) DoubleStateLabel(state int) (string, string) {
+	switch state {
+	case 1:
+		return pt.StateLabel(false)
+	case 2:
+		return pt.StateLabel(true)
+	case 0:
+		return DefaultIntermediateText, "yellow"
+	default:
+		return DefaultFaultyText, "red"
+	}
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// pointKey identifies a configured point
+type pointKey struct {
+	typ PointType
+	ioa int
+}
+
+// pointIndex finds points by type and address. It holds copies of the
+// points that are replaced, never changed, when the list changes, so the
+// network goroutine can look points up while the UI edits the list.
+type pointIndex struct {
+	mu     sync.RWMutex
+	points map[pointKey]*Point
+}
+
+// reindex rebuilds the index after the point list changed
+func (p *Profile) reindex() {
+	points := make(map[pointKey]*Point, len(p.Points))
+	for _, point := range p.Points {
+		point := point
+		points[pointKey{point.Type, point.Address}] = &point
+	}
+	if p.index == nil {
+		p.index = &pointIndex{}
+	}
+	p.index.mu.Lock()
+	p.index.points = points
+	p.index.mu.Unlock()
+}
+
+// FindPoint returns the configured point of the given type and address, or
+// nil. The point must not be modified; use SetPoint to change it.
+func (p *Profile) FindPoint(typ PointType, ioa int) *Point {
+	if p.index == nil {
+		// profiles not made by NewProfile or Load have no index
+		if i := p.position(typ, ioa); i >= 0 {
+			return &p.Points[i]
+		}
+		return nil
+	}
+	p.index.mu.RLock()
+	defer p.index.mu.RUnlock()
+	return p.index.points[pointKey{typ, ioa}]
+}
+
+// position returns the index of a point in Points, or -1
+func (p *Profile) position(typ PointType, ioa int) int {
+	for i := range p.Points {
+		if p.Points[i].Type == typ && p.Points[i].Address == ioa {
+			return i
+		}
+	}
+	return -1
+}
+
+// PointName returns the configured name of a point, or an empty string
+func (p *Profile) PointName(typ PointType, ioa int) string {
+	if point := p.FindPoint(typ, ioa); point != nil {
+		return point.Name
+	}
+	return ""
+}
+
+// SetPointName names a point, adding it to the configuration if needed
+func (p *Profile) SetPointName(typ PointType, ioa int, name string) {
+	point := Point{Address: ioa, Type: typ}
+	if i := p.position(typ, ioa); i >= 0 {
+		point = p.Points[i]
+	}
+	point.Name = name
+	p.SetPoint(point)
+}
+
+// SetPoint replaces the configured point of the same type and address, or
+// adds it
+func (p *Profile) SetPoint(point Point) {
+	if i := p.position(point.Type, point.Address); i >= 0 {
+		p.Points[i] = point
+	} else {
+		p.Points = append(p.Points, point)
+	}
+	p.sortPoints()
+}
+
+// PointsOf returns the configured points of one type
+func (p *Profile) PointsOf(typ PointType) []Point {
+	var points []Point
+	for _, point := range p.Points {
+		if point.Type == typ {
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+// sortPoints orders points by type and address and rebuilds the index
+func (p *Profile) sortPoints() {
+	order := make(map[PointType]int, len(PointTypes))
+	for i, t := range PointTypes {
+		order[t] = i
+	}
+	sort.SliceStable(p.Points, func(i, j int) bool {
+		if p.Points[i].Type != p.Points[j].Type {
+			return order[p.Points[i].Type] < order[p.Points[j].Type]
+		}
+		return p.Points[i].Address < p.Points[j].Address
+	})
+	p.reindex()
+}
+
+// migrateDescriptions converts the offset-keyed description maps of older
+// config files into points
+func (p *Profile) migrateDescriptions() {
+	for offset, desc := range p.TelemetryDescriptions {
+		if p.position(PointTelemetry, TelemetryBaseAddress+offset) < 0 {
+			p.Points = append(p.Points, Point{Address: TelemetryBaseAddress + offset, Type: PointTelemetry, Name: desc})
+		}
+	}
+	for offset, desc := range p.TeleindDescriptions {
+		if p.position(PointTeleindication, TeleindBaseAddress+offset) < 0 {
+			p.Points = append(p.Points, Point{Address: TeleindBaseAddress + offset, Type: PointTeleindication, Name: desc})
+		}
+	}
+	p.TelemetryDescriptions = nil
+	p.TeleindDescriptions = nil
+	p.sortPoints()
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEngineering(t *testing.T) {
+	tests := []struct {
+		name  string
+		point *Point
+		raw   float64
+		want  float64
+	}{
+		{"nil point", nil, 123.5, 123.5},
+		{"zero scale means 1", &Point{}, 42, 42},
+		{"offset only", &Point{Offset: -10}, 42, 32},
+		{"scale", &Point{Scale: 0.1}, 1234, 123.4},
+		{"scale and offset", &Point{Scale: 0.5, Offset: 20}, 100, 70},
+		{"negative scale", &Point{Scale: -2, Offset: 1}, 3, -5},
+		{"not clamped to range", &Point{Scale: 0.1, Min: 0, Max: 100}, 5000, 500},
+	}
+	for _, tt := range tests {
+		got := tt.point.Engineering(tt.raw)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Engineering(%g) = %g, want %g", tt.name, tt.raw, got, tt.want)
+		}
+		// Raw reverses Engineering for every point
+		if back := tt.point.Raw(got); math.Abs(back-tt.raw) > 1e-9 {
+			t.Errorf("%s: Raw(%g) = %g, want %g", tt.name, got, back, tt.raw)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		point    *Point
+		hasRange bool
+		in       []float64
+		out      []float64
+	}{
+		{"nil point", nil, false, []float64{-1e9, 0, 1e9}, nil},
+		{"zero span at zero", &Point{}, false, []float64{-1e9, 0, 1e9}, nil},
+		{"zero span elsewhere", &Point{Min: 50, Max: 50}, false, []float64{0, 50, 100}, nil},
+		{"bounds inclusive", &Point{Min: 0, Max: 100}, true, []float64{0, 50, 100}, []float64{-0.01, 100.01}},
+		{"negative range", &Point{Min: -20, Max: -10}, true, []float64{-20, -15, -10}, []float64{-25, 0}},
+	}
+	for _, tt := range tests {
+		if got := tt.point.HasRange(); got != tt.hasRange {
+			t.Errorf("%s: HasRange = %v, want %v", tt.name, got, tt.hasRange)
+		}
+		for _, v := range tt.in {
+			if !tt.point.InRange(v) {
+				t.Errorf("%s: InRange(%g) = false", tt.name, v)
+			}
+		}
+		for _, v := range tt.out {
+			if tt.point.InRange(v) {
+				t.Errorf("%s: InRange(%g) = true", tt.name, v)
+			}
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	decimals := func(n int) *int { return &n }
+	tests := []struct {
+		point *Point
+		value float64
+		want  string
+	}{
+		{nil, 1.005, "1.00"},
+		{&Point{Unit: "kV"}, 110.256, "110.26 kV"},
+		{&Point{Decimals: decimals(0), Unit: "A"}, 12.6, "13 A"},
+		{&Point{Decimals: decimals(3)}, 0.5, "0.500"},
+		{&Point{Decimals: decimals(-1)}, 0.5, "0.50"},
+	}
+	for _, tt := range tests {
+		if got := tt.point.FormatValue(tt.value); got != tt.want {
+			t.Errorf("FormatValue(%g) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestStateLabel(t *testing.T) {
+	breaker := &Point{OnText: "CLOSED", OffText: "OPEN", OnColor: "red", OffColor: "green"}
+	inverted := *breaker
+	inverted.Invert = true
+	tests := []struct {
+		name  string
+		point *Point
+		state int
+		text  string
+		color string
+	}{
+		{"nil point off", nil, 1, DefaultOffText, ""},
+		{"nil point on", nil, 2, DefaultOnText, ""},
+		{"defaults", &Point{OnColor: "red"}, 2, DefaultOnText, "red"},
+		{"only on text", &Point{OnText: "RUN"}, 1, DefaultOffText, ""},
+		{"off", breaker, 1, "OPEN", "green"},
+		{"on", breaker, 2, "CLOSED", "red"},
+		{"inverted off", &inverted, 1, "CLOSED", "red"},
+		{"inverted on", &inverted, 2, "OPEN", "green"},
+		{"intermediate", breaker, 0, DefaultIntermediateText, "yellow"},
+		{"faulty", breaker, 3, DefaultFaultyText, "red"},
+		{"inverted intermediate", &inverted, 0, DefaultIntermediateText, "yellow"},
+	}
+	for _, tt := range tests {
+		text, color := tt.point.DoubleStateLabel(tt.state)
+		if text != tt.text || color != tt.color {
+			t.Errorf("%s: DoubleStateLabel(%d) = %q, %q, want %q, %q", tt.name, tt.state, text, color, tt.text, tt.color)
+		}
+		// single points use the off and on labels of double points
+		if tt.state == 1 || tt.state == 2 {
+			text, color = tt.point.StateLabel(tt.state == 2)
+			if text != tt.text || color != tt.color {
+				t.Errorf("%s: StateLabel(%v) = %q, %q, want %q, %q", tt.name, tt.state == 2, text, color, tt.text, tt.color)
+			}
+		}
+	}
+}
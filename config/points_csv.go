@@ -0,0 +1,376 @@
+package config
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// utf8BOM is the byte order mark some editors put before UTF-8 text
+const utf8BOM = "\ufeff"
+
+// pointColumns is the column order of exported point lists
+var pointColumns = []string{"ioa", "type", "name", "unit", "scale", "offset", "decimals", "min", "max", "on_text", "off_text", "on_color", "off_color", "invert", "ll", "l", "h", "hh", "deadband", "alarm"}
+
+// PointIssue is a problem found in an imported point list
+type PointIssue struct {
+	Line    int
+	Error   bool
+	Message string
+}
+
+func (i PointIssue) String() string {
+	level := "warning"
+	if i.Error {
+		level = "error"
+	}
+	return fmt.Sprintf("line %d: %s: %s", i.Line, level, i.Message)
+}
+
+// PointReport is the validation report of an imported point list
+type PointReport struct {
+	Points int
+	Issues []PointIssue
+}
+
+// HasErrors reports whether any issue prevents the import
+func (r *PointReport) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Error {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *PointReport) String() string {
+	var errs, warns int
+	for _, issue := range r.Issues {
+		if issue.Error {
+			errs++
+		} else {
+			warns++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d points, %d errors, %d warnings\n", r.Points, errs, warns)
+	for _, issue := range r.Issues {
+		b.WriteString(issue.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func (r *PointReport) errorf(line int, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, PointIssue{Line: line, Error: true, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *PointReport) warnf(line int, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, PointIssue{Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// ExportPointsCSV writes the profile's point list as CSV
+func (p *Profile) ExportPointsCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(pointColumns); err != nil {
+		return err
+	}
+	for _, point := range p.Points {
+		record := []string{
+			strconv.Itoa(point.Address),
+			string(point.Type),
+			point.Name,
+			point.Unit,
+			formatOptionalFloat(point.Scale),
+			formatOptionalFloat(point.Offset),
+			formatDecimals(point.Decimals),
+			formatOptionalFloat(point.Min),
+			formatOptionalFloat(point.Max),
+			point.OnText,
+			point.OffText,
+			point.OnColor,
+			point.OffColor,
+			formatOptionalBool(point.Invert),
+			formatLimit(point.LowLow),
+			formatLimit(point.Low),
+			formatLimit(point.High),
+			formatLimit(point.HighHigh),
+			formatOptionalFloat(point.Deadband),
+			point.AlarmState,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadPointsCSV parses and validates a point list against the profile.
+// Both comma and semicolon separated files are accepted; a header row is
+// optional and allows the columns to appear in any order.
+func (p *Profile) ReadPointsCSV(r io.Reader) ([]Point, *PointReport, error) {
+	br := bufio.NewReader(r)
+	// spreadsheets often start UTF-8 files with a byte order mark
+	if bom, _ := br.Peek(len(utf8BOM)); string(bom) == utf8BOM {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if first, _ := br.Peek(br.Size()); detectSemicolon(first) {
+		reader.Comma = ';'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int, len(pointColumns))
+	for i, name := range pointColumns {
+		columns[name] = i
+	}
+	start := 0
+	if len(records) > 0 && isHeader(records[0]) {
+		columns = make(map[string]int)
+		for i, name := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		start = 1
+	}
+	if _, ok := columns["ioa"]; !ok {
+		return nil, nil, fmt.Errorf("missing ioa column")
+	}
+	if _, ok := columns["type"]; !ok {
+		return nil, nil, fmt.Errorf("missing type column")
+	}
+
+	report := &PointReport{}
+	points := make([]Point, 0, len(records))
+	lines := make([]int, 0, len(records))
+	for i := start; i < len(records); i++ {
+		line := i + 1
+		record := records[i]
+		field := func(name string) string {
+			if col, ok := columns[name]; ok && col < len(record) {
+				return strings.TrimSpace(record[col])
+			}
+			return ""
+		}
+		if len(record) == 1 && field("ioa") == "" {
+			continue
+		}
+
+		var point Point
+		ioa, err := strconv.ParseInt(field("ioa"), 0, 64)
+		if err != nil {
+			report.errorf(line, "invalid ioa %q", field("ioa"))
+			continue
+		}
+		point.Address = int(ioa)
+		if point.Type, err = ParsePointType(field("type")); err != nil {
+			report.errorf(line, "%v", err)
+			continue
+		}
+		point.Name = field("name")
+		point.Unit = field("unit")
+		if !parseOptionalFloat(field, "scale", &point.Scale, line, report) ||
+			!parseOptionalFloat(field, "offset", &point.Offset, line, report) ||
+			!parseOptionalFloat(field, "min", &point.Min, line, report) ||
+			!parseOptionalFloat(field, "max", &point.Max, line, report) ||
+			!parseOptionalFloat(field, "deadband", &point.Deadband, line, report) ||
+			!parseLimit(field, "ll", &point.LowLow, line, report) ||
+			!parseLimit(field, "l", &point.Low, line, report) ||
+			!parseLimit(field, "h", &point.High, line, report) ||
+			!parseLimit(field, "hh", &point.HighHigh, line, report) {
+			continue
+		}
+		if point.AlarmState, err = ParseAlarmState(field("alarm")); err != nil {
+			report.errorf(line, "%v", err)
+			continue
+		}
+		if s := field("decimals"); s != "" {
+			decimals, err := strconv.Atoi(s)
+			if err != nil || decimals < 0 {
+				report.errorf(line, "invalid decimals %q", s)
+				continue
+			}
+			point.Decimals = &decimals
+		}
+		point.OnText = field("on_text")
+		point.OffText = field("off_text")
+		point.OnColor = field("on_color")
+		point.OffColor = field("off_color")
+		if s := field("invert"); s != "" {
+			if point.Invert, err = strconv.ParseBool(s); err != nil {
+				report.errorf(line, "invalid invert %q", s)
+				continue
+			}
+		}
+		if s := field("ca"); s != "" {
+			ca, err := strconv.Atoi(s)
+			if err != nil {
+				report.errorf(line, "invalid ca %q", s)
+				continue
+			}
+			if ca != p.CommonAddress {
+				report.errorf(line, "ca %d differs from the profile's common address %d", ca, p.CommonAddress)
+				continue
+			}
+		}
+
+		points = append(points, point)
+		lines = append(lines, line)
+	}
+
+	p.validatePoints(points, lines, report)
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return report.Issues[i].Line < report.Issues[j].Line
+	})
+	report.Points = len(points)
+	return points, report, nil
+}
+
+// validatePoints reports duplicate and out-of-range addresses
+func (p *Profile) validatePoints(points []Point, lines []int, report *PointReport) {
+	seen := make(map[int]int, len(points))
+
+	for i, point := range points {
+		line := lines[i]
+
+		if point.Address < 1 || point.Address > MaxInfoObjAddr {
+			report.errorf(line, "ioa %d out of range 1-%d", point.Address, MaxInfoObjAddr)
+		}
+		if first, ok := seen[point.Address]; ok {
+			report.errorf(line, "duplicate ioa %d, first defined on line %d", point.Address, first)
+		} else {
+			seen[point.Address] = line
+		}
+
+		switch point.Type {
+		case PointTelemetry:
+			if offset := point.Address - TelemetryBaseAddress; offset < 0 || offset >= p.TelemetryCount {
+				report.warnf(line, "telemetry ioa %d outside the configured range %d-%d", point.Address, TelemetryBaseAddress, TelemetryBaseAddress+p.TelemetryCount-1)
+			}
+		case PointTeleindication:
+			if offset := point.Address - TeleindBaseAddress; offset < 0 || offset >= p.TeleindCount {
+				report.warnf(line, "teleindication ioa %d outside the configured range %d-%d", point.Address, TeleindBaseAddress, TeleindBaseAddress+p.TeleindCount-1)
+			}
+		}
+		if point.Min > point.Max {
+			report.errorf(line, "min %g is greater than max %g", point.Min, point.Max)
+		}
+		if err := point.ValidateLimits(); err != nil {
+			report.errorf(line, "%v", err)
+		}
+		if point.HasLimits() && point.Type != PointTelemetry {
+			report.warnf(line, "alarm limits are only evaluated for telemetry points")
+		}
+		if point.AlarmState != "" && point.Type != PointTeleindication {
+			report.warnf(line, "alarm states are only evaluated for teleindication points")
+		}
+		if point.Name == "" {
+			report.warnf(line, "%s ioa %d has no name", point.Type, point.Address)
+		}
+	}
+}
+
+// ReplacePoints sets the point list, or merges it into the existing one
+// when merge is set, overwriting points with the same type and address
+func (p *Profile) ReplacePoints(points []Point, merge bool) {
+	if !merge {
+		p.Points = append([]Point(nil), points...)
+		p.sortPoints()
+		return
+	}
+	for _, point := range points {
+		if i := p.position(point.Type, point.Address); i >= 0 {
+			p.Points[i] = point
+		} else {
+			p.Points = append(p.Points, point)
+		}
+	}
+	p.sortPoints()
+}
+
+// isHeader reports whether a record names the columns, i.e. has an ioa column
+func isHeader(record []string) bool {
+	for _, name := range record {
+		if strings.EqualFold(strings.TrimSpace(name), "ioa") {
+			return true
+		}
+	}
+	return false
+}
+
+// detectSemicolon guesses whether the first line uses ';' as separator
+func detectSemicolon(data []byte) bool {
+	line := string(data)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.Count(line, ";") > strings.Count(line, ",")
+}
+
+// parseOptionalFloat parses an optional numeric column into v, reporting
+// an error and returning false if it is malformed
+func parseOptionalFloat(field func(string) string, name string, v *float64, line int, report *PointReport) bool {
+	s := field(name)
+	if s == "" {
+		return true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		report.errorf(line, "invalid %s %q", name, s)
+		return false
+	}
+	*v = f
+	return true
+}
+
+// parseLimit parses an optional alarm limit column into v
+func parseLimit(field func(string) string, name string, v **float64, line int, report *PointReport) bool {
+	if field(name) == "" {
+		return true
+	}
+	var f float64
+	if !parseOptionalFloat(field, name, &f, line, report) {
+		return false
+	}
+	*v = &f
+	return true
+}
+
+func formatLimit(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'g', -1, 64)
+}
+
+func formatDecimals(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func formatOptionalFloat(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func formatOptionalBool(v bool) string {
+	if !v {
+		return ""
+	}
+	return "true"
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadPointsCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		csv    string
+		points int
+		errors []string
+		warns  []string
+	}{
+		{
+			name:   "header",
+			csv:    "ioa,type,name,unit\n16385,telemetry,Voltage,kV\n1,teleindication,Breaker,\n",
+			points: 2,
+		},
+		{
+			name:   "columns in any order",
+			csv:    "name,type,ioa\nVoltage,Telemetry,16385\n",
+			points: 1,
+		},
+		{
+			name:   "without header",
+			csv:    "16385,telemetry,Voltage\n",
+			points: 1,
+		},
+		{
+			name:   "semicolons",
+			csv:    "ioa;type;name;scale\n16385;telemetry;Voltage;0,1\n",
+			points: 0,
+			errors: []string{"line 2: error: invalid scale"},
+		},
+		{
+			name:   "byte order mark",
+			csv:    "\ufeffioa,type,name\n16385,telemetry,Voltage\n",
+			points: 1,
+		},
+		{
+			name:   "hex address",
+			csv:    "ioa,type,name\n0x4001,telemetry,Voltage\n",
+			points: 1,
+		},
+		{
+			name:   "blank lines",
+			csv:    "ioa,type,name\n\n16385,telemetry,Voltage\n\n",
+			points: 1,
+		},
+		{
+			name:   "duplicate",
+			csv:    "ioa,type,name\n16385,telemetry,A\n16385,telemetry,B\n",
+			points: 2,
+			errors: []string{"line 3: error: duplicate ioa 16385, first defined on line 2"},
+		},
+		{
+			name:   "invalid fields",
+			csv:    "ioa,type,name,decimals,invert,alarm\nx,telemetry,A,,,\n2,switch,B,,,\n3,teleindication,C,-1,,\n4,teleindication,D,,maybe,\n5,teleindication,E,,,high\n",
+			errors: []string{"invalid ioa", "unknown point type", "invalid decimals", "invalid invert", "invalid alarm state"},
+		},
+		{
+			name:   "out of range",
+			csv:    "ioa,type,name\n0,telecontrol,A\n16777216,telecontrol,B\n",
+			points: 2,
+			errors: []string{"ioa 0 out of range", "ioa 16777216 out of range"},
+		},
+		{
+			name:   "limits",
+			csv:    "ioa,type,name,min,max,l,h\n16385,telemetry,A,10,0,,\n16386,telemetry,B,,,5,1\n",
+			points: 2,
+			errors: []string{"min 10 is greater than max 0", "limit h 1 is below l 5"},
+		},
+		{
+			name:   "common address",
+			csv:    "ioa,type,name,ca\n16385,telemetry,A,1\n16386,telemetry,B,7\n16387,telemetry,C,x\n16388,telemetry,D,\n",
+			points: 2,
+			errors: []string{"line 3: error: ca 7 differs from the profile's common address 1", "line 4: error: invalid ca"},
+		},
+		{
+			name:   "warnings",
+			csv:    "ioa,type,name,h,alarm\n16485,telemetry,A,,\n1,teleindication,,5,\n16386,telemetry,C,,on\n",
+			points: 3,
+			warns:  []string{"outside the configured range", "has no name", "only evaluated for telemetry", "only evaluated for teleindication"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProfile("test")
+			points, report, err := p.ReadPointsCSV(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(points) != tt.points || report.Points != tt.points {
+				t.Errorf("got %d points, report %d, want %d", len(points), report.Points, tt.points)
+			}
+			if report.HasErrors() != (len(tt.errors) > 0) {
+				t.Errorf("HasErrors = %v:\n%s", report.HasErrors(), report)
+			}
+			text := report.String()
+			for _, want := range append(tt.errors, tt.warns...) {
+				if !strings.Contains(text, want) {
+					t.Errorf("report lacks %q:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestReadPointsCSVColumns(t *testing.T) {
+	p := NewProfile("test")
+	if _, _, err := p.ReadPointsCSV(strings.NewReader("ioa,name\n1,A\n")); err == nil {
+		t.Error("a list without type column was accepted")
+	}
+	if _, _, err := p.ReadPointsCSV(strings.NewReader("ioa,type\n1,\"telemetry\n")); err == nil {
+		t.Error("malformed CSV was accepted")
+	}
+}
+
+func TestPointsCSVRoundTrip(t *testing.T) {
+	low, high := 1.5, 9.0
+	decimals := 1
+	p := NewProfile("test")
+	p.ReplacePoints([]Point{
+		{Address: 16385, Type: PointTelemetry, Name: "Voltage, L1", Unit: "kV", Scale: 0.1, Offset: -2, Decimals: &decimals, Min: 0, Max: 20, Low: &low, High: &high, Deadband: 0.5},
+		{Address: 1, Type: PointTeleindication, Name: "Breaker", OnText: "closed", OffText: "open", Invert: true, AlarmState: AlarmOff},
+	}, false)
+
+	var buf bytes.Buffer
+	if err := p.ExportPointsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	points, report, err := NewProfile("other").ReadPointsCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.HasErrors() || len(points) != 2 {
+		t.Fatalf("re-import of the export: %d points\n%s", len(points), report)
+	}
+
+	other := NewProfile("other")
+	other.ReplacePoints(points, false)
+	for _, want := range p.Points {
+		got := other.FindPoint(want.Type, want.Address)
+		if got == nil {
+			t.Fatalf("%s %d missing", want.Type, want.Address)
+		}
+		if got.Name != want.Name || got.Scale != want.Scale || got.Offset != want.Offset ||
+			got.Invert != want.Invert || got.AlarmState != want.AlarmState || got.Deadband != want.Deadband {
+			t.Errorf("%s %d = %+v, want %+v", want.Type, want.Address, *got, want)
+		}
+		if (got.Low == nil) != (want.Low == nil) || (got.Decimals == nil) != (want.Decimals == nil) {
+			t.Errorf("%s %d optional fields differ: %+v", want.Type, want.Address, *got)
+		}
+	}
+}
+
+func TestReplacePointsMerge(t *testing.T) {
+	p := NewProfile("test")
+	p.ReplacePoints([]Point{
+		{Address: 16385, Type: PointTelemetry, Name: "A"},
+		{Address: 1, Type: PointTeleindication, Name: "B"},
+	}, false)
+	p.ReplacePoints([]Point{
+		{Address: 16385, Type: PointTelemetry, Name: "A2"},
+		{Address: 2, Type: PointTeleindication, Name: "C"},
+	}, true)
+
+	if len(p.Points) != 3 {
+		t.Fatalf("merged points = %+v", p.Points)
+	}
+	if name := p.PointName(PointTelemetry, 16385); name != "A2" {
+		t.Errorf("merged name = %q, want A2", name)
+	}
+	if name := p.PointName(PointTeleindication, 1); name != "B" {
+		t.Errorf("kept name = %q, want B", name)
+	}
+
+	p.ReplacePoints([]Point{{Address: 2, Type: PointTeleindication, Name: "C"}}, false)
+	if len(p.Points) != 1 || p.FindPoint(PointTelemetry, 16385) != nil {
+		t.Errorf("replaced points = %+v", p.Points)
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Profile describes the connection to a single server and its points
+type Profile struct {
+	Name                  string
+	IPAddress             string
+	Port                  int
+	CommonAddress         int
+	TelemetryCount        int
+	TeleindCount          int
+	InterrogationInterval int  // in seconds
+	AlarmBell             bool // ring the terminal bell when an alarm is raised
+
+	// ExtraCommonAddresses are further stations behind the same link.
+	// Their updates only reach subscribers; the points, alarms and
+	// commands are those of CommonAddress.
+	ExtraCommonAddresses []int `json:"extra_common_addresses,omitempty"`
+
+	Points []Point `json:"points"`
+	// index finds Points for the network goroutine; change Points only
+	// through the methods of Profile, which keep it up to date
+	index *pointIndex
+
+	// Deprecated: descriptions are stored in Points. These are only read
+	// from older config files and migrated on load.
+	TelemetryDescriptions map[int]string `json:"telemetry_descriptions,omitempty"`
+	TeleindDescriptions   map[int]string `json:"teleind_descriptions,omitempty"`
+}
+
+// NewProfile creates a profile with default values
+func NewProfile(name string) *Profile {
+	p := &Profile{
+		Name:                  name,
+		IPAddress:             "127.0.0.1",
+		Port:                  2404,
+		CommonAddress:         1,
+		TelemetryCount:        100,
+		TeleindCount:          100,
+		InterrogationInterval: 15,
+	}
+	p.reindex()
+	return p
+}
+
+// Clone returns a deep copy of the profile under a new name
+func (p *Profile) Clone(name string) *Profile {
+	clone := *p
+	clone.Name = name
+	clone.ExtraCommonAddresses = append([]int(nil), p.ExtraCommonAddresses...)
+	clone.Points = make([]Point, len(p.Points))
+	for i, point := range p.Points {
+		clone.Points[i] = point.clone()
+	}
+	clone.TelemetryDescriptions = cloneDescriptions(p.TelemetryDescriptions)
+	clone.TeleindDescriptions = cloneDescriptions(p.TeleindDescriptions)
+	clone.index = nil
+	clone.reindex()
+	return &clone
+}
+
+// cloneDescriptions copies a deprecated description map
+func cloneDescriptions(m map[int]string) map[int]string {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[int]string, len(m))
+	for ioa, description := range m {
+		clone[ioa] = description
+	}
+	return clone
+}
+
+// AcceptsCommonAddress reports whether ASDUs with the common address are
+// taken from the link
+func (p *Profile) AcceptsCommonAddress(ca int) bool {
+	if ca == p.CommonAddress {
+		return true
+	}
+	for _, extra := range p.ExtraCommonAddresses {
+		if extra == ca {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize upgrades settings read from older config files
+func (p *Profile) normalize() {
+	p.migrateDescriptions()
+}
+
+// ProfileNames returns the names of all profiles in file order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for _, p := range c.Profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// FindProfile returns the profile with the given name, or nil
+func (c *Config) FindProfile(name string) *Profile {
+	for _, p := range c.Profiles {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Select makes the named profile the active one
+func (c *Config) Select(name string) error {
+	p := c.FindProfile(name)
+	if p == nil {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	c.Profile = p
+	c.ActiveProfile = p.Name
+	return nil
+}
+
+// CloneProfile copies the named profile to a new one
+func (c *Config) CloneProfile(name, newName string) error {
+	p := c.FindProfile(name)
+	if p == nil {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	if err := c.checkNewName(newName); err != nil {
+		return err
+	}
+	c.Profiles = append(c.Profiles, p.Clone(newName))
+	return nil
+}
+
+// RenameProfile changes the name of a profile
+func (c *Config) RenameProfile(name, newName string) error {
+	p := c.FindProfile(name)
+	if p == nil {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	if name == newName {
+		return nil
+	}
+	if err := c.checkNewName(newName); err != nil {
+		return err
+	}
+	p.Name = newName
+	if c.ActiveProfile == name {
+		c.ActiveProfile = newName
+	}
+	return nil
+}
+
+// DeleteProfile removes a profile. The last profile cannot be deleted;
+// deleting the active profile selects the first remaining one.
+func (c *Config) DeleteProfile(name string) error {
+	if len(c.Profiles) <= 1 {
+		return fmt.Errorf("cannot delete the last profile")
+	}
+	for i, p := range c.Profiles {
+		if p.Name != name {
+			continue
+		}
+		c.Profiles = append(c.Profiles[:i], c.Profiles[i+1:]...)
+		if c.ActiveProfile == name {
+			return c.Select(c.Profiles[0].Name)
+		}
+		return nil
+	}
+	return fmt.Errorf("profile %q not found", name)
+}
+
+func (c *Config) checkNewName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("profile name must not be empty")
+	}
+	if c.FindProfile(name) != nil {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	return nil
+}
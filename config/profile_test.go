@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	decimals, high := 1, 90.0
+	p := NewProfile("original")
+	p.SetPoint(Point{Address: 16385, Type: PointTelemetry, Name: "Voltage", Decimals: &decimals, High: &high})
+	p.TelemetryDescriptions = map[int]string{16385: "Voltage"}
+
+	clone := p.Clone("copy")
+	if clone.Name != "copy" || p.Name != "original" {
+		t.Fatalf("names %q and %q", p.Name, clone.Name)
+	}
+	point := clone.FindPoint(PointTelemetry, 16385)
+	if point == nil {
+		t.Fatal("cloned point not found")
+	}
+
+	// changing the clone leaves the original alone
+	*point.Decimals = 3
+	*point.High = 100
+	point.Low = &high
+	clone.SetPointName(PointTelemetry, 16385, "Current")
+	clone.SetPoint(Point{Address: 16386, Type: PointTelemetry})
+	clone.TelemetryDescriptions[16385] = "Current"
+
+	orig := p.FindPoint(PointTelemetry, 16385)
+	if *orig.Decimals != 1 || *orig.High != 90 || orig.Low != nil {
+		t.Errorf("original limits changed: decimals %d, h %g, l %v", *orig.Decimals, *orig.High, orig.Low)
+	}
+	if orig.Name != "Voltage" || len(p.Points) != 1 {
+		t.Errorf("original points changed: %+v", p.Points)
+	}
+	if p.TelemetryDescriptions[16385] != "Voltage" {
+		t.Errorf("original descriptions changed: %v", p.TelemetryDescriptions)
+	}
+	if p.FindPoint(PointTelemetry, 16386) != nil {
+		t.Error("point added to the clone is found in the original")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	cfg := NewConfig()
+	steps := []struct {
+		name   string
+		do     func() error
+		err    bool
+		names  string
+		active string
+	}{
+		{"clone", func() error { return cfg.CloneProfile(DefaultProfileName, "backup") }, false, "default,backup", "default"},
+		{"clone to existing name", func() error { return cfg.CloneProfile(DefaultProfileName, "backup") }, true, "default,backup", "default"},
+		{"clone to empty name", func() error { return cfg.CloneProfile(DefaultProfileName, " ") }, true, "default,backup", "default"},
+		{"clone missing", func() error { return cfg.CloneProfile("missing", "x") }, true, "default,backup", "default"},
+		{"select", func() error { return cfg.Select("backup") }, false, "default,backup", "backup"},
+		{"select missing", func() error { return cfg.Select("missing") }, true, "default,backup", "backup"},
+		{"rename active", func() error { return cfg.RenameProfile("backup", "station") }, false, "default,station", "station"},
+		{"rename to itself", func() error { return cfg.RenameProfile("station", "station") }, false, "default,station", "station"},
+		{"rename to existing name", func() error { return cfg.RenameProfile("station", DefaultProfileName) }, true, "default,station", "station"},
+		{"delete active", func() error { return cfg.DeleteProfile("station") }, false, "default", "default"},
+		{"delete last", func() error { return cfg.DeleteProfile(DefaultProfileName) }, true, "default", "default"},
+	}
+	for _, step := range steps {
+		if err := step.do(); (err != nil) != step.err {
+			t.Errorf("%s: error %v", step.name, err)
+		}
+		if names := strings.Join(cfg.ProfileNames(), ","); names != step.names {
+			t.Errorf("%s: profiles %s, want %s", step.name, names, step.names)
+		}
+		if cfg.ActiveProfile != step.active || cfg.Profile.Name != step.active {
+			t.Errorf("%s: active %s (%s), want %s", step.name, cfg.ActiveProfile, cfg.Profile.Name, step.active)
+		}
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		names  string
+		active string
+		host   string
+	}{
+		{
+			name:   "profiles",
+			json:   `{"active_profile":"b","profiles":[{"Name":"a","IPAddress":"10.0.0.1"},{"Name":"b","IPAddress":"10.0.0.2"}]}`,
+			names:  "a,b",
+			active: "b",
+			host:   "10.0.0.2",
+		},
+		{
+			name:   "unknown active profile",
+			json:   `{"active_profile":"c","profiles":[{"Name":"a","IPAddress":"10.0.0.1"}]}`,
+			names:  "a",
+			active: "a",
+			host:   "10.0.0.1",
+		},
+		{
+			name:   "file without profiles",
+			json:   `{"IPAddress":"10.0.0.3","Port":2405,"telemetry_descriptions":{"0":"Voltage"}}`,
+			names:  DefaultProfileName,
+			active: DefaultProfileName,
+			host:   "10.0.0.3",
+		},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(tt.json), 0600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if names := strings.Join(cfg.ProfileNames(), ","); names != tt.names {
+			t.Errorf("%s: profiles %s, want %s", tt.name, names, tt.names)
+		}
+		if cfg.ActiveProfile != tt.active || cfg.IPAddress != tt.host {
+			t.Errorf("%s: active %s at %s, want %s at %s", tt.name, cfg.ActiveProfile, cfg.IPAddress, tt.active, tt.host)
+		}
+	}
+
+	// legacy descriptions, keyed by offset, become points
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(tests[2].json), 0600)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := cfg.PointName(PointTelemetry, 16385); name != "Voltage" {
+		t.Errorf("migrated description %q, want Voltage", name)
+	}
+}
+
+func TestAcceptsCommonAddress(t *testing.T) {
+	p := NewProfile("test")
+	p.ExtraCommonAddresses = []int{7, 9}
+	for ca, want := range map[int]bool{1: true, 7: true, 9: true, 2: false, 0: false} {
+		if got := p.AcceptsCommonAddress(ca); got != want {
+			t.Errorf("AcceptsCommonAddress(%d) = %v, want %v", ca, got, want)
+		}
+	}
+
+	clone := p.Clone("copy")
+	clone.ExtraCommonAddresses[0] = 8
+	if p.ExtraCommonAddresses[0] != 7 {
+		t.Errorf("clone shares the extra common addresses: %v", p.ExtraCommonAddresses)
+	}
+}
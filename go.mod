@@ -3,17 +3,25 @@ module iec104
 go 1.20
 
 require (
+	github.com/eclipse/paho.mqtt.golang v1.4.3
 	github.com/gdamore/tcell/v2 v2.6.0
+	github.com/gorilla/websocket v1.5.0
 	github.com/rivo/tview v0.0.0-20230621164836-6cc0565babaf
+	github.com/thinkgos/go-iecp5 v1.2.1
+	google.golang.org/grpc v1.58.3
+	google.golang.org/protobuf v1.33.0
 )
 
 require (
 	github.com/gdamore/encoding v1.0.0 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.14 // indirect
 	github.com/rivo/uniseg v0.4.3 // indirect
-	github.com/thinkgos/go-iecp5 v1.2.1 // indirect
-	golang.org/x/sys v0.5.0 // indirect
-	golang.org/x/term v0.5.0 // indirect
-	golang.org/x/text v0.7.0 // indirect
+	golang.org/x/net v0.12.0 // indirect
+	golang.org/x/sync v0.3.0 // indirect
+	golang.org/x/sys v0.10.0 // indirect
+	golang.org/x/term v0.10.0 // indirect
+	golang.org/x/text v0.11.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230711160842-782d3b101e98 // indirect
 )
@@ -0,0 +1,318 @@
+package iec_client
+
+import (
+	"fmt"
+	"iec104/config"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// AlarmCondition is the limit or state that put a point into alarm
+type AlarmCondition int
+
+const (
+	// AlarmNormal means the point is not in alarm
+	AlarmNormal AlarmCondition = iota
+	AlarmLowLow
+	AlarmLow
+	AlarmHigh
+	AlarmHighHigh
+	// AlarmState is a teleindication in its configured alarm state
+	AlarmState
+)
+
+func (c AlarmCondition) String() string {
+	switch c {
+	case AlarmNormal:
+		return "Normal"
+	case AlarmLowLow:
+		return "LL"
+	case AlarmLow:
+		return "L"
+	case AlarmHigh:
+		return "H"
+	case AlarmHighHigh:
+		return "HH"
+	case AlarmState:
+		return "State"
+	default:
+		return "Unknown"
+	}
+}
+
+// Critical reports whether the condition is a high-high, low-low or state alarm
+func (c AlarmCondition) Critical() bool {
+	return c == AlarmLowLow || c == AlarmHighHigh || c == AlarmState
+}
+
+func (c AlarmCondition) high() bool {
+	return c == AlarmHigh || c == AlarmHighHigh
+}
+
+func (c AlarmCondition) low() bool {
+	return c == AlarmLow || c == AlarmLowLow
+}
+
+// Alarm is an entry of the alarm list. It stays in the list until it has
+// both returned to normal and been acknowledged.
+type Alarm struct {
+	Type      DataType
+	Address   int
+	Condition AlarmCondition
+	Message   string
+	// Value is the engineering value that raised the alarm
+	Value float64
+	// Raised is when the alarm was raised, Cleared when the point returned
+	// to normal and Acked when it was acknowledged; zero if not yet
+	Raised  time.Time
+	Cleared time.Time
+	Acked   time.Time
+}
+
+// Active reports whether the point is still in alarm
+func (a Alarm) Active() bool {
+	return a.Cleared.IsZero()
+}
+
+// Acknowledged reports whether the alarm has been acknowledged
+func (a Alarm) Acknowledged() bool {
+	return !a.Acked.IsZero()
+}
+
+type alarmKey struct {
+	typ DataType
+	ioa int
+}
+
+// Alarms returns the alarm list, newest first
+func (c *IEC104Client) Alarms() []Alarm {
+	c.dataMu.RLock()
+	defer c.dataMu.RUnlock()
+
+	alarms := make([]Alarm, 0, len(c.alarms))
+	for _, a := range c.alarms {
+		alarms = append(alarms, *a)
+	}
+	sort.Slice(alarms, func(i, j int) bool {
+		return alarms[i].Raised.After(alarms[j].Raised)
+	})
+	return alarms
+}
+
+// AlarmCount returns a counter that changes whenever the alarm list
+// changes, which tells pollers whether Alarms has changed
+func (c *IEC104Client) AlarmCount() uint64 {
+	c.dataMu.RLock()
+	defer c.dataMu.RUnlock()
+
+	return c.alarmCount
+}
+
+// PointAlarm returns the alarm of a point, if it is in the alarm list
+func (c *IEC104Client) PointAlarm(typ DataType, ioa int) (Alarm, bool) {
+	c.dataMu.RLock()
+	defer c.dataMu.RUnlock()
+
+	if a, ok := c.alarms[alarmKey{typ, ioa}]; ok {
+		return *a, true
+	}
+	return Alarm{}, false
+}
+
+// Acknowledge acknowledges the alarm of a point and reports whether there was one
+func (c *IEC104Client) Acknowledge(typ DataType, ioa int) bool {
+	c.dataMu.Lock()
+	defer c.dataMu.Unlock()
+
+	a, ok := c.alarms[alarmKey{typ, ioa}]
+	if !ok || a.Acknowledged() {
+		return false
+	}
+	c.acknowledge(a, time.Now())
+	return true
+}
+
+// AcknowledgeAll acknowledges every alarm and returns how many were unacknowledged
+func (c *IEC104Client) AcknowledgeAll() int {
+	c.dataMu.Lock()
+	defer c.dataMu.Unlock()
+
+	now := time.Now()
+	n := 0
+	for _, a := range c.alarms {
+		if !a.Acknowledged() {
+			c.acknowledge(a, now)
+			n++
+		}
+	}
+	return n
+}
+
+// acknowledge marks an alarm acknowledged and drops it if it has already
+// cleared; the caller holds dataMu
+func (c *IEC104Client) acknowledge(a *Alarm, now time.Time) {
+	a.Acked = now
+	if !a.Active() {
+		delete(c.alarms, alarmKey{a.Type, a.Address})
+	}
+	c.alarmCount++
+}
+
+// telemetryAlarm evaluates the limits of a telemetry value; the caller holds dataMu
+func (c *IEC104Client) telemetryAlarm(u Update) {
+	point := c.conf.FindPoint(config.PointTelemetry, u.Address)
+	key := alarmKey{Telemetry, u.Address}
+	if !point.HasLimits() {
+		c.setAlarm(u, key, AlarmNormal, "")
+		return
+	}
+
+	prev := AlarmNormal
+	if a, ok := c.alarms[key]; ok && a.Active() {
+		prev = a.Condition
+	}
+	condition := limitCondition(point, u.Value, prev)
+
+	var message string
+	switch condition {
+	case AlarmHighHigh:
+		message = fmt.Sprintf("%s above HH %s", point.FormatValue(u.Value), point.FormatValue(*point.HighHigh))
+	case AlarmHigh:
+		message = fmt.Sprintf("%s above H %s", point.FormatValue(u.Value), point.FormatValue(*point.High))
+	case AlarmLow:
+		message = fmt.Sprintf("%s below L %s", point.FormatValue(u.Value), point.FormatValue(*point.Low))
+	case AlarmLowLow:
+		message = fmt.Sprintf("%s below LL %s", point.FormatValue(u.Value), point.FormatValue(*point.LowLow))
+	default:
+		message = point.FormatValue(u.Value)
+	}
+	c.setAlarm(u, key, condition, message)
+}
+
+// limitCondition returns the limit a value violates. A limit that was
+// already violated stays violated until the value is back by the deadband.
+func limitCondition(point *config.Point, value float64, prev AlarmCondition) AlarmCondition {
+	deadband := math.Abs(point.Deadband)
+	above := func(limit *float64, condition AlarmCondition) bool {
+		if limit == nil {
+			return false
+		}
+		if prev.high() && prev >= condition {
+			return value > *limit-deadband
+		}
+		return value > *limit
+	}
+	below := func(limit *float64, condition AlarmCondition) bool {
+		if limit == nil {
+			return false
+		}
+		if prev.low() && prev <= condition {
+			return value < *limit+deadband
+		}
+		return value < *limit
+	}
+
+	switch {
+	case above(point.HighHigh, AlarmHighHigh):
+		return AlarmHighHigh
+	case above(point.High, AlarmHigh):
+		return AlarmHigh
+	case below(point.LowLow, AlarmLowLow):
+		return AlarmLowLow
+	case below(point.Low, AlarmLow):
+		return AlarmLow
+	default:
+		return AlarmNormal
+	}
+}
+
+// indicationAlarm evaluates the alarm state of a teleindication; the
+// caller holds dataMu
+func (c *IEC104Client) indicationAlarm(u Update) {
+	point := c.conf.FindPoint(config.PointTeleindication, u.Address)
+	key := alarmKey{Teleindication, u.Address}
+
+	condition := AlarmNormal
+	var message string
+	if u.Double {
+		message, _ = point.DoubleStateLabel(int(u.DoubleState))
+		switch u.DoubleState {
+		case asdu.DPIIndeterminateOrIntermediate:
+			// Travelling between states, keep the current alarm
+			if a, ok := c.alarms[key]; ok && a.Active() {
+				condition = a.Condition
+			}
+		case asdu.DPIIndeterminate:
+			if point.AlarmState != "" {
+				condition = AlarmState
+			}
+		default:
+			if point.IsAlarmState(u.State) {
+				condition = AlarmState
+			}
+		}
+	} else {
+		message, _ = point.StateLabel(u.State)
+		if point.IsAlarmState(u.State) {
+			condition = AlarmState
+		}
+	}
+	c.setAlarm(u, key, condition, message)
+}
+
+// setAlarm raises, updates or clears the alarm of a point and records the
+// transitions in the event log; the caller holds dataMu
+func (c *IEC104Client) setAlarm(u Update, key alarmKey, condition AlarmCondition, message string) {
+	a, ok := c.alarms[key]
+	active := ok && a.Active()
+
+	switch {
+	case condition == AlarmNormal:
+		if !active {
+			return
+		}
+		a.Cleared = u.Received
+		if a.Acknowledged() {
+			delete(c.alarms, key)
+		}
+		c.alarmCount++
+		c.addEvent(Event{Update: u, Kind: EventAlarm, Message: fmt.Sprintf("%s alarm cleared: %s", a.Condition, message)})
+	case active && (condition == a.Condition || condition.high() && a.Condition == AlarmHighHigh || condition.low() && a.Condition == AlarmLowLow):
+		// Unchanged, or eased from HH to H or LL to L: keep the acknowledgement
+		if condition != a.Condition {
+			a.Condition, a.Message = condition, message
+			c.alarmCount++
+			c.addEvent(Event{Update: u, Kind: EventAlarm, Message: fmt.Sprintf("%s alarm: %s", condition, message)})
+		}
+	default:
+		c.alarms[key] = &Alarm{
+			Type:      key.typ,
+			Address:   key.ioa,
+			Condition: condition,
+			Message:   message,
+			Value:     u.Value,
+			Raised:    u.Received,
+		}
+		c.alarmCount++
+		c.addEvent(Event{Update: u, Kind: EventAlarm, Message: fmt.Sprintf("%s alarm: %s", condition, message)})
+	}
+}
+
+// reevaluateAlarms checks every stored value against the current limits,
+// e.g. after they were edited; the caller holds dataMu
+func (c *IEC104Client) reevaluateAlarms() {
+	ca := c.conf.CommonAddress
+	for _, p := range c.Telemetry {
+		u := p.update(ca)
+		u.Received = time.Now()
+		c.telemetryAlarm(u)
+	}
+	for _, p := range c.Teleindication {
+		u := p.update(ca)
+		u.Received = time.Now()
+		c.indicationAlarm(u)
+	}
+}
@@ -0,0 +1,169 @@
+package iec_client
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"iec104/config"
+	"iec104/simulator/simtest"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// limit returns a pointer to an alarm limit
+func limit(v float64) *float64 {
+	return &v
+}
+
+// idleClient returns a client that is never connected for a profile with
+// the given points
+func idleClient(t *testing.T, points ...config.Point) *IEC104Client {
+	t.Helper()
+	c := newTestClient(t, simtest.Profile(t, "127.0.0.1:1"))
+	c.conf.ReplacePoints(points, false)
+	return c
+}
+
+func TestLimitCondition(t *testing.T) {
+	point := &config.Point{LowLow: limit(0), Low: limit(10), High: limit(90), HighHigh: limit(100), Deadband: -2}
+	tests := []struct {
+		value float64
+		prev  AlarmCondition
+		want  AlarmCondition
+	}{
+		{50, AlarmNormal, AlarmNormal},
+		{90, AlarmNormal, AlarmNormal},
+		{91, AlarmNormal, AlarmHigh},
+		{89, AlarmNormal, AlarmNormal},
+		{89, AlarmHigh, AlarmHigh},
+		{88, AlarmHigh, AlarmNormal},
+		{101, AlarmHigh, AlarmHighHigh},
+		{99, AlarmHigh, AlarmHigh},
+		{99, AlarmHighHigh, AlarmHighHigh},
+		{97, AlarmHighHigh, AlarmHigh},
+		{87, AlarmHighHigh, AlarmNormal},
+		{9, AlarmNormal, AlarmLow},
+		{11, AlarmLow, AlarmLow},
+		{12, AlarmLow, AlarmNormal},
+		{-1, AlarmLow, AlarmLowLow},
+		{1, AlarmLowLow, AlarmLowLow},
+		{3, AlarmLowLow, AlarmLow},
+		{11, AlarmHigh, AlarmNormal},
+		{89, AlarmLow, AlarmNormal},
+	}
+	for _, tt := range tests {
+		if got := limitCondition(point, tt.value, tt.prev); got != tt.want {
+			t.Errorf("limitCondition(%g, %s) = %s, want %s", tt.value, tt.prev, got, tt.want)
+		}
+	}
+
+	high := &config.Point{High: limit(90)}
+	if got := limitCondition(high, -1000, AlarmNormal); got != AlarmNormal {
+		t.Errorf("value below a point without low limits = %s", got)
+	}
+}
+
+func TestTelemetryAlarm(t *testing.T) {
+	const ioa = TelemetryBaseAddress
+	c := idleClient(t, config.Point{Address: ioa, Type: config.PointTelemetry, High: limit(90), HighHigh: limit(100), Deadband: 2})
+
+	// each step is a value received, or an acknowledgement for a negative one
+	steps := []struct {
+		name   string
+		value  float64
+		listed bool
+		want   Alarm
+		event  string
+	}{
+		{"normal", 50, false, Alarm{}, ""},
+		{"raise", 95, true, Alarm{Condition: AlarmHigh, Value: 95}, "H alarm: 95.00 above H 90.00"},
+		{"inside deadband", 89, true, Alarm{Condition: AlarmHigh, Value: 95}, ""},
+		{"escalate", 105, true, Alarm{Condition: AlarmHighHigh, Value: 105}, "HH alarm: 105.00 above HH 100.00"},
+		{"acknowledge", -1, true, Alarm{Condition: AlarmHighHigh, Value: 105, Acked: time.Now()}, ""},
+		{"ease keeps acknowledgement", 95, true, Alarm{Condition: AlarmHigh, Value: 105, Acked: time.Now()}, "H alarm: 95.00 above H 90.00"},
+		{"return acknowledged", 50, false, Alarm{}, "H alarm cleared: 50.00"},
+		{"raise again", 95, true, Alarm{Condition: AlarmHigh, Value: 95}, "H alarm: 95.00 above H 90.00"},
+		{"return unacknowledged", 50, true, Alarm{Condition: AlarmHigh, Value: 95, Cleared: time.Now()}, "H alarm cleared: 50.00"},
+		{"acknowledge cleared", -1, false, Alarm{}, ""},
+	}
+	for _, step := range steps {
+		events := len(c.Events())
+		if step.value < 0 {
+			if !c.Acknowledge(Telemetry, ioa) {
+				t.Fatalf("%s: no alarm to acknowledge", step.name)
+			}
+		} else {
+			c.updateTelemetry(1, asdu.Spontaneous, ioa, step.value, asdu.QDSGood, time.Time{})
+		}
+
+		a, listed := c.PointAlarm(Telemetry, ioa)
+		if listed != step.listed {
+			t.Fatalf("%s: listed = %v, want %v", step.name, listed, step.listed)
+		}
+		if listed && (a.Condition != step.want.Condition || a.Value != step.want.Value ||
+			a.Acknowledged() != step.want.Acknowledged() || a.Active() != step.want.Active()) {
+			t.Errorf("%s: alarm = %+v, want %+v", step.name, a, step.want)
+		}
+
+		var got []string
+		for _, e := range c.Events()[events:] {
+			if e.Kind == EventAlarm {
+				got = append(got, e.Message)
+			}
+		}
+		if want := step.event; (want == "") != (len(got) == 0) || len(got) > 1 || want != "" && got[0] != want {
+			t.Errorf("%s: alarm events %q, want %q", step.name, got, want)
+		}
+	}
+}
+
+func TestIndicationAlarm(t *testing.T) {
+	c := idleClient(t,
+		config.Point{Address: 1, Type: config.PointTeleindication, AlarmState: config.AlarmOff},
+		config.Point{Address: 2, Type: config.PointTeleindication, AlarmState: config.AlarmOn, Invert: true},
+		config.Point{Address: 3, Type: config.PointTeleindication, AlarmState: config.AlarmOn},
+	)
+
+	tests := []struct {
+		name   string
+		update func()
+		ioa    int
+		active bool
+	}{
+		{"single in alarm state", func() { c.updateTeleindication(1, asdu.Spontaneous, 1, false, asdu.QDSGood, time.Time{}) }, 1, true},
+		{"single back", func() { c.updateTeleindication(1, asdu.Spontaneous, 1, true, asdu.QDSGood, time.Time{}) }, 1, false},
+		{"inverted", func() { c.updateTeleindication(1, asdu.Spontaneous, 2, false, asdu.QDSGood, time.Time{}) }, 2, true},
+		{"double on", func() { c.updateDoublePoint(1, asdu.Spontaneous, 3, asdu.DPIDeterminedOn, asdu.QDSGood, time.Time{}) }, 3, true},
+		{"double travelling keeps alarm", func() {
+			c.updateDoublePoint(1, asdu.Spontaneous, 3, asdu.DPIIndeterminateOrIntermediate, asdu.QDSGood, time.Time{})
+		}, 3, true},
+		{"double off", func() { c.updateDoublePoint(1, asdu.Spontaneous, 3, asdu.DPIDeterminedOff, asdu.QDSGood, time.Time{}) }, 3, false},
+		{"double faulty", func() { c.updateDoublePoint(1, asdu.Spontaneous, 3, asdu.DPIIndeterminate, asdu.QDSGood, time.Time{}) }, 3, true},
+	}
+	for _, tt := range tests {
+		tt.update()
+		a, ok := c.PointAlarm(Teleindication, tt.ioa)
+		if ok && a.Condition != AlarmState {
+			t.Errorf("%s: condition = %s, want %s", tt.name, a.Condition, AlarmState)
+		}
+		if active := ok && a.Active(); active != tt.active {
+			t.Errorf("%s: active = %v, want %v", tt.name, active, tt.active)
+		}
+	}
+
+	if n := c.AcknowledgeAll(); n != 3 {
+		t.Errorf("AcknowledgeAll = %d, want 3", n)
+	}
+	// acknowledging drops the cleared alarm of point 1 and keeps the active ones
+	var messages []string
+	for _, a := range c.Alarms() {
+		messages = append(messages, a.Message)
+		if !a.Active() || !a.Acknowledged() {
+			t.Errorf("alarm after AcknowledgeAll = %+v", a)
+		}
+	}
+	if len(messages) != 2 {
+		t.Errorf("alarms after AcknowledgeAll: %s", strings.Join(messages, ", "))
+	}
+}
@@ -1,10 +1,14 @@
 package iec_client
 
 import (
+	"context"
 	"fmt"
 	"github.com/thinkgos/go-iecp5/asdu"
 	"github.com/thinkgos/go-iecp5/cs104"
 	"iec104/config"
+	"net"
+	"sort"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,6 +19,9 @@ var (
 )
 
 type ConnectionStateHandler func(bool)
+
+// DataHandler receives point updates; data is a float64 for analog points,
+// a bool for single points and an asdu.DoublePoint for double points
 type DataHandler func(typ DataType, iot int, data interface{})
 
 type Logger interface {
@@ -25,42 +32,88 @@ type Logger interface {
 
 type IEC104Client struct {
 	client *cs104.Client
-	conf   *config.Config
+	tap    *tap
+	conf   *config.Profile
 	Logger Logger
+	// LibraryDebug passes the IEC 104 library's own log, which traces
+	// every frame, to Logger's Debugf; it applies from the next Connect
+	LibraryDebug bool
 
 	closer                 chan struct{}
 	mu                     sync.Mutex
 	connectionStateHandler ConnectionStateHandler
 	dataHandler            DataHandler
 
+	pending pendingCommands
+
+	subMu         sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+
+	dataMu         sync.RWMutex
+	lastReceived   atomic.Int64
 	Connected      atomic.Bool
 	Telemetry      map[int]TelemetryPoint
 	Teleindication map[int]TeleindPoint
 	Telecontrol    map[int]TelecontrolPoint
 	Teleregulation map[int]TeleregulationPoint
+
+	historySize int
+	history     map[int]*history
+	events      ring[Event]
+	eventCount  uint64
+	alarms      map[alarmKey]*Alarm
+	alarmCount  uint64
+	frames      frameLog
+	stats       linkStats
 }
 
-func NewIEC104Client(conf *config.Config) *IEC104Client {
+func NewIEC104Client(conf *config.Profile) *IEC104Client {
 	client := &IEC104Client{
 		conf:           conf,
 		closer:         make(chan struct{}),
+		subscriptions:  make(map[*Subscription]struct{}),
 		Telemetry:      make(map[int]TelemetryPoint),
 		Teleindication: make(map[int]TeleindPoint),
 		Telecontrol:    make(map[int]TelecontrolPoint),
 		Teleregulation: make(map[int]TeleregulationPoint),
+		historySize:    DefaultHistorySize,
+		history:        make(map[int]*history),
+		alarms:         make(map[alarmKey]*Alarm),
 	}
+	client.stats.reset()
 
 	go client.run()
 	return client
 }
 
-func (c *IEC104Client) UpdateConfig(conf *config.Config) {
+// Profile returns the connection profile the client uses
+func (c *IEC104Client) Profile() *config.Profile {
+	return c.conf
+}
+
+func (c *IEC104Client) UpdateConfig(conf *config.Profile) {
 	c.mu.Lock()
 	c.mu.Unlock()
 
 	c.conf = conf
 }
 
+// ClearData forgets all received point values, e.g. after switching servers
+func (c *IEC104Client) ClearData() {
+	c.dataMu.Lock()
+	defer c.dataMu.Unlock()
+
+	c.Telemetry = make(map[int]TelemetryPoint)
+	c.Teleindication = make(map[int]TeleindPoint)
+	c.Telecontrol = make(map[int]TelecontrolPoint)
+	c.Teleregulation = make(map[int]TeleregulationPoint)
+	c.history = make(map[int]*history)
+	c.events = ring[Event]{}
+	c.eventCount++
+	c.alarms = make(map[alarmKey]*Alarm)
+	c.alarmCount++
+}
+
 func (c *IEC104Client) RegisterConnectionStateHandler(handler ConnectionStateHandler) {
 	c.connectionStateHandler = handler
 }
@@ -69,41 +122,74 @@ func (c *IEC104Client) RegisterDataHandler(handler DataHandler) {
 	c.dataHandler = handler
 }
 
+// Connect starts connecting to the server of the profile and keeps
+// reconnecting until Disconnect. A client still trying to connect to the
+// same server is kept; one trying the server of a previous profile is
+// replaced.
 func (c *IEC104Client) Connect() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.Connected.Load() {
 		return nil
 	}
+	remote := net.JoinHostPort(c.conf.IPAddress, strconv.Itoa(c.conf.Port))
+	if c.client != nil {
+		if !c.tap.closed() && c.tap.remote == remote {
+			return nil
+		}
+		c.tap.Close()
+		c.client.Close()
+	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
+	const reconnectInterval = 5 * time.Second
 	option := cs104.NewOption()
 	option.SetAutoReconnect(true)
-	option.SetReconnectInterval(5 * time.Second)
+	option.SetReconnectInterval(reconnectInterval)
 
-	err := option.AddRemoteServer(fmt.Sprintf("%s:%d", c.conf.IPAddress, c.conf.Port))
+	// the library dials the tap, which captures the frames on their way
+	tap, err := newTap(c, remote, cs104.DefaultConfig().ConnectTimeout0, reconnectInterval)
 	if err != nil {
 		return err
 	}
+	if err := option.AddRemoteServer(tap.Addr()); err != nil {
+		tap.Close()
+		return err
+	}
 
+	c.tap = tap
 	c.client = cs104.NewClient(c, option)
-	c.client.LogMode(false)
+	tap.params = c.client.Params()
+	c.client.SetLogProvider(libraryLog{c: c, tap: tap})
+	c.client.LogMode(c.LibraryDebug)
 
-	c.client.SetOnConnectHandler(func(client *cs104.Client) {
+	tap.onUp = func() {
 		c.Connected.Store(true)
+		c.stats.connected(true)
 		if c.connectionStateHandler != nil {
 			c.connectionStateHandler(true)
 		}
+		c.notify(Notice{Kind: NoticeConnection, Time: time.Now(), Connected: true})
 		c.Logger.Infof("Connected to server: %s:%d", c.conf.IPAddress, c.conf.Port)
-		client.SendStartDt()
-	})
-
-	c.client.SetConnectionLostHandler(func(client *cs104.Client) {
+	}
+	tap.onDown = func() {
 		c.Connected.Store(false)
+		c.stats.connected(false)
 		if c.connectionStateHandler != nil {
 			c.connectionStateHandler(false)
 		}
+		c.notify(Notice{Kind: NoticeConnection, Time: time.Now(), Connected: false})
 		c.Logger.Infof("Disconnected from server: %s:%d", c.conf.IPAddress, c.conf.Port)
+	}
+	tap.Start()
+
+	// the STARTDT waits in the tap until the server is reached
+	c.client.SetOnConnectHandler(func(client *cs104.Client) {
+		tap.connected(client.UnderlyingConn())
+		client.SendStartDt()
+	})
+	c.client.SetConnectionLostHandler(func(client *cs104.Client) {
+		tap.lost(client.UnderlyingConn())
 	})
 
 	err = c.client.Start()
@@ -115,13 +201,14 @@ func (c *IEC104Client) Connect() error {
 }
 
 func (c *IEC104Client) Disconnect() error {
-	if !c.Connected.Load() || c.client == nil {
-		return nil
-	}
-
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.client == nil {
+		return nil
+	}
+
+	c.tap.Close()
 	c.client.Close()
 	c.Connected.Store(false)
 	return nil
@@ -130,73 +217,49 @@ func (c *IEC104Client) Disconnect() error {
 func (c *IEC104Client) Close() {
 	close(c.closer)
 	c.Disconnect()
+
+	c.subMu.RLock()
+	subs := make([]*Subscription, 0, len(c.subscriptions))
+	for sub := range c.subscriptions {
+		subs = append(subs, sub)
+	}
+	c.subMu.RUnlock()
+	for _, sub := range subs {
+		sub.Close()
+	}
 }
 
 // SendTelecontrol sends a telecontrol command (digital control) to the server
-// TODO only support single command & with select
+// and blocks until it is confirmed or DefaultCommandTimeout elapses
 func (c *IEC104Client) SendTelecontrol(offset int, value bool) error {
 	if !c.Connected.Load() || c.client == nil {
 		return ErrorNoConnection
 	}
 
-	ioa := offset + 24577
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	err := asdu.SingleCmd(c.client, asdu.C_SC_NA_1, asdu.CauseOfTransmission{
-		Cause: asdu.Activation,
-	}, 1, asdu.SingleCommandInfo{
-		Ioa:   asdu.InfoObjAddr(ioa),
-		Value: value,
-		Qoc: asdu.QualifierOfCommand{
-			InSelect: true,
-		},
-	})
-	if err != nil {
-		return fmt.Errorf("send Select Command error: %v", err)
-	}
-
-	time.Sleep(time.Millisecond * 200)
-	err = asdu.SingleCmd(c.client, asdu.C_SC_NA_1, asdu.CauseOfTransmission{
-		Cause: asdu.Activation,
-	}, 1, asdu.SingleCommandInfo{
-		Ioa:   asdu.InfoObjAddr(ioa),
-		Value: value,
-	})
-	if err != nil {
-		return fmt.Errorf("send Telecontrol Command error: %v", err)
-	}
-
-	return nil
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultCommandTimeout)
+	defer cancel()
+	return c.SendTelecontrolContext(ctx, offset, value)
 }
 
 // SendTelemetry sends a telemetry command (analog control) to the server
-// TODO only support float
+// and blocks until it is confirmed or DefaultCommandTimeout elapses
 func (c *IEC104Client) SendTelemetry(offset int, value float64) error {
 	if !c.Connected.Load() || c.client == nil {
 		return ErrorNoConnection
 	}
 
-	ioa := offset + 25089
-
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	err := asdu.SetpointCmdFloat(c.client, asdu.C_SE_NC_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, 1, asdu.SetpointCommandFloatInfo{
-		Ioa:   asdu.InfoObjAddr(ioa),
-		Value: float32(value),
-	})
-	if err != nil {
-		return fmt.Errorf("send Telemetry Command error: %v", err)
-	}
-	return nil
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultCommandTimeout)
+	defer cancel()
+	return c.SendTelemetryContext(ctx, offset, value)
 }
 
-func (c *IEC104Client) InterrogationHandler(asdu.Connect, *asdu.ASDU) error {
+func (c *IEC104Client) InterrogationHandler(_ asdu.Connect, a *asdu.ASDU) error {
+	c.handleConfirmation(a)
 	return nil
 }
 
-func (c *IEC104Client) CounterInterrogationHandler(asdu.Connect, *asdu.ASDU) error {
+func (c *IEC104Client) CounterInterrogationHandler(_ asdu.Connect, a *asdu.ASDU) error {
+	c.handleConfirmation(a)
 	return nil
 }
 
@@ -221,70 +284,45 @@ func (c *IEC104Client) DelayAcquisitionHandler(asdu.Connect, *asdu.ASDU) error {
 }
 
 func (c *IEC104Client) ASDUHandler(client asdu.Connect, a *asdu.ASDU) error {
-	if a.CommonAddr != asdu.CommonAddr(c.conf.CommonAddress) {
+	ca, cause := int(a.CommonAddr), a.Coa.Cause
+	if !c.conf.AcceptsCommonAddress(ca) {
 		return nil
 	}
+	c.lastReceived.Store(time.Now().UnixNano())
+
+	if isCommandType(a.Identifier.Type) {
+		if ca == c.conf.CommonAddress {
+			c.handleConfirmation(a)
+		}
+		return nil
+	}
+
 	switch a.Identifier.Type {
-	case asdu.M_ME_NC_1:
+	case asdu.M_ME_NC_1, asdu.M_ME_TF_1:
 		data := a.GetMeasuredValueFloat()
 		for _, d := range data {
-			c.Telemetry[int(d.Ioa)] = TelemetryPoint{
-				DataPoint: DataPoint{
-					Address:   int(d.Ioa),
-					Timestamp: d.Time,
-				},
-				Value: float64(d.Value),
-			}
-
-			if c.dataHandler != nil {
-				c.dataHandler(Telemetry, int(d.Ioa), float64(d.Value))
-			}
+			c.updateTelemetry(ca, cause, int(d.Ioa), float64(d.Value), d.Qds, d.Time)
 		}
-	case asdu.M_ME_NA_1, asdu.M_ME_ND_1:
+	case asdu.M_ME_NA_1, asdu.M_ME_ND_1, asdu.M_ME_TD_1:
 		data := a.GetMeasuredValueNormal()
 		for _, d := range data {
-			c.Telemetry[int(d.Ioa)] = TelemetryPoint{
-				DataPoint: DataPoint{
-					Address:   int(d.Ioa),
-					Timestamp: d.Time,
-				},
-				Value: float64(d.Value),
-			}
-
-			if c.dataHandler != nil {
-				c.dataHandler(Telemetry, int(d.Ioa), float64(d.Value))
-			}
+			c.updateTelemetry(ca, cause, int(d.Ioa), d.Value.Float64(), d.Qds, d.Time)
 		}
 
-	case asdu.M_SP_NA_1:
+	case asdu.M_SP_NA_1, asdu.M_SP_TB_1:
 		data := a.GetSinglePoint()
 		for _, d := range data {
-			c.Teleindication[int(d.Ioa)] = TeleindPoint{
-				DataPoint: DataPoint{
-					Address:   int(d.Ioa),
-					Timestamp: d.Time,
-				},
-				Value: d.Value,
-			}
-
-			if c.dataHandler != nil {
-				c.dataHandler(Teleindication, int(d.Ioa), d.Value)
-			}
+			c.updateTeleindication(ca, cause, int(d.Ioa), d.Value, d.Qds, d.Time)
 		}
-	case asdu.M_ME_NB_1:
+	case asdu.M_DP_NA_1, asdu.M_DP_TB_1:
+		data := a.GetDoublePoint()
+		for _, d := range data {
+			c.updateDoublePoint(ca, cause, int(d.Ioa), d.Value, d.Qds, d.Time)
+		}
+	case asdu.M_ME_NB_1, asdu.M_ME_TE_1:
 		data := a.GetMeasuredValueScaled()
 		for _, d := range data {
-			c.Telemetry[int(d.Ioa)] = TelemetryPoint{
-				DataPoint: DataPoint{
-					Address:   int(d.Ioa),
-					Timestamp: d.Time,
-				},
-				Value: float64(d.Value),
-			}
-
-			if c.dataHandler != nil {
-				c.dataHandler(Telemetry, int(d.Ioa), float64(d.Value))
-			}
+			c.updateTelemetry(ca, cause, int(d.Ioa), float64(d.Value), d.Qds, d.Time)
 		}
 
 	default:
@@ -293,6 +331,232 @@ func (c *IEC104Client) ASDUHandler(client asdu.Connect, a *asdu.ASDU) error {
 	return nil
 }
 
+// updateTelemetry scales a measured value into engineering units,
+// stores it and notifies subscribers
+func (c *IEC104Client) updateTelemetry(ca int, cause asdu.Cause, ioa int, raw float64, qds asdu.QualityDescriptor, t time.Time) {
+	// the points are configured for the profile's own station
+	var point *config.Point
+	if ca == c.conf.CommonAddress {
+		point = c.conf.FindPoint(config.PointTelemetry, ioa)
+	}
+	value := point.Engineering(raw)
+	u := Update{
+		DataPoint:  newDataPoint(ioa, cause, qds, t),
+		Type:       Telemetry,
+		CommonAddr: ca,
+		Value:      value,
+		Raw:        raw,
+	}
+	if ca != c.conf.CommonAddress {
+		c.deliver(u)
+		return
+	}
+
+	c.dataMu.Lock()
+	prev, had := c.Telemetry[ioa]
+	c.Telemetry[ioa] = TelemetryPoint{
+		DataPoint: u.DataPoint,
+		Value:     value,
+		Raw:       raw,
+	}
+	c.record(ioa, Sample{Time: u.Received, Value: value, Raw: raw, Quality: qds})
+	c.thresholdEvent(u, prev, had)
+	c.telemetryAlarm(u)
+	c.dataMu.Unlock()
+
+	c.publish(u)
+}
+
+// updateTeleindication stores a status value and notifies subscribers
+func (c *IEC104Client) updateTeleindication(ca int, cause asdu.Cause, ioa int, value bool, qds asdu.QualityDescriptor, t time.Time) {
+	var analog float64
+	if value {
+		analog = 1
+	}
+	u := Update{
+		DataPoint:  newDataPoint(ioa, cause, qds, t),
+		Type:       Teleindication,
+		CommonAddr: ca,
+		Value:      analog,
+		State:      value,
+	}
+	if ca != c.conf.CommonAddress {
+		c.deliver(u)
+		return
+	}
+
+	c.dataMu.Lock()
+	prev, had := c.Teleindication[ioa]
+	c.Teleindication[ioa] = TeleindPoint{
+		DataPoint: u.DataPoint,
+		Value:     value,
+	}
+	c.stateEvent(u, prev, had)
+	c.indicationAlarm(u)
+	c.dataMu.Unlock()
+
+	c.publish(u)
+}
+
+// updateDoublePoint stores a double point status value and notifies subscribers
+func (c *IEC104Client) updateDoublePoint(ca int, cause asdu.Cause, ioa int, value asdu.DoublePoint, qds asdu.QualityDescriptor, t time.Time) {
+	on := value == asdu.DPIDeterminedOn
+	var analog float64
+	if on {
+		analog = 1
+	}
+	u := Update{
+		DataPoint:   newDataPoint(ioa, cause, qds, t),
+		Type:        Teleindication,
+		CommonAddr:  ca,
+		Value:       analog,
+		State:       on,
+		Double:      true,
+		DoubleState: value,
+	}
+	if ca != c.conf.CommonAddress {
+		c.deliver(u)
+		return
+	}
+
+	c.dataMu.Lock()
+	prev, had := c.Teleindication[ioa]
+	c.Teleindication[ioa] = TeleindPoint{
+		DataPoint:   u.DataPoint,
+		Value:       on,
+		Double:      true,
+		DoubleState: value,
+	}
+	c.doubleEvent(u, prev, had)
+	c.indicationAlarm(u)
+	c.dataMu.Unlock()
+
+	c.publish(u)
+}
+
+// newDataPoint describes a value received now
+func newDataPoint(ioa int, cause asdu.Cause, qds asdu.QualityDescriptor, t time.Time) DataPoint {
+	return DataPoint{
+		Address:   ioa,
+		Timestamp: t,
+		Quality:   qds,
+		Received:  time.Now(),
+		Cause:     cause,
+	}
+}
+
+// Rescale recomputes stored engineering values and alarms after the point
+// scaling or limits changed
+func (c *IEC104Client) Rescale() {
+	c.dataMu.Lock()
+	defer c.dataMu.Unlock()
+
+	for ioa, p := range c.Telemetry {
+		point := c.conf.FindPoint(config.PointTelemetry, ioa)
+		p.Value = point.Engineering(p.Raw)
+		c.Telemetry[ioa] = p
+		if h, ok := c.history[ioa]; ok {
+			for i := range h.items {
+				h.items[i].Value = point.Engineering(h.items[i].Raw)
+			}
+		}
+	}
+	c.reevaluateAlarms()
+}
+
+// LastReceived returns the time the last ASDU arrived, or the zero time
+func (c *IEC104Client) LastReceived() time.Time {
+	n := c.lastReceived.Load()
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// Snapshot returns the last known value of every monitored point,
+// ordered by type and address
+func (c *IEC104Client) Snapshot() []Update {
+	c.dataMu.RLock()
+	defer c.dataMu.RUnlock()
+
+	ca := c.conf.CommonAddress
+	points := make([]Update, 0, len(c.Telemetry)+len(c.Teleindication))
+	for _, p := range c.Telemetry {
+		points = append(points, p.update(ca))
+	}
+	for _, p := range c.Teleindication {
+		points = append(points, p.update(ca))
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Type != points[j].Type {
+			return points[i].Type < points[j].Type
+		}
+		return points[i].Address < points[j].Address
+	})
+	return points
+}
+
+// Point returns the last known value of a point. For telecontrol and
+// teleregulation that is the last command confirmed by SendTelecontrol or
+// SendTelemetry.
+func (c *IEC104Client) Point(typ DataType, ioa int) (Update, bool) {
+	c.dataMu.RLock()
+	defer c.dataMu.RUnlock()
+
+	ca := c.conf.CommonAddress
+	switch typ {
+	case Telemetry:
+		if p, ok := c.Telemetry[ioa]; ok {
+			return p.update(ca), true
+		}
+	case Teleindication:
+		if p, ok := c.Teleindication[ioa]; ok {
+			return p.update(ca), true
+		}
+	case Telecontrol:
+		if p, ok := c.Telecontrol[ioa-TelecontrolBaseAddress]; ok {
+			return p.update(ca), true
+		}
+	case Teleregulation:
+		if p, ok := c.Teleregulation[ioa-TeleregulationBaseAddress]; ok {
+			return p.update(ca), true
+		}
+	}
+	return Update{}, false
+}
+
+// Points returns the last known values of one type, ordered by address
+func (c *IEC104Client) Points(typ DataType) []Update {
+	c.dataMu.RLock()
+	defer c.dataMu.RUnlock()
+
+	ca := c.conf.CommonAddress
+	var points []Update
+	switch typ {
+	case Telemetry:
+		for _, p := range c.Telemetry {
+			points = append(points, p.update(ca))
+		}
+	case Teleindication:
+		for _, p := range c.Teleindication {
+			points = append(points, p.update(ca))
+		}
+	case Telecontrol:
+		for _, p := range c.Telecontrol {
+			points = append(points, p.update(ca))
+		}
+	case Teleregulation:
+		for _, p := range c.Teleregulation {
+			points = append(points, p.update(ca))
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Address < points[j].Address
+	})
+	return points
+}
+
 func (c *IEC104Client) run() {
 	time.Sleep(time.Second * 5)
 	c.allCall()
@@ -0,0 +1,121 @@
+package iec_client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"iec104/config"
+	"iec104/simulator"
+	"iec104/simulator/simtest"
+)
+
+// newTestClient creates a client for the profile that is closed with the test
+func newTestClient(t *testing.T, profile *config.Profile) *IEC104Client {
+	t.Helper()
+	c := NewIEC104Client(profile)
+	c.Logger = simtest.NopLogger{}
+	t.Cleanup(c.Close)
+	return c
+}
+
+// connectStation starts a simulated station and a client connected to it
+func connectStation(t *testing.T) (*simulator.Server, *IEC104Client) {
+	t.Helper()
+	sim := simtest.Station(t)
+	c := newTestClient(t, simtest.Profile(t, sim.Addr()))
+	simtest.Connect(t, c)
+	return sim, c
+}
+
+func TestConnect(t *testing.T) {
+	sim := simtest.Station(t)
+	c := newTestClient(t, simtest.Profile(t, sim.Addr()))
+
+	states := make(chan bool, 10)
+	c.RegisterConnectionStateHandler(func(connected bool) { states <- connected })
+
+	// connecting again while the link comes up keeps the first attempt
+	for i := 0; i < 3; i++ {
+		if err := c.Connect(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.ConnectContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if n := c.Stats().Connects; n != 1 {
+		t.Errorf("connects = %d, want 1", n)
+	}
+	if connected := <-states; !connected {
+		t.Error("first state change is a disconnect")
+	}
+
+	if err := c.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case connected := <-states:
+		if connected {
+			t.Error("second state change is a connect")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no disconnect after Disconnect")
+	}
+	if c.Connected.Load() {
+		t.Error("connected after Disconnect")
+	}
+
+	if err := c.ConnectContext(ctx); err != nil {
+		t.Fatalf("reconnect after Disconnect: %v", err)
+	}
+}
+
+func TestConnectReplacesOtherServer(t *testing.T) {
+	// nothing listens on a port that was just free
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := l.Addr().String()
+	l.Close()
+
+	c := newTestClient(t, simtest.Profile(t, dead))
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	sim := simtest.Station(t)
+	c.UpdateConfig(simtest.Profile(t, sim.Addr()))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.ConnectContext(ctx); err != nil {
+		t.Fatalf("connect after switching servers: %v", err)
+	}
+	if n := c.Stats().Connects; n != 1 {
+		t.Errorf("connects = %d, want 1", n)
+	}
+}
+
+func TestConnectUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := l.Addr().String()
+	l.Close()
+
+	c := newTestClient(t, simtest.Profile(t, dead))
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := c.ConnectContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("ConnectContext = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if c.Connected.Load() {
+		t.Error("connected to a closed port")
+	}
+}
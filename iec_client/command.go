@@ -0,0 +1,411 @@
+package iec_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iec104/config"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+	"github.com/thinkgos/go-iecp5/cs104"
+)
+
+// DefaultCommandTimeout bounds the legacy blocking command functions
+const DefaultCommandTimeout = 10 * time.Second
+
+var (
+	ErrorNegativeConfirmation = fmt.Errorf("negative confirmation from server")
+	ErrorCommandPending       = fmt.Errorf("command already pending for address")
+	ErrorUnknownCommand       = fmt.Errorf("unknown command kind")
+)
+
+// pendingKey identifies an outstanding command awaiting confirmation
+type pendingKey struct {
+	typ asdu.TypeID
+	ioa int
+}
+
+// pendingCommands routes confirmation ASDUs back to the waiting caller
+type pendingCommands struct {
+	mu      sync.Mutex
+	waiters map[pendingKey]chan asdu.CauseOfTransmission
+}
+
+func (p *pendingCommands) add(key pendingKey) (chan asdu.CauseOfTransmission, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.waiters == nil {
+		p.waiters = make(map[pendingKey]chan asdu.CauseOfTransmission)
+	}
+	if _, ok := p.waiters[key]; ok {
+		return nil, ErrorCommandPending
+	}
+	ch := make(chan asdu.CauseOfTransmission, 4)
+	p.waiters[key] = ch
+	return ch, nil
+}
+
+func (p *pendingCommands) remove(key pendingKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.waiters, key)
+}
+
+func (p *pendingCommands) notify(key pendingKey, coa asdu.CauseOfTransmission) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch, ok := p.waiters[key]
+	if !ok {
+		return false
+	}
+	select {
+	case ch <- coa:
+	default:
+	}
+	return true
+}
+
+// ConnectContext starts the connection and waits until the link is up
+func (c *IEC104Client) ConnectContext(ctx context.Context) error {
+	if err := c.Connect(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for !c.Connected.Load() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// InterrogateContext sends an interrogation command and waits for its termination
+func (c *IEC104Client) InterrogateContext(ctx context.Context, qoi asdu.QualifierOfInterrogation) error {
+	ca := asdu.CommonAddr(c.conf.CommonAddress)
+	return c.execute(ctx, pendingKey{typ: asdu.C_IC_NA_1}, true, func(conn asdu.Connect) error {
+		return asdu.InterrogationCmd(conn, asdu.CauseOfTransmission{Cause: asdu.Activation}, ca, qoi)
+	})
+}
+
+// CounterInterrogateContext sends a counter interrogation command and waits for its termination
+func (c *IEC104Client) CounterInterrogateContext(ctx context.Context, qcc asdu.QualifierCountCall) error {
+	ca := asdu.CommonAddr(c.conf.CommonAddress)
+	return c.execute(ctx, pendingKey{typ: asdu.C_CI_NA_1}, true, func(conn asdu.Connect) error {
+		return asdu.CounterInterrogationCmd(conn, asdu.CauseOfTransmission{Cause: asdu.Activation}, ca, qcc)
+	})
+}
+
+// SingleCommandContext sends a single command, optionally preceded by a select,
+// and waits for the activation confirmation
+func (c *IEC104Client) SingleCommandContext(ctx context.Context, ioa int, value bool, selectFirst bool) error {
+	ca := asdu.CommonAddr(c.conf.CommonAddress)
+	send := func(inSelect bool) func(asdu.Connect) error {
+		return func(conn asdu.Connect) error {
+			return asdu.SingleCmd(conn, asdu.C_SC_NA_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, ca, asdu.SingleCommandInfo{
+				Ioa:   asdu.InfoObjAddr(ioa),
+				Value: value,
+				Qoc:   asdu.QualifierOfCommand{InSelect: inSelect},
+			})
+		}
+	}
+	return c.selectExecute(ctx, pendingKey{typ: asdu.C_SC_NA_1, ioa: ioa}, selectFirst, send)
+}
+
+// DoubleCommandContext sends a double command, optionally preceded by a select,
+// and waits for the activation confirmation
+func (c *IEC104Client) DoubleCommandContext(ctx context.Context, ioa int, value asdu.DoubleCommand, selectFirst bool) error {
+	ca := asdu.CommonAddr(c.conf.CommonAddress)
+	send := func(inSelect bool) func(asdu.Connect) error {
+		return func(conn asdu.Connect) error {
+			return asdu.DoubleCmd(conn, asdu.C_DC_NA_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, ca, asdu.DoubleCommandInfo{
+				Ioa:   asdu.InfoObjAddr(ioa),
+				Value: value,
+				Qoc:   asdu.QualifierOfCommand{InSelect: inSelect},
+			})
+		}
+	}
+	return c.selectExecute(ctx, pendingKey{typ: asdu.C_DC_NA_1, ioa: ioa}, selectFirst, send)
+}
+
+// SetpointFloatContext sends a short floating point setpoint and waits for the activation confirmation
+func (c *IEC104Client) SetpointFloatContext(ctx context.Context, ioa int, value float32) error {
+	ca := asdu.CommonAddr(c.conf.CommonAddress)
+	return c.execute(ctx, pendingKey{typ: asdu.C_SE_NC_1, ioa: ioa}, false, func(conn asdu.Connect) error {
+		return asdu.SetpointCmdFloat(conn, asdu.C_SE_NC_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, ca, asdu.SetpointCommandFloatInfo{
+			Ioa:   asdu.InfoObjAddr(ioa),
+			Value: value,
+		})
+	})
+}
+
+// SetpointScaledContext sends a scaled setpoint and waits for the activation confirmation
+func (c *IEC104Client) SetpointScaledContext(ctx context.Context, ioa int, value int16) error {
+	ca := asdu.CommonAddr(c.conf.CommonAddress)
+	return c.execute(ctx, pendingKey{typ: asdu.C_SE_NB_1, ioa: ioa}, false, func(conn asdu.Connect) error {
+		return asdu.SetpointCmdScaled(conn, asdu.C_SE_NB_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, ca, asdu.SetpointCommandScaledInfo{
+			Ioa:   asdu.InfoObjAddr(ioa),
+			Value: value,
+		})
+	})
+}
+
+// SetpointNormalContext sends a normalized setpoint (-1 to 1) and waits for the activation confirmation
+func (c *IEC104Client) SetpointNormalContext(ctx context.Context, ioa int, value float64) error {
+	ca := asdu.CommonAddr(c.conf.CommonAddress)
+	return c.execute(ctx, pendingKey{typ: asdu.C_SE_NA_1, ioa: ioa}, false, func(conn asdu.Connect) error {
+		return asdu.SetpointCmdNormal(conn, asdu.C_SE_NA_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, ca, asdu.SetpointCommandNormalInfo{
+			Ioa:   asdu.InfoObjAddr(ioa),
+			Value: asdu.Normalize(value * 32767),
+		})
+	})
+}
+
+// SendTelecontrolContext sends a select-before-operate single command by
+// offset and keeps the value once confirmed
+func (c *IEC104Client) SendTelecontrolContext(ctx context.Context, offset int, value bool) error {
+	ioa := offset + TelecontrolBaseAddress
+	if err := c.SingleCommandContext(ctx, ioa, value, true); err != nil {
+		return err
+	}
+	c.dataMu.Lock()
+	c.Telecontrol[offset] = TelecontrolPoint{DataPoint: DataPoint{Address: ioa, Received: time.Now()}, Value: value}
+	c.dataMu.Unlock()
+	return nil
+}
+
+// SendTelemetryContext sends a floating point setpoint by offset. The value
+// is in engineering units and is converted with the point's scaling; it is
+// kept once confirmed.
+func (c *IEC104Client) SendTelemetryContext(ctx context.Context, offset int, value float64) error {
+	ioa := offset + TeleregulationBaseAddress
+	raw := c.conf.FindPoint(config.PointTeleregulation, ioa).Raw(value)
+	if err := c.SetpointFloatContext(ctx, ioa, float32(raw)); err != nil {
+		return err
+	}
+	c.dataMu.Lock()
+	c.Teleregulation[offset] = TeleregulationPoint{DataPoint: DataPoint{Address: ioa, Received: time.Now()}, Value: value}
+	c.dataMu.Unlock()
+	return nil
+}
+
+// selectExecute runs the optional select phase followed by the execute
+// phase; both phases count as one command, and the address stays reserved
+// from the select until the execute is confirmed so that no other command
+// to it can come in between
+func (c *IEC104Client) selectExecute(ctx context.Context, key pendingKey, selectFirst bool, send func(inSelect bool) func(asdu.Connect) error) error {
+	sent := time.Now()
+	ch, err := c.pending.add(key)
+	if err == nil {
+		if selectFirst {
+			if err = c.activate(ctx, ch, false, send(true)); err != nil {
+				err = fmt.Errorf("select: %w", err)
+			}
+		}
+		if err == nil {
+			if err = c.activate(ctx, ch, false, send(false)); err != nil {
+				err = fmt.Errorf("execute: %w", err)
+			}
+		}
+		c.pending.remove(key)
+	}
+	c.commandDone(key, time.Since(sent), err)
+	return err
+}
+
+// execute sends an activation and waits for the confirmation, and for the
+// termination too when waitTerm is set
+func (c *IEC104Client) execute(ctx context.Context, key pendingKey, waitTerm bool, send func(asdu.Connect) error) error {
+	sent := time.Now()
+	err := c.exchange(ctx, key, waitTerm, send)
+	c.commandDone(key, time.Since(sent), err)
+	return err
+}
+
+// exchange sends an activation and waits for its answers
+func (c *IEC104Client) exchange(ctx context.Context, key pendingKey, waitTerm bool, send func(asdu.Connect) error) error {
+	ch, err := c.pending.add(key)
+	if err != nil {
+		return err
+	}
+	defer c.pending.remove(key)
+	return c.activate(ctx, ch, waitTerm, send)
+}
+
+// activate sends an activation and waits for its answers on ch, the
+// channel of the pending command
+func (c *IEC104Client) activate(ctx context.Context, ch chan asdu.CauseOfTransmission, waitTerm bool, send func(asdu.Connect) error) error {
+	if err := c.sendContext(ctx, send); err != nil {
+		return err
+	}
+	return c.waitConfirmation(ctx, ch, waitTerm)
+}
+
+// commandDone counts a command and notifies subscribers of its outcome
+func (c *IEC104Client) commandDone(key pendingKey, elapsed time.Duration, err error) {
+	c.stats.command(key.typ, elapsed, err)
+
+	n := CommandNotice{Type: key.typ, Address: key.ioa, Outcome: Outcome(err), Elapsed: elapsed}
+	if err != nil {
+		n.Error = err.Error()
+	}
+	c.notify(Notice{Kind: NoticeCommand, Time: time.Now(), Command: n})
+}
+
+// waitConfirmation waits for the answers to an activation
+func (c *IEC104Client) waitConfirmation(ctx context.Context, ch chan asdu.CauseOfTransmission, waitTerm bool) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case coa := <-ch:
+			if coa.IsNegative {
+				return fmt.Errorf("%w: %s", ErrorNegativeConfirmation, coa)
+			}
+			switch coa.Cause {
+			case asdu.ActivationCon:
+				if !waitTerm {
+					return nil
+				}
+			case asdu.ActivationTerm:
+				// without waitTerm, a termination can only be the late one
+				// of the previous command to the address
+				if waitTerm {
+					return nil
+				}
+			case asdu.UnknownTypeID, asdu.UnknownCOT, asdu.UnknownCA, asdu.UnknownIOA:
+				return fmt.Errorf("%w: %s", ErrorNegativeConfirmation, coa)
+			}
+		}
+	}
+}
+
+// sendContext retries a send while the link is still starting up
+func (c *IEC104Client) sendContext(ctx context.Context, send func(asdu.Connect) error) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		c.mu.Lock()
+		client := c.client
+		c.mu.Unlock()
+
+		if client != nil && c.Connected.Load() {
+			err := send(client)
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, cs104.ErrNotActive) && !errors.Is(err, cs104.ErrBufferFulled) {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if client == nil || !c.Connected.Load() {
+				return ErrorNoConnection
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleConfirmation routes a command mirror from the server to its waiter
+func (c *IEC104Client) handleConfirmation(a *asdu.ASDU) {
+	var ioa int
+	if a.Identifier.Type != asdu.C_IC_NA_1 && a.Identifier.Type != asdu.C_CI_NA_1 {
+		ioa = int(a.DecodeInfoObjAddr())
+	}
+	if !c.pending.notify(pendingKey{typ: a.Identifier.Type, ioa: ioa}, a.Coa) {
+		c.Logger.Debugf("Unexpected %s %s for address %d", a.Identifier.Type, a.Coa, ioa)
+	}
+}
+
+// isCommandType reports whether the type identification is a process command
+func isCommandType(t asdu.TypeID) bool {
+	return (t >= asdu.C_SC_NA_1 && t <= asdu.C_BO_NA_1) || (t >= asdu.C_SC_TA_1 && t <= asdu.C_BO_TA_1)
+}
+
+// CommandKinds are the command kinds understood by ParseCommand
+var CommandKinds = []string{"sc", "dc", "se-nc", "se-nb", "se-na"}
+
+// CommandFunc sends a command and waits for its confirmation
+type CommandFunc func(ctx context.Context, c *IEC104Client) error
+
+// ParseCommand builds a command from its kind and textual value: sc (single),
+// dc (double), se-nc (float setpoint), se-nb (scaled setpoint) or se-na
+// (normalized setpoint). Switch values are on/off, setpoints numbers.
+func ParseCommand(kind string, ioa int, value string, selectFirst bool) (CommandFunc, error) {
+	switch kind {
+	case "sc":
+		on, err := ParseSwitch(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *IEC104Client) error {
+			return c.SingleCommandContext(ctx, ioa, on, selectFirst)
+		}, nil
+	case "dc":
+		on, err := ParseSwitch(value)
+		if err != nil {
+			return nil, err
+		}
+		dco := asdu.DCOOff
+		if on {
+			dco = asdu.DCOOn
+		}
+		return func(ctx context.Context, c *IEC104Client) error {
+			return c.DoubleCommandContext(ctx, ioa, dco, selectFirst)
+		}, nil
+	case "se-nc":
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *IEC104Client) error {
+			return c.SetpointFloatContext(ctx, ioa, float32(f))
+		}, nil
+	case "se-nb":
+		n, err := strconv.ParseInt(value, 10, 16)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, c *IEC104Client) error {
+			return c.SetpointScaledContext(ctx, ioa, int16(n))
+		}, nil
+	case "se-na":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		if f < -1 || f > 1 {
+			return nil, fmt.Errorf("normalized value %v out of range [-1, 1]", f)
+		}
+		return func(ctx context.Context, c *IEC104Client) error {
+			return c.SetpointNormalContext(ctx, ioa, f)
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w %q", ErrorUnknownCommand, kind)
+	}
+}
+
+// ParseSwitch parses an on/off command value
+func ParseSwitch(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "1", "true", "close":
+		return true, nil
+	case "off", "0", "false", "open":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid switch value %q", value)
+}
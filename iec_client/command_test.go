@@ -0,0 +1,199 @@
+package iec_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"iec104/simulator"
+	"iec104/simulator/simtest"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+func TestCommands(t *testing.T) {
+	sim, c := connectStation(t)
+	sub := c.Subscribe(SubscribeOptions{Notices: true})
+	defer sub.Close()
+
+	tests := []struct {
+		name    string
+		kind    string
+		ioa     int
+		value   string
+		sbo     bool
+		outcome CommandOutcome
+	}{
+		{"single", "sc", TelecontrolBaseAddress, "off", false, CommandConfirmed},
+		{"select before operate", "sc", TelecontrolBaseAddress, "on", true, CommandConfirmed},
+		{"rejected", "sc", TelecontrolBaseAddress + 1, "on", false, CommandNegative},
+		{"rejected select", "sc", TelecontrolBaseAddress + 1, "on", true, CommandNegative},
+		{"unknown point", "sc", TelecontrolBaseAddress + 100, "on", false, CommandNegative},
+		{"float setpoint", "se-nc", TeleregulationBaseAddress, "42.5", false, CommandConfirmed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := ParseCommand(tt.kind, tt.ioa, tt.value, tt.sbo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err = cmd(ctx, c)
+			if got := Outcome(err); got != tt.outcome {
+				t.Fatalf("outcome = %s (%v), want %s", got, err, tt.outcome)
+			}
+			if tt.outcome == CommandNegative && !errors.Is(err, ErrorNegativeConfirmation) {
+				t.Errorf("error %v is not a negative confirmation", err)
+			}
+
+			var notices []CommandNotice
+			timeout := time.After(time.Second)
+		drain:
+			for {
+				select {
+				case n := <-sub.N:
+					if n.Kind == NoticeCommand {
+						notices = append(notices, n.Command)
+						// give a second notice the chance to arrive
+						timeout = time.After(100 * time.Millisecond)
+					}
+				case <-timeout:
+					break drain
+				}
+			}
+			if len(notices) != 1 || notices[0].Address != tt.ioa || notices[0].Outcome != tt.outcome {
+				t.Errorf("command notices = %+v, want one %s", notices, tt.outcome)
+			}
+		})
+	}
+
+	// the simulator applies the setpoint to its feedback point
+	if v, _, _ := sim.Value(TelemetryBaseAddress); v != 42.5 {
+		t.Errorf("telemetry after setpoint = %v, want 42.5", v)
+	}
+
+	counts := make(map[CommandOutcome]uint64)
+	for _, cc := range c.Stats().Commands {
+		if cc.Type == asdu.C_SC_NA_1 {
+			counts[cc.Outcome] += cc.Count
+		}
+	}
+	// a select and its execute count once
+	if counts[CommandConfirmed] != 2 || counts[CommandNegative] != 3 {
+		t.Errorf("single command counts = %v, want 2 confirmed and 3 negative", counts)
+	}
+}
+
+func TestCommandPending(t *testing.T) {
+	_, c := connectStation(t)
+	key := pendingKey{typ: asdu.C_SC_NA_1, ioa: TelecontrolBaseAddress}
+	if _, err := c.pending.add(key); err != nil {
+		t.Fatal(err)
+	}
+	defer c.pending.remove(key)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := c.SingleCommandContext(ctx, TelecontrolBaseAddress, true, false); !errors.Is(err, ErrorCommandPending) {
+		t.Errorf("second command = %v, want %v", err, ErrorCommandPending)
+	}
+}
+
+func TestConcurrentSelectExecute(t *testing.T) {
+	sim, c := connectStation(t)
+	// the station reports each command and holds it until the test proceeds
+	selects := make(chan bool)
+	proceed := make(chan struct{})
+	sim.RegisterCommandHandler(func(cmd simulator.Command) {
+		select {
+		case selects <- cmd.Select:
+		case <-time.After(5 * time.Second):
+			return
+		}
+		select {
+		case <-proceed:
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	key := pendingKey{typ: asdu.C_SC_NA_1, ioa: TelecontrolBaseAddress}
+	waiter := func() chan asdu.CauseOfTransmission {
+		c.pending.mu.Lock()
+		defer c.pending.mu.Unlock()
+		return c.pending.waiters[key]
+	}
+	second := func(phase string) {
+		t.Helper()
+		if err := c.SingleCommandContext(ctx, TelecontrolBaseAddress, false, true); !errors.Is(err, ErrorCommandPending) {
+			t.Errorf("second command during the %s = %v, want %v", phase, err, ErrorCommandPending)
+		}
+	}
+
+	first := make(chan error, 1)
+	go func() { first <- c.SingleCommandContext(ctx, TelecontrolBaseAddress, true, true) }()
+	if inSelect := <-selects; !inSelect {
+		t.Fatal("first command did not start with a select")
+	}
+	held := waiter()
+	second("select")
+	proceed <- struct{}{}
+
+	if inSelect := <-selects; inSelect {
+		t.Fatal("select was followed by another select")
+	}
+	// the address was not released between the select and the execute
+	if waiter() != held {
+		t.Error("address was released between the select and the execute")
+	}
+	second("execute")
+	proceed <- struct{}{}
+
+	if err := <-first; err != nil {
+		t.Fatal(err)
+	}
+	if waiter() != nil {
+		t.Error("address still pending after the execute")
+	}
+}
+
+func TestCommandDisconnected(t *testing.T) {
+	c := newTestClient(t, simtest.Profile(t, "127.0.0.1:1"))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := c.SingleCommandContext(ctx, TelecontrolBaseAddress, true, false); Outcome(err) != CommandFailed {
+		t.Errorf("command without a link = %v, want failed", err)
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		kind    string
+		value   string
+		wantErr bool
+	}{
+		{"sc", "on", false},
+		{"sc", "Open", false},
+		{"sc", "maybe", true},
+		{"dc", "close", false},
+		{"se-nc", "1.5", false},
+		{"se-nc", "x", true},
+		{"se-nb", "-32768", false},
+		{"se-nb", "40000", true},
+		{"se-na", "0.5", false},
+		{"se-na", "2", true},
+		{"xx", "on", true},
+	}
+	for _, tt := range tests {
+		_, err := ParseCommand(tt.kind, TelecontrolBaseAddress, tt.value, false)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCommand(%q, %q) error = %v, want error %v", tt.kind, tt.value, err, tt.wantErr)
+		}
+	}
+	if _, err := ParseCommand("xx", 1, "on", false); !errors.Is(err, ErrorUnknownCommand) {
+		t.Errorf("unknown kind error = %v, want %v", err, ErrorUnknownCommand)
+	}
+}
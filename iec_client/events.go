@@ -0,0 +1,156 @@
+package iec_client
+
+import (
+	"fmt"
+	"iec104/config"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// DefaultEventLogSize is the number of events kept in the sequence-of-events log
+const DefaultEventLogSize = 10000
+
+// EventKind classifies sequence-of-events entries
+type EventKind int
+
+const (
+	// EventChange is a new state or quality of a digital point
+	EventChange EventKind = iota
+	// EventThreshold is a telemetry value leaving or re-entering its configured range
+	EventThreshold
+	// EventAlarm is an alarm being raised, changed or cleared
+	EventAlarm
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventChange:
+		return "Change"
+	case EventThreshold:
+		return "Threshold"
+	case EventAlarm:
+		return "Alarm"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event is an entry of the sequence-of-events log
+type Event struct {
+	Update
+	Kind    EventKind
+	Message string
+}
+
+// Events returns the sequence-of-events log in the order the events were received
+func (c *IEC104Client) Events() []Event {
+	c.dataMu.RLock()
+	defer c.dataMu.RUnlock()
+
+	return c.events.ordered()
+}
+
+// EventCount returns the number of events recorded since the client was
+// created, which tells pollers whether Events has changed
+func (c *IEC104Client) EventCount() uint64 {
+	c.dataMu.RLock()
+	defer c.dataMu.RUnlock()
+
+	return c.eventCount
+}
+
+// ClearEvents empties the sequence-of-events log
+func (c *IEC104Client) ClearEvents() {
+	c.dataMu.Lock()
+	defer c.dataMu.Unlock()
+
+	c.events = ring[Event]{}
+	c.eventCount++
+}
+
+// addEvent appends to the sequence-of-events log; the caller holds dataMu
+func (c *IEC104Client) addEvent(e Event) {
+	c.events.add(e, DefaultEventLogSize)
+	c.eventCount++
+	c.notify(Notice{Kind: NoticeEvent, Time: e.Received, Event: e})
+}
+
+// stateEvent records a change of a single point; the caller holds dataMu
+func (c *IEC104Client) stateEvent(u Update, prev TeleindPoint, had bool) {
+	if had && prev.Value == u.State && prev.Quality == u.Quality && !prev.Double {
+		return
+	}
+
+	point := c.conf.FindPoint(config.PointTeleindication, u.Address)
+	text, _ := point.StateLabel(u.State)
+	message := text
+	if had && prev.Value != u.State {
+		prevText, _ := point.StateLabel(prev.Value)
+		message = fmt.Sprintf("%s -> %s", prevText, text)
+	}
+	c.addEvent(Event{Update: u, Kind: EventChange, Message: qualityChange(message, prev.Quality, u.Quality, had)})
+}
+
+// doubleEvent records a change of a double point; the caller holds dataMu
+func (c *IEC104Client) doubleEvent(u Update, prev TeleindPoint, had bool) {
+	if had && prev.Double && prev.DoubleState == u.DoubleState && prev.Quality == u.Quality {
+		return
+	}
+
+	point := c.conf.FindPoint(config.PointTeleindication, u.Address)
+	text, _ := point.DoubleStateLabel(int(u.DoubleState))
+	message := text
+	if had && prev.Double && prev.DoubleState != u.DoubleState {
+		prevText, _ := point.DoubleStateLabel(int(prev.DoubleState))
+		message = fmt.Sprintf("%s -> %s", prevText, text)
+	}
+	c.addEvent(Event{Update: u, Kind: EventChange, Message: qualityChange(message, prev.Quality, u.Quality, had)})
+}
+
+// thresholdEvent records a telemetry value crossing the configured
+// min/max range; the caller holds dataMu
+func (c *IEC104Client) thresholdEvent(u Update, prev TelemetryPoint, had bool) {
+	point := c.conf.FindPoint(config.PointTelemetry, u.Address)
+	if !point.HasRange() {
+		return
+	}
+
+	side := rangeSide(point, u.Value)
+	if had && rangeSide(point, prev.Value) == side {
+		return
+	}
+	if !had && side == 0 {
+		return
+	}
+
+	var message string
+	switch side {
+	case 1:
+		message = fmt.Sprintf("above max %s: %s", point.FormatValue(point.Max), point.FormatValue(u.Value))
+	case -1:
+		message = fmt.Sprintf("below min %s: %s", point.FormatValue(point.Min), point.FormatValue(u.Value))
+	default:
+		message = fmt.Sprintf("back in range: %s", point.FormatValue(u.Value))
+	}
+	c.addEvent(Event{Update: u, Kind: EventThreshold, Message: message})
+}
+
+// rangeSide returns -1 below the point's range, 1 above it and 0 inside
+func rangeSide(point *config.Point, value float64) int {
+	switch {
+	case value > point.Max:
+		return 1
+	case value < point.Min:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// qualityChange appends a quality transition to an event message
+func qualityChange(message string, prev, cur asdu.QualityDescriptor, had bool) string {
+	if !had || prev == cur {
+		return message
+	}
+	return fmt.Sprintf("%s (quality %s -> %s)", message, QualityString(prev), QualityString(cur))
+}
@@ -0,0 +1,101 @@
+package iec_client
+
+import (
+	"testing"
+	"time"
+
+	"iec104/config"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+func TestStateEvents(t *testing.T) {
+	c := idleClient(t,
+		config.Point{Address: 1, Type: config.PointTeleindication, OnText: "CLOSED", OffText: "OPEN"},
+		config.Point{Address: 2, Type: config.PointTeleindication, OnText: "CLOSED", OffText: "OPEN"},
+	)
+	single := func(value bool, qds asdu.QualityDescriptor) func() {
+		return func() { c.updateTeleindication(1, asdu.Spontaneous, 1, value, qds, time.Time{}) }
+	}
+	double := func(value asdu.DoublePoint, qds asdu.QualityDescriptor) func() {
+		return func() { c.updateDoublePoint(1, asdu.Spontaneous, 2, value, qds, time.Time{}) }
+	}
+
+	// each step expects the message of the one event it records, or none
+	steps := []struct {
+		name    string
+		update  func()
+		message string
+	}{
+		{"single first value", single(true, asdu.QDSGood), "CLOSED"},
+		{"single unchanged", single(true, asdu.QDSGood), ""},
+		{"single change", single(false, asdu.QDSGood), "CLOSED -> OPEN"},
+		{"single quality", single(false, asdu.QDSInvalid), "OPEN (quality OK -> IV)"},
+		{"single change and quality", single(true, asdu.QDSGood), "OPEN -> CLOSED (quality IV -> OK)"},
+		{"double first value", double(asdu.DPIDeterminedOff, asdu.QDSGood), "OPEN"},
+		{"double unchanged", double(asdu.DPIDeterminedOff, asdu.QDSGood), ""},
+		{"double travelling", double(asdu.DPIIndeterminateOrIntermediate, asdu.QDSGood), "OPEN -> INTER"},
+		{"double change", double(asdu.DPIDeterminedOn, asdu.QDSGood), "INTER -> CLOSED"},
+		{"double faulty", double(asdu.DPIIndeterminate, asdu.QDSGood), "CLOSED -> FAULT"},
+		{"double quality", double(asdu.DPIIndeterminate, asdu.QDSNotTopical), "FAULT (quality OK -> NT)"},
+	}
+	for _, step := range steps {
+		before := c.EventCount()
+		events := len(c.Events())
+		step.update()
+
+		var got []Event
+		for _, e := range c.Events()[events:] {
+			if e.Kind == EventChange {
+				got = append(got, e)
+			}
+		}
+		switch {
+		case step.message == "" && len(got) > 0:
+			t.Errorf("%s: recorded %q", step.name, got[0].Message)
+		case step.message != "" && len(got) != 1:
+			t.Errorf("%s: recorded %d events, want %q", step.name, len(got), step.message)
+		case step.message != "" && got[0].Message != step.message:
+			t.Errorf("%s: message = %q, want %q", step.name, got[0].Message, step.message)
+		}
+		if step.message != "" && c.EventCount() == before {
+			t.Errorf("%s: event count unchanged", step.name)
+		}
+	}
+
+	// a single point reported as a double point records its first double state
+	events := len(c.Events())
+	c.updateDoublePoint(1, asdu.Spontaneous, 1, asdu.DPIDeterminedOn, asdu.QDSGood, time.Time{})
+	if got := c.Events()[events:]; len(got) != 1 || got[0].Message != "CLOSED" || !got[0].Double {
+		t.Errorf("single point turned double recorded %+v", got)
+	}
+}
+
+func TestThresholdEvents(t *testing.T) {
+	const ioa = TelemetryBaseAddress
+	c := idleClient(t, config.Point{Address: ioa, Type: config.PointTelemetry, Min: 0, Max: 100})
+
+	steps := []struct {
+		value   float64
+		message string
+	}{
+		{50, ""},
+		{120, "above max 100.00: 120.00"},
+		{130, ""},
+		{-5, "below min 0.00: -5.00"},
+		{20, "back in range: 20.00"},
+	}
+	for _, step := range steps {
+		events := len(c.Events())
+		c.updateTelemetry(1, asdu.Spontaneous, ioa, step.value, asdu.QDSGood, time.Time{})
+		var got string
+		for _, e := range c.Events()[events:] {
+			if e.Kind == EventThreshold {
+				got = e.Message
+			}
+		}
+		if got != step.message {
+			t.Errorf("value %g: event %q, want %q", step.value, got, step.message)
+		}
+	}
+}
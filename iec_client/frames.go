@@ -0,0 +1,392 @@
+package iec_client
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// DefaultFrameLogSize is the number of frames kept by the protocol monitor
+const DefaultFrameLogSize = 5000
+
+var (
+	ErrorShortFrame = fmt.Errorf("frame shorter than an APCI")
+	ErrorBadStart   = fmt.Errorf("frame does not start with 0x68")
+	ErrorBadLength  = fmt.Errorf("frame length does not match its APCI")
+)
+
+// FrameDirection tells whether a frame was received or sent
+type FrameDirection int
+
+const (
+	FrameReceived FrameDirection = iota
+	FrameSent
+)
+
+func (d FrameDirection) String() string {
+	if d == FrameSent {
+		return "TX"
+	}
+	return "RX"
+}
+
+// FrameFormat is the APCI format of a frame
+type FrameFormat int
+
+const (
+	// FrameI is an information transfer frame carrying an ASDU
+	FrameI FrameFormat = iota
+	// FrameS is a supervisory frame acknowledging I frames
+	FrameS
+	// FrameU is an unnumbered control frame: STARTDT, STOPDT or TESTFR
+	FrameU
+)
+
+func (f FrameFormat) String() string {
+	switch f {
+	case FrameI:
+		return "I"
+	case FrameS:
+		return "S"
+	default:
+		return "U"
+	}
+}
+
+// InfoObject is a decoded information object of an ASDU
+type InfoObject struct {
+	Address int
+	// Value is the numeric value: 0 or 1 for single points and commands,
+	// the double point state, the measured value or the counter reading
+	Value float64
+	// Text is the value as shown by the protocol monitor
+	Text    string
+	Quality asdu.QualityDescriptor
+	Time    time.Time
+}
+
+// Frame is an APDU exchanged with the server
+type Frame struct {
+	Time      time.Time
+	Direction FrameDirection
+	Format    FrameFormat
+	Raw       []byte
+	// SendSeq and RecvSeq are N(S) and N(R); S frames only carry N(R)
+	SendSeq uint16
+	RecvSeq uint16
+	// Function is the function of a U frame, e.g. "STARTDT act"
+	Function string
+	// Identifier and Objects are the decoded ASDU of an I frame
+	Identifier asdu.Identifier
+	Objects    []InfoObject
+	// Error describes why the ASDU of an I frame could not be decoded
+	Error string
+}
+
+// ParseFrame decodes a raw APDU, including the ASDU of an I frame, with
+// the given ASDU parameters
+func ParseFrame(raw []byte, params *asdu.Params) (Frame, error) {
+	f := Frame{Raw: raw}
+	switch {
+	case len(raw) < 6:
+		return f, ErrorShortFrame
+	case raw[0] != 0x68:
+		return f, ErrorBadStart
+	case int(raw[1])+2 != len(raw):
+		return f, ErrorBadLength
+	}
+
+	switch {
+	case raw[2]&0x01 == 0:
+		f.Format = FrameI
+		f.SendSeq = binary.LittleEndian.Uint16(raw[2:]) >> 1
+		f.RecvSeq = binary.LittleEndian.Uint16(raw[4:]) >> 1
+		a := asdu.NewEmptyASDU(params)
+		if err := a.UnmarshalBinary(raw[6:]); err != nil {
+			f.Error = err.Error()
+			return f, nil
+		}
+		f.Identifier = a.Identifier
+		f.Objects = DecodeObjects(a)
+	case raw[2]&0x03 == 0x01:
+		f.Format = FrameS
+		f.RecvSeq = binary.LittleEndian.Uint16(raw[4:]) >> 1
+	default:
+		f.Format = FrameU
+		f.Function = uFunction(raw[2])
+	}
+	return f, nil
+}
+
+func uFunction(b byte) string {
+	switch b {
+	case 0x07:
+		return "STARTDT act"
+	case 0x0B:
+		return "STARTDT con"
+	case 0x13:
+		return "STOPDT act"
+	case 0x23:
+		return "STOPDT con"
+	case 0x43:
+		return "TESTFR act"
+	case 0x83:
+		return "TESTFR con"
+	default:
+		return fmt.Sprintf("unknown 0x%02x", b)
+	}
+}
+
+// DecodeObjects decodes the information objects of the monitored and
+// command types the client handles; it consumes the ASDU's objects
+func DecodeObjects(a *asdu.ASDU) []InfoObject {
+	var objects []InfoObject
+	add := func(ioa asdu.InfoObjAddr, value float64, text string, qds asdu.QualityDescriptor, t time.Time) {
+		objects = append(objects, InfoObject{Address: int(ioa), Value: value, Text: text, Quality: qds, Time: t})
+	}
+
+	switch a.Type {
+	case asdu.M_SP_NA_1, asdu.M_SP_TA_1, asdu.M_SP_TB_1:
+		for _, d := range a.GetSinglePoint() {
+			add(d.Ioa, boolValue(d.Value), onOff(d.Value), d.Qds, d.Time)
+		}
+	case asdu.M_DP_NA_1, asdu.M_DP_TA_1, asdu.M_DP_TB_1:
+		for _, d := range a.GetDoublePoint() {
+			add(d.Ioa, float64(d.Value), DoublePointString(d.Value), d.Qds, d.Time)
+		}
+	case asdu.M_BO_NA_1, asdu.M_BO_TA_1, asdu.M_BO_TB_1:
+		for _, d := range a.GetBitString32() {
+			add(d.Ioa, float64(d.Value), fmt.Sprintf("0x%08x", d.Value), d.Qds, d.Time)
+		}
+	case asdu.M_ME_NA_1, asdu.M_ME_TA_1, asdu.M_ME_TD_1, asdu.M_ME_ND_1:
+		for _, d := range a.GetMeasuredValueNormal() {
+			add(d.Ioa, d.Value.Float64(), fmt.Sprintf("%.5f", d.Value.Float64()), d.Qds, d.Time)
+		}
+	case asdu.M_ME_NB_1, asdu.M_ME_TB_1, asdu.M_ME_TE_1:
+		for _, d := range a.GetMeasuredValueScaled() {
+			add(d.Ioa, float64(d.Value), fmt.Sprint(d.Value), d.Qds, d.Time)
+		}
+	case asdu.M_ME_NC_1, asdu.M_ME_TC_1, asdu.M_ME_TF_1:
+		for _, d := range a.GetMeasuredValueFloat() {
+			add(d.Ioa, float64(d.Value), fmt.Sprint(d.Value), d.Qds, d.Time)
+		}
+	case asdu.M_IT_NA_1, asdu.M_IT_TA_1, asdu.M_IT_TB_1:
+		for _, d := range a.GetIntegratedTotals() {
+			var qds asdu.QualityDescriptor
+			if d.Value.IsInvalid {
+				qds = asdu.QDSInvalid
+			}
+			add(d.Ioa, float64(d.Value.CounterReading), fmt.Sprintf("%d seq %d", d.Value.CounterReading, d.Value.SeqNumber), qds, d.Time)
+		}
+	case asdu.C_SC_NA_1, asdu.C_SC_TA_1:
+		d := a.GetSingleCmd()
+		add(d.Ioa, boolValue(d.Value), onOff(d.Value)+selectText(d.Qoc.InSelect), 0, d.Time)
+	case asdu.C_DC_NA_1, asdu.C_DC_TA_1:
+		d := a.GetDoubleCmd()
+		add(d.Ioa, float64(d.Value), fmt.Sprintf("DCO %d", d.Value)+selectText(d.Qoc.InSelect), 0, d.Time)
+	case asdu.C_SE_NA_1, asdu.C_SE_TA_1:
+		d := a.GetSetpointNormalCmd()
+		add(d.Ioa, d.Value.Float64(), fmt.Sprintf("%.5f", d.Value.Float64())+selectText(d.Qos.InSelect), 0, d.Time)
+	case asdu.C_SE_NB_1, asdu.C_SE_TB_1:
+		d := a.GetSetpointCmdScaled()
+		add(d.Ioa, float64(d.Value), fmt.Sprint(d.Value)+selectText(d.Qos.InSelect), 0, d.Time)
+	case asdu.C_SE_NC_1, asdu.C_SE_TC_1:
+		d := a.GetSetpointFloatCmd()
+		add(d.Ioa, float64(d.Value), fmt.Sprint(d.Value)+selectText(d.Qos.InSelect), 0, d.Time)
+	case asdu.C_IC_NA_1:
+		ioa, qoi := a.GetInterrogationCmd()
+		add(ioa, float64(qoi), fmt.Sprintf("QOI %d", qoi), 0, time.Time{})
+	case asdu.C_CI_NA_1:
+		ioa, qcc := a.GetCounterInterrogationCmd()
+		add(ioa, float64(qcc.Value()), fmt.Sprintf("QCC request %d freeze %d", qcc.Request, qcc.Freeze), 0, time.Time{})
+	case asdu.C_RD_NA_1:
+		add(a.GetReadCmd(), 0, "read", 0, time.Time{})
+	case asdu.C_CS_NA_1:
+		ioa, t := a.GetClockSynchronizationCmd()
+		add(ioa, 0, t.Format("2006-01-02 15:04:05.000"), 0, t)
+	case asdu.C_RP_NA_1:
+		ioa, qrp := a.GetResetProcessCmd()
+		add(ioa, float64(qrp), fmt.Sprintf("QRP %d", qrp), 0, time.Time{})
+	case asdu.C_CD_NA_1:
+		ioa, msec := a.GetDelayAcquireCommand()
+		add(ioa, float64(msec), fmt.Sprintf("%d ms", msec), 0, time.Time{})
+	}
+	return objects
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func onOff(b bool) string {
+	if b {
+		return "ON"
+	}
+	return "OFF"
+}
+
+func selectText(inSelect bool) string {
+	if inSelect {
+		return " select"
+	}
+	return " execute"
+}
+
+// DoublePointString names a double point state
+func DoublePointString(v asdu.DoublePoint) string {
+	switch v {
+	case asdu.DPIDeterminedOff:
+		return "OFF"
+	case asdu.DPIDeterminedOn:
+		return "ON"
+	case asdu.DPIIndeterminateOrIntermediate:
+		return "INTER"
+	default:
+		return "FAULT"
+	}
+}
+
+// Summary describes a frame in one line: the APCI for S and U frames,
+// the ASDU identifier and objects for I frames
+func (f Frame) Summary() string {
+	switch f.Format {
+	case FrameS:
+		return fmt.Sprintf("S N(R)=%d", f.RecvSeq)
+	case FrameU:
+		return f.Function
+	}
+	if f.Error != "" {
+		return "ASDU error: " + f.Error
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s CA %d", f.Identifier.Type, CauseString(f.Identifier.Coa.Cause), f.Identifier.CommonAddr)
+	if f.Identifier.Coa.IsNegative {
+		b.WriteString(" neg")
+	}
+	if f.Identifier.Coa.IsTest {
+		b.WriteString(" test")
+	}
+	for i, o := range f.Objects {
+		if i == 0 {
+			b.WriteString(":")
+		}
+		fmt.Fprintf(&b, " %d=%s", o.Address, o.Text)
+		if o.Quality != asdu.QDSGood {
+			fmt.Fprintf(&b, "(%s)", QualityString(o.Quality))
+		}
+	}
+	return b.String()
+}
+
+// frameLog is the ring of frames shown by the protocol monitor
+type frameLog struct {
+	mu       sync.Mutex
+	frames   ring[Frame]
+	count    uint64
+	recorder FrameRecorder
+}
+
+// FrameRecorder receives every frame the client sends or receives, e.g. to
+// write a capture file
+type FrameRecorder interface {
+	WriteFrame(f Frame) error
+}
+
+// SetRecorder starts passing frames to the recorder, or stops when it is
+// nil. A recorder that fails is dropped.
+func (c *IEC104Client) SetRecorder(r FrameRecorder) {
+	c.frames.mu.Lock()
+	defer c.frames.mu.Unlock()
+
+	c.frames.recorder = r
+}
+
+// Frames returns the captured frames, oldest first
+func (c *IEC104Client) Frames() []Frame {
+	c.frames.mu.Lock()
+	defer c.frames.mu.Unlock()
+
+	return c.frames.frames.ordered()
+}
+
+// FrameCount returns the number of frames captured since the client was
+// created, which tells pollers whether Frames has changed
+func (c *IEC104Client) FrameCount() uint64 {
+	c.frames.mu.Lock()
+	defer c.frames.mu.Unlock()
+
+	return c.frames.count
+}
+
+// ClearFrames empties the frame log
+func (c *IEC104Client) ClearFrames() {
+	c.frames.mu.Lock()
+	defer c.frames.mu.Unlock()
+
+	c.frames.frames = ring[Frame]{}
+	c.frames.count++
+}
+
+// captureFrame decodes and stores a raw APDU; raw is copied since the
+// library reuses its buffers
+func (c *IEC104Client) captureFrame(dir FrameDirection, raw []byte, params *asdu.Params) {
+	data := append([]byte(nil), raw...)
+	f, err := ParseFrame(data, params)
+	if err != nil {
+		f.Error = err.Error()
+	}
+	f.Time = time.Now()
+	f.Direction = dir
+	c.addFrame(f)
+}
+
+// addFrame counts and stores a frame and passes it to the recorder
+func (c *IEC104Client) addFrame(f Frame) {
+	c.stats.frame(f)
+
+	c.frames.mu.Lock()
+	defer c.frames.mu.Unlock()
+
+	c.frames.frames.add(f, DefaultFrameLogSize)
+	c.frames.count++
+	if c.frames.recorder != nil {
+		if err := c.frames.recorder.WriteFrame(f); err != nil {
+			c.Logger.Errorf("Error recording frame, recording stopped: %v", err)
+			c.frames.recorder = nil
+		}
+	}
+}
+
+// libraryLog passes the library's log output to the client's log; the
+// library's warnings are logged as info
+type libraryLog struct {
+	c   *IEC104Client
+	tap *tap
+}
+
+func (l libraryLog) Critical(format string, v ...interface{}) {
+	l.c.Logger.Errorf(format, v...)
+}
+
+func (l libraryLog) Error(format string, v ...interface{}) {
+	// closing the connection on Disconnect fails the library's reads
+	if !l.tap.closed() {
+		l.c.Logger.Errorf(format, v...)
+	}
+}
+
+func (l libraryLog) Warn(format string, v ...interface{}) {
+	l.c.Logger.Infof(format, v...)
+}
+
+func (l libraryLog) Debug(format string, v ...interface{}) {
+	l.c.Logger.Debugf(format, v...)
+}
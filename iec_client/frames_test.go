@@ -0,0 +1,144 @@
+package iec_client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+var (
+	startAct = []byte{0x68, 0x04, 0x07, 0x00, 0x00, 0x00}
+	startCon = []byte{0x68, 0x04, 0x0b, 0x00, 0x00, 0x00}
+	sFrame   = []byte{0x68, 0x04, 0x01, 0x00, 0x0a, 0x00}
+)
+
+func TestParseFrame(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      []byte
+		format   FrameFormat
+		function string
+		err      error
+	}{
+		{"startdt act", startAct, FrameU, "STARTDT act", nil},
+		{"startdt con", startCon, FrameU, "STARTDT con", nil},
+		{"s frame", sFrame, FrameS, "", nil},
+		{"short", []byte{0x68, 0x04, 0x07}, 0, "", ErrorShortFrame},
+		{"bad start", []byte{0x67, 0x04, 0x07, 0x00, 0x00, 0x00}, 0, "", ErrorBadStart},
+		{"bad length", []byte{0x68, 0x05, 0x07, 0x00, 0x00, 0x00}, 0, "", ErrorBadLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFrame(tt.raw, asdu.ParamsWide)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if f.Format != tt.format || f.Function != tt.function {
+				t.Errorf("frame = %s %q, want %s %q", f.Format, f.Function, tt.format, tt.function)
+			}
+		})
+	}
+
+	f, err := ParseFrame(sFrame, asdu.ParamsWide)
+	if err != nil || f.RecvSeq != 5 {
+		t.Errorf("S frame N(R) = %d, %v; want 5", f.RecvSeq, err)
+	}
+}
+
+func TestFrameSplitter(t *testing.T) {
+	joined := append(append([]byte(nil), startAct...), startCon...)
+	tests := []struct {
+		name   string
+		writes [][]byte
+		want   [][]byte
+	}{
+		{"one", [][]byte{startAct}, [][]byte{startAct}},
+		{"two in one read", [][]byte{joined}, [][]byte{startAct, startCon}},
+		{"split", [][]byte{joined[:3], joined[3:8], joined[8:]}, [][]byte{startAct, startCon}},
+		{"garbage first", [][]byte{{0x00, 0x01}, startAct}, [][]byte{startAct}},
+		{"short length", [][]byte{{0x68, 0x02}, startCon}, [][]byte{startCon}},
+		{"incomplete", [][]byte{startAct[:5]}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s frameSplitter
+			var got [][]byte
+			for _, w := range tt.writes {
+				s.write(w, func(raw []byte) { got = append(got, append([]byte(nil), raw...)) })
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d frames, want %d: % x", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !bytes.Equal(got[i], tt.want[i]) {
+					t.Errorf("frame %d = % x, want % x", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+type recorder struct {
+	frames chan Frame
+}
+
+func (r recorder) WriteFrame(f Frame) error {
+	select {
+	case r.frames <- f:
+	default:
+	}
+	return nil
+}
+
+func TestCapturedFrames(t *testing.T) {
+	_, c := connectStation(t)
+	rec := recorder{frames: make(chan Frame, 100)}
+	c.SetRecorder(rec)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.InterrogateContext(ctx, asdu.QOIStation); err != nil {
+		t.Fatal(err)
+	}
+
+	// the tap saw the link come up before the recorder was set
+	frames := c.Frames()
+	if len(frames) < 2 || frames[0].Direction != FrameSent || frames[0].Function != "STARTDT act" ||
+		frames[1].Direction != FrameReceived || frames[1].Function != "STARTDT con" {
+		t.Fatalf("first frames = %+v", frames)
+	}
+
+	var sent, confirmed bool
+	for !confirmed {
+		select {
+		case f := <-rec.frames:
+			if f.Format != FrameI || f.Identifier.Type != asdu.C_IC_NA_1 {
+				continue
+			}
+			switch {
+			case f.Direction == FrameSent && f.Identifier.Coa.Cause == asdu.Activation:
+				sent = true
+			case f.Direction == FrameReceived && f.Identifier.Coa.Cause == asdu.ActivationCon:
+				confirmed = sent
+			}
+		case <-ctx.Done():
+			t.Fatal("interrogation frames not recorded")
+		}
+	}
+
+	if s := c.Stats(); s.Sent.I == 0 || s.Received.I == 0 || s.Sent.U == 0 {
+		t.Errorf("frame counts sent %+v received %+v", s.Sent, s.Received)
+	}
+	n := c.FrameCount()
+	c.ClearFrames()
+	if len(c.Frames()) != 0 || c.FrameCount() == n {
+		t.Error("ClearFrames kept the frames or the count")
+	}
+}
@@ -0,0 +1,100 @@
+package iec_client
+
+import (
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// DefaultHistorySize is the number of samples kept per telemetry point
+const DefaultHistorySize = 600
+
+// Sample is one recorded telemetry value
+type Sample struct {
+	// Time is the local time the value arrived
+	Time    time.Time
+	Value   float64
+	Raw     float64
+	Quality asdu.QualityDescriptor
+}
+
+// ring is a fixed size buffer keeping the newest items
+type ring[T any] struct {
+	items []T
+	next  int
+	full  bool
+}
+
+func (r *ring[T]) add(item T, size int) {
+	if !r.full {
+		r.items = append(r.items, item)
+		if len(r.items) == size {
+			r.full = true
+		}
+		return
+	}
+	r.items[r.next] = item
+	r.next = (r.next + 1) % len(r.items)
+}
+
+// ordered returns a copy of the items, oldest first
+func (r *ring[T]) ordered() []T {
+	items := make([]T, 0, len(r.items))
+	if r.full {
+		items = append(items, r.items[r.next:]...)
+		return append(items, r.items[:r.next]...)
+	}
+	return append(items, r.items...)
+}
+
+// history is the sample buffer of one telemetry point
+type history struct {
+	ring[Sample]
+}
+
+// since returns the samples newer than t, oldest first
+func (h *history) since(t time.Time) []Sample {
+	ordered := h.ordered()
+	for i, s := range ordered {
+		if s.Time.After(t) {
+			return ordered[i:]
+		}
+	}
+	return nil
+}
+
+// SetHistorySize changes the number of samples kept per telemetry point.
+// Zero disables the history. Existing samples are discarded.
+func (c *IEC104Client) SetHistorySize(size int) {
+	c.dataMu.Lock()
+	defer c.dataMu.Unlock()
+
+	c.historySize = size
+	c.history = make(map[int]*history)
+}
+
+// History returns the recorded samples of a telemetry point newer than
+// since, oldest first
+func (c *IEC104Client) History(ioa int, since time.Time) []Sample {
+	c.dataMu.RLock()
+	defer c.dataMu.RUnlock()
+
+	h, ok := c.history[ioa]
+	if !ok {
+		return nil
+	}
+	return h.since(since)
+}
+
+// record appends a telemetry sample; the caller holds dataMu
+func (c *IEC104Client) record(ioa int, s Sample) {
+	if c.historySize <= 0 {
+		return
+	}
+	h, ok := c.history[ioa]
+	if !ok {
+		h = &history{}
+		c.history[ioa] = h
+	}
+	h.add(s, c.historySize)
+}
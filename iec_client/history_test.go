@@ -0,0 +1,112 @@
+package iec_client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+func TestRing(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		added int
+		want  []int
+	}{
+		{"empty", 3, 0, []int{}},
+		{"below capacity", 3, 2, []int{1, 2}},
+		{"at capacity", 3, 3, []int{1, 2, 3}},
+		{"wrapped", 3, 4, []int{2, 3, 4}},
+		{"wrapped twice", 3, 8, []int{6, 7, 8}},
+		{"single item", 1, 5, []int{5}},
+	}
+	for _, tt := range tests {
+		var r ring[int]
+		for i := 1; i <= tt.added; i++ {
+			r.add(i, tt.size)
+		}
+		got := r.ordered()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ordered = %v, want %v", tt.name, got, tt.want)
+		}
+		if len(r.items) > tt.size {
+			t.Errorf("%s: %d items kept, capacity %d", tt.name, len(r.items), tt.size)
+		}
+		// the result is a copy
+		if len(got) > 0 {
+			got[0] = -1
+			if r.ordered()[0] == -1 {
+				t.Errorf("%s: ordered shares the buffer", tt.name)
+			}
+		}
+	}
+}
+
+func TestHistorySince(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	var h history
+	for i := 0; i < 5; i++ {
+		h.add(Sample{Time: start.Add(time.Duration(i) * time.Second), Value: float64(i)}, 4)
+	}
+	tests := []struct {
+		since time.Time
+		want  int
+	}{
+		{time.Time{}, 4},
+		{start, 4},
+		{start.Add(2 * time.Second), 2},
+		{start.Add(2500 * time.Millisecond), 2},
+		{start.Add(4 * time.Second), 0},
+	}
+	for _, tt := range tests {
+		got := h.since(tt.since)
+		if len(got) != tt.want {
+			t.Errorf("since %v: %d samples, want %d", tt.since.Sub(start), len(got), tt.want)
+		}
+		if len(got) > 0 && got[len(got)-1].Value != 4 {
+			t.Errorf("since %v: newest sample %v, want 4", tt.since.Sub(start), got[len(got)-1].Value)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	const ioa = TelemetryBaseAddress
+	c := idleClient(t)
+	c.SetHistorySize(3)
+
+	start := time.Now()
+	for v := 1.0; v <= 5; v++ {
+		c.updateTelemetry(1, asdu.Spontaneous, ioa, v, asdu.QDSGood, time.Time{})
+	}
+	values := func(samples []Sample) []float64 {
+		got := []float64{}
+		for _, s := range samples {
+			got = append(got, s.Value)
+		}
+		return got
+	}
+
+	samples := c.History(ioa, time.Time{})
+	if got := values(samples); !reflect.DeepEqual(got, []float64{3, 4, 5}) {
+		t.Fatalf("history = %v, want the newest 3 oldest first", got)
+	}
+	for i := 1; i < len(samples); i++ {
+		if samples[i].Time.Before(samples[i-1].Time) {
+			t.Errorf("sample %d is older than the one before", i)
+		}
+	}
+	if got := c.History(ioa, time.Now().Add(time.Hour)); len(got) != 0 {
+		t.Errorf("history since the future = %v", values(got))
+	}
+	if got := c.History(ioa+1, start); got != nil {
+		t.Errorf("history of a point never received = %v", values(got))
+	}
+
+	c.SetHistorySize(0)
+	c.updateTelemetry(1, asdu.Spontaneous, ioa, 6, asdu.QDSGood, time.Time{})
+	if got := c.History(ioa, time.Time{}); got != nil {
+		t.Errorf("history with size 0 = %v", values(got))
+	}
+}
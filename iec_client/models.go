@@ -1,7 +1,16 @@
 package iec_client
 
 import (
+	"fmt"
+	"iec104/config"
+	"strings"
 	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+var (
+	ErrorUnknownDataType = fmt.Errorf("unknown data type")
 )
 
 // DataType represents the type of IEC104 data
@@ -18,6 +27,28 @@ const (
 	Teleregulation
 )
 
+// Default information object base addresses for each data type
+const (
+	TelemetryBaseAddress      = config.TelemetryBaseAddress
+	TeleindBaseAddress        = config.TeleindBaseAddress
+	TelecontrolBaseAddress    = config.TelecontrolBaseAddress
+	TeleregulationBaseAddress = config.TeleregulationBaseAddress
+)
+
+// PointType returns the matching configured point type
+func (d DataType) PointType() config.PointType {
+	switch d {
+	case Telemetry:
+		return config.PointTelemetry
+	case Teleindication:
+		return config.PointTeleindication
+	case Telecontrol:
+		return config.PointTelecontrol
+	default:
+		return config.PointTeleregulation
+	}
+}
+
 func (d DataType) String() string {
 	switch d {
 	case Telemetry:
@@ -33,23 +64,69 @@ func (d DataType) String() string {
 	}
 }
 
+// ParseDataType looks up a data type by its name, ignoring case
+func ParseDataType(name string) (DataType, error) {
+	for _, t := range []DataType{Telemetry, Teleindication, Telecontrol, Teleregulation} {
+		if strings.EqualFold(t.String(), name) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrorUnknownDataType, name)
+}
+
 // DataPoint represents a generic IEC104 data point
 type DataPoint struct {
 	Address     int
 	Description string
 	Timestamp   time.Time
+	Quality     asdu.QualityDescriptor
+	// Received is the local time the value arrived
+	Received time.Time
+	// Cause is the cause of transmission of the last value
+	Cause asdu.Cause
 }
 
 // TelemetryPoint represents a measured value (analog)
 type TelemetryPoint struct {
 	DataPoint
+	// Value is in engineering units, Raw is the value as received
 	Value float64
+	Raw   float64
+}
+
+func (p TelemetryPoint) update(ca int) Update {
+	return Update{
+		DataPoint:  p.DataPoint,
+		Type:       Telemetry,
+		CommonAddr: ca,
+		Value:      p.Value,
+		Raw:        p.Raw,
+	}
 }
 
 // TeleindPoint represents status information (digital)
 type TeleindPoint struct {
 	DataPoint
 	Value bool
+	// Double is set for double point indications, whose full state is in DoubleState
+	Double      bool
+	DoubleState asdu.DoublePoint
+}
+
+func (p TeleindPoint) update(ca int) Update {
+	var analog float64
+	if p.Value {
+		analog = 1
+	}
+	return Update{
+		DataPoint:   p.DataPoint,
+		Type:        Teleindication,
+		CommonAddr:  ca,
+		Value:       analog,
+		State:       p.Value,
+		Double:      p.Double,
+		DoubleState: p.DoubleState,
+	}
 }
 
 // TelecontrolPoint represents a command (digital control)
@@ -58,8 +135,62 @@ type TelecontrolPoint struct {
 	Value bool
 }
 
+func (p TelecontrolPoint) update(ca int) Update {
+	var analog float64
+	if p.Value {
+		analog = 1
+	}
+	return Update{
+		DataPoint:  p.DataPoint,
+		Type:       Telecontrol,
+		CommonAddr: ca,
+		Value:      analog,
+		State:      p.Value,
+	}
+}
+
 // TeleregulationPoint represents a setpoint (analog control)
 type TeleregulationPoint struct {
 	DataPoint
 	Value float64
 }
+
+func (p TeleregulationPoint) update(ca int) Update {
+	return Update{
+		DataPoint:  p.DataPoint,
+		Type:       Teleregulation,
+		CommonAddr: ca,
+		Value:      p.Value,
+	}
+}
+
+// CauseString returns the name of a cause of transmission, e.g. "Spontaneous"
+func CauseString(c asdu.Cause) string {
+	s := asdu.CauseOfTransmission{Cause: c}.String()
+	return strings.TrimSuffix(strings.TrimPrefix(s, "COT<"), ">")
+}
+
+// QualityString returns a compact representation of a quality descriptor
+func QualityString(q asdu.QualityDescriptor) string {
+	if q == asdu.QDSGood {
+		return "OK"
+	}
+
+	var flags []string
+	if q&asdu.QDSInvalid != 0 {
+		flags = append(flags, "IV")
+	}
+	if q&asdu.QDSNotTopical != 0 {
+		flags = append(flags, "NT")
+	}
+	if q&asdu.QDSSubstituted != 0 {
+		flags = append(flags, "SB")
+	}
+	if q&asdu.QDSBlocked != 0 {
+		flags = append(flags, "BL")
+	}
+	if q&asdu.QDSOverflow != 0 {
+		flags = append(flags, "OV")
+	}
+	return strings.Join(flags, ",")
+}
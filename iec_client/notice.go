@@ -0,0 +1,64 @@
+package iec_client
+
+import (
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// NoticeKind classifies the notices delivered next to the point updates
+type NoticeKind int
+
+const (
+	// NoticeConnection is the link coming up or going down
+	NoticeConnection NoticeKind = iota
+	// NoticeEvent is a new entry of the sequence-of-events log
+	NoticeEvent
+	// NoticeCommand is the outcome of a command or interrogation sent by the client
+	NoticeCommand
+)
+
+func (k NoticeKind) String() string {
+	switch k {
+	case NoticeConnection:
+		return "Connection"
+	case NoticeEvent:
+		return "Event"
+	case NoticeCommand:
+		return "Command"
+	default:
+		return "Unknown"
+	}
+}
+
+// CommandNotice describes one command sent and its outcome. Select and
+// execute of a command are one notice, with the time both took.
+type CommandNotice struct {
+	Type    asdu.TypeID
+	Address int
+	Outcome CommandOutcome
+	Error   string
+	Elapsed time.Duration
+}
+
+// Notice is a change besides a point update: Connected is set for
+// NoticeConnection, Event for NoticeEvent and Command for NoticeCommand
+type Notice struct {
+	Kind      NoticeKind
+	Time      time.Time
+	Connected bool
+	Event     Event
+	Command   CommandNotice
+}
+
+// notify hands a notice to the subscribers that asked for notices. It
+// never blocks, as it may be called with dataMu held.
+func (c *IEC104Client) notify(n Notice) {
+	c.subMu.RLock()
+	defer c.subMu.RUnlock()
+	for sub := range c.subscriptions {
+		if sub.notices != nil {
+			sub.deliverNotice(n)
+		}
+	}
+}
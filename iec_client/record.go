@@ -0,0 +1,61 @@
+package iec_client
+
+import (
+	"iec104/config"
+	"time"
+)
+
+// Record is the JSON representation of a point update used by the
+// headless commands and the gateways
+type Record struct {
+	Type       string     `json:"type"`
+	CommonAddr int        `json:"ca"`
+	Address    int        `json:"ioa"`
+	Name       string     `json:"name,omitempty"`
+	Value      float64    `json:"value"`
+	Raw        *float64   `json:"raw,omitempty"`
+	Unit       string     `json:"unit,omitempty"`
+	State      string     `json:"state,omitempty"`
+	Quality    string     `json:"quality"`
+	Cause      string     `json:"cot,omitempty"`
+	Timestamp  *time.Time `json:"timestamp,omitempty"`
+	Received   *time.Time `json:"received,omitempty"`
+}
+
+// NewRecord describes an update with the name, unit and state labels of its
+// configured point
+func NewRecord(profile *config.Profile, u Update) Record {
+	point := profile.FindPoint(u.Type.PointType(), u.Address)
+	r := Record{
+		Type:       u.Type.String(),
+		CommonAddr: u.CommonAddr,
+		Address:    u.Address,
+		Value:      u.Value,
+		Quality:    QualityString(u.Quality),
+		Cause:      CauseString(u.Cause),
+		Timestamp:  optionalTime(u.Timestamp),
+		Received:   optionalTime(u.Received),
+	}
+	if point != nil {
+		r.Name = point.Name
+	}
+	switch {
+	case u.Double:
+		r.State, _ = point.DoubleStateLabel(int(u.DoubleState))
+	case u.Type == Teleindication:
+		r.State, _ = point.StateLabel(u.State)
+	case u.Type == Telemetry:
+		r.Raw = &u.Raw
+		if point != nil {
+			r.Unit = point.Unit
+		}
+	}
+	return r
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
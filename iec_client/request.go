@@ -0,0 +1,139 @@
+package iec_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+var (
+	ErrorNoAddress    = fmt.Errorf("ioa or offset is required")
+	ErrorInvalidGroup = fmt.Errorf("invalid interrogation group")
+)
+
+// CommandRequest is a command as the gateways receive it, e.g.
+// {"id":"1","kind":"sc","ioa":24577,"value":"on"}
+type CommandRequest struct {
+	// ID is copied to the result so callers can match them up
+	ID   string `json:"id,omitempty"`
+	Kind string `json:"kind"`
+	// Address is the information object address of the command. Offset
+	// addresses the point relative to the base address of the kind's
+	// type instead, as SendTelecontrol and SendTelemetry do.
+	Address int  `json:"ioa,omitempty"`
+	Offset  *int `json:"offset,omitempty"`
+	// Value is on/off or a number, as a JSON string, bool or number
+	Value json.RawMessage `json:"value"`
+	// Select uses select before operate for sc and dc, the default
+	Select *bool `json:"select,omitempty"`
+}
+
+// CommandResult is the outcome of a command request
+type CommandResult struct {
+	ID      string         `json:"id,omitempty"`
+	Kind    string         `json:"kind"`
+	Address int            `json:"ioa,omitempty"`
+	Value   string         `json:"value"`
+	Outcome CommandOutcome `json:"outcome"`
+	Error   string         `json:"error,omitempty"`
+	// Elapsed is the time to the confirmation in milliseconds
+	Elapsed float64 `json:"elapsed_ms"`
+}
+
+// Parse validates the request and returns its command and address
+func (r CommandRequest) Parse() (CommandFunc, int, error) {
+	address := r.Address
+	if r.Offset != nil {
+		address = *r.Offset + TelecontrolBaseAddress
+		if strings.HasPrefix(r.Kind, "se-") {
+			address = *r.Offset + TeleregulationBaseAddress
+		}
+	}
+	selectFirst := r.Select == nil || *r.Select
+	send, err := ParseCommand(r.Kind, address, r.value(), selectFirst)
+	if err != nil {
+		return nil, address, err
+	}
+	if address <= 0 {
+		return nil, address, ErrorNoAddress
+	}
+	return send, address, nil
+}
+
+// Execute sends the requested command and waits for its confirmation. The
+// result describes the outcome; the error is returned as well for callers
+// that need to tell the failures apart.
+func (c *IEC104Client) Execute(ctx context.Context, r CommandRequest) (CommandResult, error) {
+	result := CommandResult{ID: r.ID, Kind: r.Kind, Value: r.value()}
+	send, address, err := r.Parse()
+	result.Address = address
+	if err == nil {
+		start := time.Now()
+		err = send(ctx, c)
+		result.Elapsed = Milliseconds(time.Since(start))
+	}
+	result.Outcome = Outcome(err)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, err
+}
+
+// value turns a JSON string, bool or number into the textual value
+// ParseCommand expects
+func (r CommandRequest) value() string {
+	var s string
+	if err := json.Unmarshal(r.Value, &s); err == nil {
+		return s
+	}
+	return strings.TrimSpace(string(r.Value))
+}
+
+// Milliseconds returns a duration as fractional milliseconds
+func Milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// InterrogationRequest asks for a general or counter interrogation
+type InterrogationRequest struct {
+	ID string `json:"id,omitempty"`
+	// Group is 0 for the whole station, or 1 to 16 for a general and 1 to 4
+	// for a counter interrogation
+	Group   int  `json:"group,omitempty"`
+	Counter bool `json:"counter,omitempty"`
+}
+
+// Interrogate runs the requested interrogation and waits for its
+// termination. The result has kind gi or ci.
+func (c *IEC104Client) Interrogate(ctx context.Context, r InterrogationRequest) (CommandResult, error) {
+	result := CommandResult{ID: r.ID, Kind: "gi", Value: "station"}
+	if r.Group > 0 {
+		result.Value = fmt.Sprintf("group %d", r.Group)
+	}
+
+	var err error
+	start := time.Now()
+	switch {
+	case r.Counter && r.Group >= 0 && r.Group <= 4:
+		result.Kind = "ci"
+		qcc := asdu.QualifierCountCall{Request: asdu.QCCTotal, Freeze: asdu.QCCFrzRead}
+		if r.Group > 0 {
+			qcc.Request = asdu.QCCGroup1 + asdu.QCCRequest(r.Group-1)
+		}
+		err = c.CounterInterrogateContext(ctx, qcc)
+	case !r.Counter && r.Group >= 0 && r.Group <= 16:
+		err = c.InterrogateContext(ctx, asdu.QOIStation+asdu.QualifierOfInterrogation(r.Group))
+	default:
+		err = fmt.Errorf("%w %d", ErrorInvalidGroup, r.Group)
+	}
+	result.Elapsed = Milliseconds(time.Since(start))
+	result.Outcome = Outcome(err)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, err
+}
@@ -0,0 +1,265 @@
+package iec_client
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// CommandOutcome is the result of a command sent with the Context functions
+type CommandOutcome string
+
+const (
+	CommandConfirmed CommandOutcome = "confirmed"
+	CommandNegative  CommandOutcome = "negative"
+	CommandTimeout   CommandOutcome = "timeout"
+	CommandFailed    CommandOutcome = "failed"
+)
+
+// Outcome classifies the error returned by a command
+func Outcome(err error) CommandOutcome {
+	switch {
+	case err == nil:
+		return CommandConfirmed
+	case errors.Is(err, ErrorNegativeConfirmation):
+		return CommandNegative
+	case errors.Is(err, context.DeadlineExceeded):
+		return CommandTimeout
+	default:
+		return CommandFailed
+	}
+}
+
+// FrameCounts counts the frames of one direction by format
+type FrameCounts struct {
+	I uint64
+	S uint64
+	U uint64
+}
+
+// Total returns the number of frames of all formats
+func (f FrameCounts) Total() uint64 {
+	return f.I + f.S + f.U
+}
+
+// ASDUCount is the number of ASDUs of a type and cause in one direction
+type ASDUCount struct {
+	Direction FrameDirection
+	Type      asdu.TypeID
+	Cause     asdu.Cause
+	Count     uint64
+}
+
+// CommandCount is the number of commands of a type with the same outcome
+type CommandCount struct {
+	Type    asdu.TypeID
+	Outcome CommandOutcome
+	Count   uint64
+}
+
+// RoundTrip summarizes the time from sending a command to its confirmation
+type RoundTrip struct {
+	Count   uint64
+	Total   time.Duration
+	Last    time.Duration
+	Average time.Duration
+	Max     time.Duration
+}
+
+// Stats are the link counters of a client since Since
+type Stats struct {
+	Since time.Time
+
+	// Connects counts links established, Reconnects those after the first
+	Connects    uint64
+	Reconnects  uint64
+	Disconnects uint64
+	// ConnectedSince is zero while disconnected
+	ConnectedSince time.Time
+	LastReceived   time.Time
+	LastSent       time.Time
+
+	Received FrameCounts
+	Sent     FrameCounts
+	// ASDUs are ordered by direction, type and cause
+	ASDUs []ASDUCount
+	// NegativeConfirmations counts received negative or unknown-cause answers
+	NegativeConfirmations uint64
+
+	// Interrogations counts general interrogations from activation to
+	// termination, whoever sent them
+	Interrogations    uint64
+	LastInterrogation time.Duration
+	MaxInterrogation  time.Duration
+
+	// Commands are the outcomes of the commands sent by this client, and
+	// CommandRoundTrip the confirmation times of the process commands
+	Commands         []CommandCount
+	CommandRoundTrip RoundTrip
+}
+
+type asduKey struct {
+	dir   FrameDirection
+	typ   asdu.TypeID
+	cause asdu.Cause
+}
+
+type commandKey struct {
+	typ     asdu.TypeID
+	outcome CommandOutcome
+}
+
+// linkStats collects the counters behind Stats
+type linkStats struct {
+	mu       sync.Mutex
+	stats    Stats
+	asdus    map[asduKey]uint64
+	commands map[commandKey]uint64
+	// giStarted is the time of the pending interrogation activation
+	giStarted time.Time
+}
+
+// Stats returns a copy of the link counters
+func (c *IEC104Client) Stats() Stats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	s := c.stats.stats
+	for k, n := range c.stats.asdus {
+		s.ASDUs = append(s.ASDUs, ASDUCount{Direction: k.dir, Type: k.typ, Cause: k.cause, Count: n})
+	}
+	sort.Slice(s.ASDUs, func(i, j int) bool {
+		a, b := s.ASDUs[i], s.ASDUs[j]
+		if a.Direction != b.Direction {
+			return a.Direction < b.Direction
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Cause < b.Cause
+	})
+	for k, n := range c.stats.commands {
+		s.Commands = append(s.Commands, CommandCount{Type: k.typ, Outcome: k.outcome, Count: n})
+	}
+	sort.Slice(s.Commands, func(i, j int) bool {
+		a, b := s.Commands[i], s.Commands[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Outcome < b.Outcome
+	})
+	return s
+}
+
+// ResetStats zeroes the counters; the connection state is kept
+func (c *IEC104Client) ResetStats() {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	c.stats.reset()
+}
+
+func (l *linkStats) reset() {
+	l.stats = Stats{
+		Since:          time.Now(),
+		ConnectedSince: l.stats.ConnectedSince,
+		LastReceived:   l.stats.LastReceived,
+		LastSent:       l.stats.LastSent,
+	}
+	l.asdus = make(map[asduKey]uint64)
+	l.commands = make(map[commandKey]uint64)
+	l.giStarted = time.Time{}
+}
+
+// connected counts a link coming up or going down
+func (l *linkStats) connected(up bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if up {
+		l.stats.Connects++
+		if l.stats.Connects > 1 {
+			l.stats.Reconnects++
+		}
+		l.stats.ConnectedSince = time.Now()
+		return
+	}
+	l.stats.Disconnects++
+	l.stats.ConnectedSince = time.Time{}
+	l.giStarted = time.Time{}
+}
+
+// frame counts a frame sent or received
+func (l *linkStats) frame(f Frame) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	counts := &l.stats.Received
+	if f.Direction == FrameSent {
+		counts = &l.stats.Sent
+		l.stats.LastSent = f.Time
+	} else {
+		l.stats.LastReceived = f.Time
+	}
+	switch f.Format {
+	case FrameI:
+		counts.I++
+	case FrameS:
+		counts.S++
+	case FrameU:
+		counts.U++
+	}
+	if f.Format != FrameI || f.Error != "" {
+		return
+	}
+
+	id := f.Identifier
+	l.asdus[asduKey{f.Direction, id.Type, id.Coa.Cause}]++
+	if f.Direction == FrameReceived && (id.Coa.IsNegative || (id.Coa.Cause >= asdu.UnknownTypeID && id.Coa.Cause <= asdu.UnknownIOA)) {
+		l.stats.NegativeConfirmations++
+	}
+
+	if id.Type != asdu.C_IC_NA_1 {
+		return
+	}
+	switch {
+	case f.Direction == FrameSent && id.Coa.Cause == asdu.Activation:
+		l.giStarted = f.Time
+	case f.Direction == FrameReceived && id.Coa.Cause == asdu.ActivationTerm && !l.giStarted.IsZero():
+		d := f.Time.Sub(l.giStarted)
+		l.giStarted = time.Time{}
+		l.stats.Interrogations++
+		l.stats.LastInterrogation = d
+		if d > l.stats.MaxInterrogation {
+			l.stats.MaxInterrogation = d
+		}
+	case f.Direction == FrameReceived && id.Coa.IsNegative:
+		l.giStarted = time.Time{}
+	}
+}
+
+// command counts the outcome of a command and, for process commands that
+// were confirmed, the time it took
+func (l *linkStats) command(typ asdu.TypeID, elapsed time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	outcome := Outcome(err)
+	l.commands[commandKey{typ, outcome}]++
+
+	if outcome != CommandConfirmed || !isCommandType(typ) {
+		return
+	}
+	rtt := &l.stats.CommandRoundTrip
+	rtt.Count++
+	rtt.Last = elapsed
+	rtt.Total += elapsed
+	rtt.Average = rtt.Total / time.Duration(rtt.Count)
+	if elapsed > rtt.Max {
+		rtt.Max = elapsed
+	}
+}
@@ -0,0 +1,122 @@
+package iec_client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// iframe returns an I frame of the given type and cause
+func iframe(dir FrameDirection, typ asdu.TypeID, cause asdu.Cause, negative bool, at time.Time) Frame {
+	return Frame{Time: at, Direction: dir, Format: FrameI, Identifier: asdu.Identifier{
+		Type: typ,
+		Coa:  asdu.CauseOfTransmission{Cause: cause, IsNegative: negative},
+	}}
+}
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		err  error
+		want CommandOutcome
+	}{
+		{nil, CommandConfirmed},
+		{fmt.Errorf("select: %w", ErrorNegativeConfirmation), CommandNegative},
+		{context.DeadlineExceeded, CommandTimeout},
+		{ErrorNoConnection, CommandFailed},
+	}
+	for _, tt := range tests {
+		if got := Outcome(tt.err); got != tt.want {
+			t.Errorf("Outcome(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	c := idleClient(t)
+	start := time.Now()
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	c.stats.connected(true)
+	c.stats.connected(false)
+	c.stats.connected(true)
+	for _, f := range []Frame{
+		{Time: at(0), Direction: FrameSent, Format: FrameU},
+		{Time: at(1), Direction: FrameReceived, Format: FrameU},
+		iframe(FrameSent, asdu.C_IC_NA_1, asdu.Activation, false, at(10)),
+		iframe(FrameReceived, asdu.C_IC_NA_1, asdu.ActivationCon, false, at(20)),
+		iframe(FrameReceived, asdu.M_SP_NA_1, asdu.InterrogatedByStation, false, at(30)),
+		iframe(FrameReceived, asdu.M_ME_NC_1, asdu.InterrogatedByStation, false, at(40)),
+		iframe(FrameReceived, asdu.C_IC_NA_1, asdu.ActivationTerm, false, at(60)),
+		{Time: at(70), Direction: FrameSent, Format: FrameS},
+		// a termination without activation is not an interrogation
+		iframe(FrameReceived, asdu.C_IC_NA_1, asdu.ActivationTerm, false, at(80)),
+		iframe(FrameReceived, asdu.C_SC_NA_1, asdu.ActivationCon, true, at(90)),
+		iframe(FrameReceived, asdu.C_SC_NA_1, asdu.UnknownIOA, false, at(95)),
+		{Time: at(99), Direction: FrameReceived, Format: FrameI, Error: "short ASDU"},
+	} {
+		c.stats.frame(f)
+	}
+	c.stats.command(asdu.C_SC_NA_1, 20*time.Millisecond, nil)
+	c.stats.command(asdu.C_SC_NA_1, 40*time.Millisecond, nil)
+	c.stats.command(asdu.C_SC_NA_1, time.Second, ErrorNegativeConfirmation)
+	c.stats.command(asdu.C_IC_NA_1, 50*time.Millisecond, nil)
+
+	s := c.Stats()
+	if s.Connects != 2 || s.Reconnects != 1 || s.Disconnects != 1 || s.ConnectedSince.IsZero() {
+		t.Errorf("connects %d, reconnects %d, disconnects %d, connected since %v",
+			s.Connects, s.Reconnects, s.Disconnects, s.ConnectedSince)
+	}
+	if want := (FrameCounts{I: 8, U: 1}); s.Received != want {
+		t.Errorf("received %+v, want %+v", s.Received, want)
+	}
+	if want := (FrameCounts{I: 1, S: 1, U: 1}); s.Sent != want {
+		t.Errorf("sent %+v, want %+v", s.Sent, want)
+	}
+	if !s.LastSent.Equal(at(70)) || !s.LastReceived.Equal(at(99)) {
+		t.Errorf("last sent %v, last received %v", s.LastSent.Sub(start), s.LastReceived.Sub(start))
+	}
+	wantASDUs := []ASDUCount{
+		{FrameReceived, asdu.M_SP_NA_1, asdu.InterrogatedByStation, 1},
+		{FrameReceived, asdu.M_ME_NC_1, asdu.InterrogatedByStation, 1},
+		{FrameReceived, asdu.C_SC_NA_1, asdu.ActivationCon, 1},
+		{FrameReceived, asdu.C_SC_NA_1, asdu.UnknownIOA, 1},
+		{FrameReceived, asdu.C_IC_NA_1, asdu.ActivationCon, 1},
+		{FrameReceived, asdu.C_IC_NA_1, asdu.ActivationTerm, 2},
+		{FrameSent, asdu.C_IC_NA_1, asdu.Activation, 1},
+	}
+	if !reflect.DeepEqual(s.ASDUs, wantASDUs) {
+		t.Errorf("ASDUs\n%+v\nwant\n%+v", s.ASDUs, wantASDUs)
+	}
+	if s.NegativeConfirmations != 2 {
+		t.Errorf("negative confirmations %d, want 2", s.NegativeConfirmations)
+	}
+	if s.Interrogations != 1 || s.LastInterrogation != 50*time.Millisecond || s.MaxInterrogation != 50*time.Millisecond {
+		t.Errorf("interrogations %d, last %v, max %v", s.Interrogations, s.LastInterrogation, s.MaxInterrogation)
+	}
+	wantCommands := []CommandCount{
+		{asdu.C_SC_NA_1, CommandConfirmed, 2},
+		{asdu.C_SC_NA_1, CommandNegative, 1},
+		{asdu.C_IC_NA_1, CommandConfirmed, 1},
+	}
+	if !reflect.DeepEqual(s.Commands, wantCommands) {
+		t.Errorf("commands %+v, want %+v", s.Commands, wantCommands)
+	}
+	// only confirmed process commands count towards the round trip
+	if want := (RoundTrip{Count: 2, Total: 60 * time.Millisecond, Last: 40 * time.Millisecond,
+		Average: 30 * time.Millisecond, Max: 40 * time.Millisecond}); s.CommandRoundTrip != want {
+		t.Errorf("round trip %+v, want %+v", s.CommandRoundTrip, want)
+	}
+
+	c.ResetStats()
+	s = c.Stats()
+	if s.Connects != 0 || s.Received.Total() != 0 || len(s.ASDUs) != 0 || len(s.Commands) != 0 {
+		t.Errorf("counters after reset: %+v", s)
+	}
+	if s.ConnectedSince.IsZero() || !s.LastReceived.Equal(at(99)) || s.Since.Before(start) {
+		t.Errorf("reset dropped the connection state: %+v", s)
+	}
+}
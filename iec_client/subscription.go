@@ -0,0 +1,257 @@
+package iec_client
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// Update represents a single point change delivered to subscribers
+type Update struct {
+	DataPoint
+	Type       DataType
+	CommonAddr int
+	// Value holds the analog value in engineering units, or 1/0 for digital points
+	Value float64
+	// Raw holds the analog value as received, before scaling
+	Raw float64
+	// State holds the digital value for Teleindication and Telecontrol
+	State bool
+	// Double is set for double point indications, whose full state is in DoubleState
+	Double      bool
+	DoubleState asdu.DoublePoint
+}
+
+// Filter selects which updates a subscription receives.
+// Zero values match everything.
+type Filter struct {
+	Types       []DataType
+	MinAddress  int
+	MaxAddress  int
+	CommonAddrs []int
+}
+
+// Match reports whether the update passes the filter
+func (f Filter) Match(u Update) bool {
+	if len(f.Types) > 0 && !containsType(f.Types, u.Type) {
+		return false
+	}
+	if f.MinAddress > 0 && u.Address < f.MinAddress {
+		return false
+	}
+	if f.MaxAddress > 0 && u.Address > f.MaxAddress {
+		return false
+	}
+	if len(f.CommonAddrs) > 0 && !containsInt(f.CommonAddrs, u.CommonAddr) {
+		return false
+	}
+	return true
+}
+
+// DropPolicy decides what happens when a subscriber's buffer is full
+type DropPolicy int
+
+const (
+	// DropNewest discards the incoming update
+	DropNewest DropPolicy = iota
+	// DropOldest discards the oldest buffered update to make room
+	DropOldest
+	// BlockWithTimeout waits up to SubscribeOptions.Timeout, then discards the
+	// update. The timeout counts from the arrival of the update, so subscribers
+	// that block together delay the link by the longest timeout, not their sum.
+	BlockWithTimeout
+)
+
+// SubscribeOptions configures a subscription
+type SubscribeOptions struct {
+	Filter  Filter
+	Buffer  int
+	Policy  DropPolicy
+	Timeout time.Duration
+	// Notices also delivers connection changes, events and command outcomes
+	// on Subscription.N. They are never waited for: when the buffer is full
+	// the oldest notice is dropped.
+	Notices bool
+}
+
+// Subscription is a buffered stream of updates
+type Subscription struct {
+	C <-chan Update
+	// N is nil unless notices were requested
+	N <-chan Notice
+
+	ch      chan Update
+	notices chan Notice
+	opts    SubscribeOptions
+	client  *IEC104Client
+	dropped atomic.Uint64
+	once    sync.Once
+
+	// mu keeps Close from closing the channels during a delivery
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Dropped returns how many updates and notices were discarded for this subscriber
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes and closes the update channel
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.client.subMu.Lock()
+		delete(s.client.subscriptions, s)
+		s.client.subMu.Unlock()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+		close(s.ch)
+		if s.notices != nil {
+			close(s.notices)
+		}
+	})
+}
+
+// deliver hands the update to the subscriber according to its drop policy.
+// A blocking subscriber waits until its timeout has passed since the update
+// arrived.
+func (s *Subscription) deliver(u Update, arrived time.Time) {
+	if !s.opts.Filter.Match(u) {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.ch <- u:
+		return
+	default:
+	}
+
+	switch s.opts.Policy {
+	case DropOldest:
+		select {
+		case <-s.ch:
+			s.dropped.Add(1)
+		default:
+		}
+		select {
+		case s.ch <- u:
+			return
+		default:
+		}
+	case BlockWithTimeout:
+		timer := time.NewTimer(time.Until(arrived.Add(s.opts.Timeout)))
+		defer timer.Stop()
+		select {
+		case s.ch <- u:
+			return
+		case <-timer.C:
+		}
+	}
+	s.dropped.Add(1)
+}
+
+// deliverNotice hands a notice to the subscriber, dropping the oldest one
+// when the buffer is full. It must be called with the client's subMu read
+// lock held.
+func (s *Subscription) deliverNotice(n Notice) {
+	for i := 0; i < 2; i++ {
+		select {
+		case s.notices <- n:
+			return
+		default:
+		}
+		select {
+		case <-s.notices:
+			s.dropped.Add(1)
+		default:
+		}
+	}
+	s.dropped.Add(1)
+}
+
+// Subscribe registers a new subscriber. Updates are delivered without ever
+// blocking the network goroutine longer than the configured timeout.
+func (c *IEC104Client) Subscribe(opts SubscribeOptions) *Subscription {
+	if opts.Buffer <= 0 {
+		opts.Buffer = 256
+	}
+	if opts.Policy == BlockWithTimeout && opts.Timeout <= 0 {
+		opts.Timeout = 100 * time.Millisecond
+	}
+
+	ch := make(chan Update, opts.Buffer)
+	sub := &Subscription{
+		C:      ch,
+		ch:     ch,
+		opts:   opts,
+		client: c,
+	}
+	if opts.Notices {
+		sub.notices = make(chan Notice, opts.Buffer)
+		sub.N = sub.notices
+	}
+
+	c.subMu.Lock()
+	c.subscriptions[sub] = struct{}{}
+	c.subMu.Unlock()
+	return sub
+}
+
+// publish fans an update out to the legacy data handler and all subscribers
+func (c *IEC104Client) publish(u Update) {
+	if c.dataHandler != nil {
+		switch {
+		case u.Double:
+			c.dataHandler(u.Type, u.Address, u.DoubleState)
+		case u.Type == Teleindication, u.Type == Telecontrol:
+			c.dataHandler(u.Type, u.Address, u.State)
+		default:
+			c.dataHandler(u.Type, u.Address, u.Value)
+		}
+	}
+	c.deliver(u)
+}
+
+// deliver hands an update to the subscribers only
+func (c *IEC104Client) deliver(u Update) {
+	// deliver without the lock, so Subscribe and Close are not held up by
+	// blocking subscribers
+	c.subMu.RLock()
+	subs := make([]*Subscription, 0, len(c.subscriptions))
+	for sub := range c.subscriptions {
+		subs = append(subs, sub)
+	}
+	c.subMu.RUnlock()
+
+	arrived := time.Now()
+	for _, sub := range subs {
+		sub.deliver(u, arrived)
+	}
+}
+
+func containsType(types []DataType, t DataType) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
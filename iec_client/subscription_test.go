@@ -0,0 +1,180 @@
+package iec_client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"iec104/config"
+	"iec104/simulator"
+	"iec104/simulator/simtest"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+func TestFilterMatch(t *testing.T) {
+	u := Update{DataPoint: DataPoint{Address: 16390}, Type: Telemetry, CommonAddr: 1}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty", Filter{}, true},
+		{"type", Filter{Types: []DataType{Telemetry}}, true},
+		{"other type", Filter{Types: []DataType{Teleindication, Telecontrol}}, false},
+		{"in range", Filter{MinAddress: 16385, MaxAddress: 16390}, true},
+		{"below", Filter{MinAddress: 16391}, false},
+		{"above", Filter{MaxAddress: 16389}, false},
+		{"common address", Filter{CommonAddrs: []int{2, 1}}, true},
+		{"other common address", Filter{CommonAddrs: []int{2}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(u); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDropPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  DropPolicy
+		first   int
+		dropped uint64
+	}{
+		{"newest", DropNewest, 1, 2},
+		{"oldest", DropOldest, 3, 2},
+		{"block", BlockWithTimeout, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewIEC104Client(simtest.Profile(t, "127.0.0.1:1"))
+			sub := c.Subscribe(SubscribeOptions{Buffer: 2, Policy: tt.policy, Timeout: 10 * time.Millisecond})
+			for ioa := 1; ioa <= 4; ioa++ {
+				c.publish(Update{DataPoint: DataPoint{Address: ioa}, Type: Teleindication})
+			}
+			if u := <-sub.C; u.Address != tt.first {
+				t.Errorf("first update = %d, want %d", u.Address, tt.first)
+			}
+			if n := sub.Dropped(); n != tt.dropped {
+				t.Errorf("dropped = %d, want %d", n, tt.dropped)
+			}
+
+			sub.Close()
+			sub.Close()
+			<-sub.C
+			if _, ok := <-sub.C; ok {
+				t.Error("channel open after Close")
+			}
+			// publishing after Close neither panics nor delivers
+			c.publish(Update{DataPoint: DataPoint{Address: 5}, Type: Teleindication})
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	sim, c := connectStation(t)
+	sub := c.Subscribe(SubscribeOptions{Filter: Filter{Types: []DataType{Telemetry}}, Notices: true})
+	defer sub.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.InterrogateContext(ctx, asdu.QOIStation); err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[int]float64)
+	for len(got) < 2 {
+		select {
+		case u := <-sub.C:
+			if u.Type != Telemetry {
+				t.Fatalf("filtered subscription got %s %d", u.Type, u.Address)
+			}
+			got[u.Address] = u.Value
+		case <-ctx.Done():
+			t.Fatalf("interrogation delivered %v", got)
+		}
+	}
+	if got[TelemetryBaseAddress] != 12.5 || got[TelemetryBaseAddress+1] != 300 {
+		t.Errorf("interrogated telemetry = %v", got)
+	}
+
+	if err := sim.Set(TeleindBaseAddress, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.Set(TelemetryBaseAddress, 20); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case u := <-sub.C:
+		if u.Address != TelemetryBaseAddress || u.Value != 20 || u.Cause != asdu.Spontaneous {
+			t.Errorf("spontaneous update = %+v", u)
+		}
+	case <-ctx.Done():
+		t.Fatal("no spontaneous update")
+	}
+	if p, ok := c.Point(Teleindication, TeleindBaseAddress); !ok || p.State {
+		t.Errorf("teleindication after change = %+v, %v; want off", p, ok)
+	}
+
+	c.Disconnect()
+	for {
+		select {
+		case n := <-sub.N:
+			if n.Kind == NoticeConnection && !n.Connected {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("no disconnect notice")
+		}
+	}
+}
+
+func TestExtraCommonAddresses(t *testing.T) {
+	// the station answers as CA 7 only, which the profile lists as extra
+	sim, err := simulator.NewServer(&simulator.Database{CommonAddress: 7, Points: simtest.Points})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.Start("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+
+	profile := simtest.Profile(t, sim.Addr())
+	profile.ExtraCommonAddresses = []int{7}
+	profile.SetPoint(config.Point{Address: TelemetryBaseAddress, Type: config.PointTelemetry, Scale: 10})
+	c := newTestClient(t, profile)
+	simtest.Connect(t, c)
+	other := newTestClient(t, simtest.Profile(t, sim.Addr()))
+	simtest.Connect(t, other)
+
+	all := c.Subscribe(SubscribeOptions{})
+	defer all.Close()
+	own := c.Subscribe(SubscribeOptions{Filter: Filter{CommonAddrs: []int{1}}})
+	defer own.Close()
+	ignored := other.Subscribe(SubscribeOptions{})
+	defer ignored.Close()
+
+	if err := sim.Set(TelemetryBaseAddress, 20); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case u := <-all.C:
+		// the scaling of the profile's points is not applied to other stations
+		if u.CommonAddr != 7 || u.Address != TelemetryBaseAddress || u.Value != 20 || u.Raw != 20 {
+			t.Errorf("update from the extra station = %+v", u)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update from the extra station")
+	}
+	select {
+	case u := <-own.C:
+		t.Errorf("subscription filtered to CA 1 got %+v", u)
+	case u := <-ignored.C:
+		t.Errorf("profile without extra addresses got %+v", u)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if _, ok := c.Point(Telemetry, TelemetryBaseAddress); ok {
+		t.Error("value of the extra station stored as a point of the profile")
+	}
+}
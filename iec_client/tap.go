@@ -0,0 +1,262 @@
+package iec_client
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// claimTimeout bounds the wait for the library to claim an accepted
+// connection; the library does so right after its dial returns
+const claimTimeout = time.Second
+
+// tap relays the library's connection through a loopback listener to the
+// server and captures the frames passing in both directions. The library
+// has no hook for its dialer, so it is pointed at the tap instead. Only the
+// library's connection is relayed: it claims its connection in the
+// library's OnConnect, and connections nobody claims, or that arrive while
+// another one is relayed, are closed.
+type tap struct {
+	c        *IEC104Client
+	params   *asdu.Params
+	remote   string
+	timeout  time.Duration
+	retry    time.Duration
+	listener net.Listener
+
+	// onUp and onDown are called when a relayed connection reached the
+	// server and when it ended
+	onUp   func()
+	onDown func()
+
+	mu     sync.Mutex
+	active net.Conn
+	claims map[string]chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// newTap starts a tap that dials remote for every connection the library
+// makes; after a failed dial the library is held off for retry
+func newTap(c *IEC104Client, remote string, timeout, retry time.Duration) (*tap, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	t := &tap{
+		c:        c,
+		remote:   remote,
+		timeout:  timeout,
+		retry:    retry,
+		listener: l,
+		onUp:     func() {},
+		onDown:   func() {},
+		claims:   make(map[string]chan struct{}),
+		done:     make(chan struct{}),
+	}
+	return t, nil
+}
+
+// Start accepts the library's connections until Close
+func (t *tap) Start() {
+	go t.serve()
+}
+
+// Addr returns the address the library connects to
+func (t *tap) Addr() string {
+	return t.listener.Addr().String()
+}
+
+// Close stops accepting connections; relayed connections end with the
+// library's connection
+func (t *tap) Close() {
+	t.once.Do(func() {
+		close(t.done)
+		t.listener.Close()
+	})
+}
+
+// closed reports whether the tap was closed
+func (t *tap) closed() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// claim returns the channel that is closed once the library claimed the
+// connection from the given address
+func (t *tap) claim(addr string) chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ch, ok := t.claims[addr]
+	if !ok {
+		ch = make(chan struct{})
+		t.claims[addr] = ch
+	}
+	return ch
+}
+
+// release forgets the claim of a connection
+func (t *tap) release(addr string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.claims, addr)
+}
+
+// connected claims the library's connection conn for relaying
+func (t *tap) connected(conn net.Conn) {
+	if conn == nil {
+		return
+	}
+	ch := t.claim(conn.LocalAddr().String())
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-ch:
+	default:
+		close(ch)
+	}
+}
+
+// lost forgets the library's connection conn once the library dropped it
+func (t *tap) lost(conn net.Conn) {
+	if conn != nil {
+		t.release(conn.LocalAddr().String())
+	}
+}
+
+func (t *tap) serve() {
+	for {
+		local, err := t.listener.Accept()
+		if err != nil {
+			return
+		}
+		t.mu.Lock()
+		busy := t.active != nil
+		if !busy {
+			t.active = local
+		}
+		t.mu.Unlock()
+		if busy {
+			t.c.Logger.Errorf("Closed connection from %s to the relay of %s: another one is relayed", local.RemoteAddr(), t.remote)
+			local.Close()
+			continue
+		}
+		go t.relay(local)
+	}
+}
+
+// relay connects the library's connection to the server
+func (t *tap) relay(local net.Conn) {
+	addr := local.RemoteAddr().String()
+	defer func() {
+		local.Close()
+		t.release(addr)
+		t.mu.Lock()
+		t.active = nil
+		t.mu.Unlock()
+	}()
+
+	select {
+	case <-t.claim(addr):
+	case <-time.After(claimTimeout):
+		t.c.Logger.Errorf("Closed connection from %s to the relay of %s: not the client's", addr, t.remote)
+		return
+	case <-t.done:
+		return
+	}
+
+	remote, err := net.DialTimeout("tcp", t.remote, t.timeout)
+	if err != nil {
+		if !t.closed() {
+			t.c.Logger.Errorf("Error connecting to %s: %v", t.remote, err)
+		}
+		// the library reconnects right away once its connection drops
+		select {
+		case <-time.After(t.retry):
+		case <-t.done:
+		}
+		return
+	}
+	defer remote.Close()
+	// a tap closed meanwhile belongs to a replaced or disconnected client
+	if t.closed() {
+		return
+	}
+	t.onUp()
+	defer t.onDown()
+
+	stopped := make(chan struct{}, 2)
+	go func() {
+		t.copy(local, remote, FrameReceived)
+		stopped <- struct{}{}
+	}()
+	go func() {
+		t.copy(remote, local, FrameSent)
+		stopped <- struct{}{}
+	}()
+	<-stopped
+	local.Close()
+	remote.Close()
+	<-stopped
+}
+
+// copy passes src to dst unchanged and captures the frames in between
+func (t *tap) copy(dst io.Writer, src io.Reader, dir FrameDirection) {
+	var s frameSplitter
+	buf := make([]byte, 4096)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			s.write(buf[:n], func(raw []byte) {
+				t.c.captureFrame(dir, raw, t.params)
+			})
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// frameSplitter cuts a byte stream into APDUs the way the library reads
+// them: bytes before a start character are skipped
+type frameSplitter struct {
+	buf []byte
+}
+
+// write adds data to the stream and calls emit for every complete APDU
+func (s *frameSplitter) write(data []byte, emit func(raw []byte)) {
+	s.buf = append(s.buf, data...)
+	for {
+		i := bytes.IndexByte(s.buf, 0x68)
+		if i < 0 {
+			s.buf = s.buf[:0]
+			return
+		}
+		s.buf = s.buf[i:]
+		if len(s.buf) < 2 {
+			return
+		}
+		n := int(s.buf[1]) + 2
+		if n < 6 {
+			s.buf = s.buf[1:]
+			continue
+		}
+		if len(s.buf) < n {
+			return
+		}
+		emit(s.buf[:n])
+		s.buf = s.buf[n:]
+	}
+}
@@ -0,0 +1,90 @@
+package iec_client
+
+import (
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"iec104/simulator/simtest"
+)
+
+// closedByPeer reports whether the peer closes or resets conn without
+// sending anything
+func closedByPeer(t *testing.T, conn net.Conn) bool {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(claimTimeout + 2*time.Second))
+	_, err := conn.Read(make([]byte, 1))
+	var netErr net.Error
+	return err != nil && !(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+func TestTapRefusesOtherConnections(t *testing.T) {
+	sim, c := connectStation(t)
+	frames := c.FrameCount()
+
+	// the library's connection is relayed already
+	conn, err := net.Dial("tcp", c.tap.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Write(startAct)
+	if !closedByPeer(t, conn) {
+		t.Error("second connection to the tap was relayed")
+	}
+
+	// a connection the library did not make is not relayed either
+	tap, err := newTap(c, sim.Addr(), time.Second, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tap.Start()
+	defer tap.Close()
+	foreign, err := net.Dial("tcp", tap.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer foreign.Close()
+	foreign.Write(startAct)
+	if !closedByPeer(t, foreign) {
+		t.Error("connection the library did not claim was relayed")
+	}
+
+	if n := c.FrameCount(); n != frames {
+		t.Errorf("%d frames captured from refused connections", n-frames)
+	}
+	if !c.Connected.Load() {
+		t.Error("client lost its link")
+	}
+	time.Sleep(50 * time.Millisecond)
+	tap.mu.Lock()
+	defer tap.mu.Unlock()
+	if len(tap.claims) != 0 || tap.active != nil {
+		t.Errorf("refused connection left claims %v, active %v", tap.claims, tap.active)
+	}
+}
+
+func TestLibraryDebug(t *testing.T) {
+	sim := simtest.Station(t)
+	for _, debug := range []bool{false, true} {
+		logger := &countingLogger{}
+		c := NewIEC104Client(simtest.Profile(t, sim.Addr()))
+		c.Logger = logger
+		c.LibraryDebug = debug
+		simtest.Connect(t, c)
+		c.Close()
+		if got := logger.debug.Load() > 0; got != debug {
+			t.Errorf("LibraryDebug %v: library debug log written = %v", debug, got)
+		}
+	}
+}
+
+// countingLogger counts the debug messages
+type countingLogger struct {
+	simtest.NopLogger
+	debug atomic.Int64
+}
+
+func (l *countingLogger) Debugf(string, ...interface{}) { l.debug.Add(1) }
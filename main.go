@@ -1,16 +1,54 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"iec104/cli"
 	"iec104/config"
 	"iec104/ui"
+	"os"
 )
 
 func main() {
+	// Parse command line flags and environment overrides
+	opts, err := config.ParseOptions(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(cli.ExitOK)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(cli.ExitUsage)
+	}
+
 	// Initialize configuration
-	cfg := config.LoadFromDisk()
+	cfg, err := config.Load(opts.ConfigPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(cli.ExitError)
+	}
+	if err := opts.Apply(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(cli.ExitUsage)
+	}
+
+	// Run a headless command if one was given
+	if len(opts.Args) > 0 {
+		os.Exit(cli.Run(cfg, opts.Args))
+	}
 
 	// Initialize and start the UI
-	app := ui.NewApp(cfg)
+	app := ui.NewApp(cfg, ui.Options{
+		LogLevel:       ui.LoggerLevel(opts.LogLevel),
+		StartConnected: opts.StartConnected,
+		Replay:         opts.Replay,
+		Metrics:        opts.Metrics,
+		API:            opts.API,
+		APIToken:       opts.APIToken,
+		APIOrigins:     opts.APIOrigins,
+		GRPC:           opts.GRPC,
+		GRPCReflection: opts.GRPCReflection,
+	})
 	if err := app.Run(); err != nil {
 		panic(err)
 	}
@@ -0,0 +1,273 @@
+// Package metrics exposes the link counters, command outcomes and point
+// values of IEC104Client instances in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"iec104/iec_client"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Path is where the metrics are served
+const Path = "/metrics"
+
+// Exporter serves the metrics of a changing set of clients
+type Exporter struct {
+	mu      sync.Mutex
+	clients []*iec_client.IEC104Client
+}
+
+// NewExporter creates an exporter without clients
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// Add starts exporting a client, labelled with the name of its profile
+func (e *Exporter) Add(c *iec_client.IEC104Client) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, existing := range e.clients {
+		if existing == c {
+			return
+		}
+	}
+	e.clients = append(e.clients, c)
+}
+
+// Remove stops exporting a client
+func (e *Exporter) Remove(c *iec_client.IEC104Client) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i, existing := range e.clients {
+		if existing == c {
+			e.clients = append(e.clients[:i], e.clients[i+1:]...)
+			return
+		}
+	}
+}
+
+// Serve listens on addr and serves the metrics in the background until the
+// returned server is closed
+func (e *Exporter) Serve(addr string) (*http.Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(Path, e)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	return srv, nil
+}
+
+// ServeHTTP writes the metrics of all clients
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	clients := append([]*iec_client.IEC104Client(nil), e.clients...)
+	e.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	Write(w, clients, time.Now())
+}
+
+// target is the data gathered from one client for a scrape
+type target struct {
+	labels string
+	up     bool
+	stats  iec_client.Stats
+	points []iec_client.Update
+	names  []string
+}
+
+// Write writes the metrics of the clients at the given time
+func Write(out io.Writer, clients []*iec_client.IEC104Client, now time.Time) {
+	targets := make([]target, len(clients))
+	for i, c := range clients {
+		profile := c.Profile()
+		t := target{
+			labels: labels("profile", profile.Name, "server", fmt.Sprintf("%s:%d", profile.IPAddress, profile.Port)),
+			up:     c.Connected.Load(),
+			stats:  c.Stats(),
+			points: c.Snapshot(),
+		}
+		for _, u := range t.points {
+			t.names = append(t.names, profile.PointName(u.Type.PointType(), u.Address))
+		}
+		targets[i] = t
+	}
+
+	w := &writer{out: out}
+	w.family("iec104_up", "gauge", "Whether the link to the server is up.")
+	for _, t := range targets {
+		w.sample("iec104_up", t.labels, "", boolValue(t.up))
+	}
+	w.family("iec104_connected_since_seconds", "gauge", "Unix time the link came up, 0 while it is down.")
+	for _, t := range targets {
+		w.sample("iec104_connected_since_seconds", t.labels, "", unixSeconds(t.stats.ConnectedSince))
+	}
+	w.family("iec104_connects_total", "counter", "Links established.")
+	for _, t := range targets {
+		w.sample("iec104_connects_total", t.labels, "", float64(t.stats.Connects))
+	}
+	w.family("iec104_reconnects_total", "counter", "Links established again after the first.")
+	for _, t := range targets {
+		w.sample("iec104_reconnects_total", t.labels, "", float64(t.stats.Reconnects))
+	}
+	w.family("iec104_disconnects_total", "counter", "Links lost or closed.")
+	for _, t := range targets {
+		w.sample("iec104_disconnects_total", t.labels, "", float64(t.stats.Disconnects))
+	}
+
+	w.family("iec104_last_receive_timestamp_seconds", "gauge", "Unix time of the last frame received, 0 if none.")
+	for _, t := range targets {
+		w.sample("iec104_last_receive_timestamp_seconds", t.labels, "", unixSeconds(t.stats.LastReceived))
+	}
+	w.family("iec104_last_receive_age_seconds", "gauge", "Seconds since the last frame was received.")
+	for _, t := range targets {
+		if !t.stats.LastReceived.IsZero() {
+			w.sample("iec104_last_receive_age_seconds", t.labels, "", now.Sub(t.stats.LastReceived).Seconds())
+		}
+	}
+
+	w.family("iec104_frames_total", "counter", "APDUs by direction and format.")
+	for _, t := range targets {
+		for _, d := range []struct {
+			dir    string
+			counts iec_client.FrameCounts
+		}{{"rx", t.stats.Received}, {"tx", t.stats.Sent}} {
+			w.sample("iec104_frames_total", t.labels, labels("direction", d.dir, "format", "I"), float64(d.counts.I))
+			w.sample("iec104_frames_total", t.labels, labels("direction", d.dir, "format", "S"), float64(d.counts.S))
+			w.sample("iec104_frames_total", t.labels, labels("direction", d.dir, "format", "U"), float64(d.counts.U))
+		}
+	}
+	w.family("iec104_asdus_total", "counter", "ASDUs by direction, type and cause of transmission.")
+	for _, t := range targets {
+		for _, a := range t.stats.ASDUs {
+			w.sample("iec104_asdus_total", t.labels, labels("direction", strings.ToLower(a.Direction.String()),
+				"type", typeName(a.Type.String()), "cause", iec_client.CauseString(a.Cause)), float64(a.Count))
+		}
+	}
+	w.family("iec104_negative_confirmations_total", "counter", "Negative or unknown-cause answers received.")
+	for _, t := range targets {
+		w.sample("iec104_negative_confirmations_total", t.labels, "", float64(t.stats.NegativeConfirmations))
+	}
+
+	w.family("iec104_interrogations_total", "counter", "General interrogations completed.")
+	for _, t := range targets {
+		w.sample("iec104_interrogations_total", t.labels, "", float64(t.stats.Interrogations))
+	}
+	w.family("iec104_interrogation_duration_seconds", "gauge", "Duration of the last general interrogation.")
+	for _, t := range targets {
+		if t.stats.Interrogations > 0 {
+			w.sample("iec104_interrogation_duration_seconds", t.labels, "", t.stats.LastInterrogation.Seconds())
+		}
+	}
+
+	w.family("iec104_commands_total", "counter", "Commands sent by type and outcome: confirmed, negative, timeout or failed; select and execute count as one command.")
+	for _, t := range targets {
+		for _, c := range t.stats.Commands {
+			w.sample("iec104_commands_total", t.labels, labels("type", typeName(c.Type.String()), "outcome", string(c.Outcome)), float64(c.Count))
+		}
+	}
+	w.family("iec104_command_round_trip_seconds", "summary", "Time from sending a process command to its confirmation, including the select phase.")
+	for _, t := range targets {
+		rtt := t.stats.CommandRoundTrip
+		w.sample("iec104_command_round_trip_seconds_sum", t.labels, "", rtt.Total.Seconds())
+		w.sample("iec104_command_round_trip_seconds_count", t.labels, "", float64(rtt.Count))
+	}
+
+	w.family("iec104_point_value", "gauge", "Last value of a monitored point in engineering units; 1/0 or the double point state for indications.")
+	for _, t := range targets {
+		for i, u := range t.points {
+			w.sample("iec104_point_value", t.labels, pointLabels(u, t.names[i]), pointValue(u))
+		}
+	}
+	w.family("iec104_point_quality", "gauge", "Quality descriptor bits of a point's last value, 0 is good.")
+	for _, t := range targets {
+		for i, u := range t.points {
+			w.sample("iec104_point_quality", t.labels, pointLabels(u, t.names[i]), float64(u.Quality))
+		}
+	}
+	w.family("iec104_point_age_seconds", "gauge", "Seconds since a point was last updated.")
+	for _, t := range targets {
+		for i, u := range t.points {
+			w.sample("iec104_point_age_seconds", t.labels, pointLabels(u, t.names[i]), now.Sub(u.Received).Seconds())
+		}
+	}
+}
+
+// writer writes metric families and samples
+type writer struct {
+	out io.Writer
+}
+
+func (w *writer) family(name, typ, help string) {
+	fmt.Fprintf(w.out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes a sample with the target's labels followed by extra labels
+func (w *writer) sample(name, target, extra string, value float64) {
+	l := target
+	if extra != "" {
+		l += "," + extra
+	}
+	fmt.Fprintf(w.out, "%s{%s} %s\n", name, l, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// labels formats name and value pairs as Prometheus labels
+func labels(pairs ...string) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func pointLabels(u iec_client.Update, name string) string {
+	return labels("ca", strconv.Itoa(u.CommonAddr), "type", strings.ToLower(u.Type.String()),
+		"ioa", strconv.Itoa(u.Address), "name", name)
+}
+
+func pointValue(u iec_client.Update) float64 {
+	if u.Double {
+		return float64(u.DoubleState)
+	}
+	return u.Value
+}
+
+// typeName strips the TID<> around a type identification
+func typeName(s string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(s, "TID<"), ">")
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func unixSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / 1e9
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"iec104/config"
+	"iec104/iec_client"
+	"iec104/simulator/simtest"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// connectStation starts a simulated station and a client connected to it
+func connectStation(t *testing.T) *iec_client.IEC104Client {
+	t.Helper()
+	sim := simtest.Station(t)
+	profile := simtest.Profile(t, sim.Addr())
+	profile.SetPointName(config.PointTelemetry, config.TelemetryBaseAddress, `Voltage "L1"`)
+	client := iec_client.NewIEC104Client(profile)
+	client.Logger = simtest.NopLogger{}
+	t.Cleanup(client.Close)
+	simtest.Connect(t, client)
+	return client
+}
+
+func TestWrite(t *testing.T) {
+	c := connectStation(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.InterrogateContext(ctx, asdu.QOIStation); err != nil {
+		t.Fatal(err)
+	}
+	// a select before operate, a direct command and a rejected one
+	if err := c.SingleCommandContext(ctx, config.TelecontrolBaseAddress, false, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SingleCommandContext(ctx, config.TelecontrolBaseAddress, true, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SingleCommandContext(ctx, config.TelecontrolBaseAddress+1, true, false); err == nil {
+		t.Fatal("rejected command was confirmed")
+	}
+
+	var b strings.Builder
+	Write(&b, []*iec_client.IEC104Client{c}, time.Now())
+	out := b.String()
+
+	target := `profile="test",server="` + c.Profile().IPAddress + ":" + strconv.Itoa(c.Profile().Port) + `"`
+	for _, want := range []string{
+		"# TYPE iec104_commands_total counter\n",
+		"iec104_up{" + target + "} 1\n",
+		"iec104_connects_total{" + target + "} 1\n",
+		"iec104_interrogations_total{" + target + "} 1\n",
+		"iec104_commands_total{" + target + `,type="C_SC_NA_1",outcome="confirmed"} 2` + "\n",
+		"iec104_commands_total{" + target + `,type="C_SC_NA_1",outcome="negative"} 1` + "\n",
+		"iec104_command_round_trip_seconds_count{" + target + "} 2\n",
+		"iec104_point_value{" + target + `,ca="1",type="telemetry",ioa="16385",name="Voltage \"L1\""} 12.5` + "\n",
+		"iec104_point_value{" + target + `,ca="1",type="teleindication",ioa="1",name=""} 1` + "\n",
+		"iec104_point_quality{" + target + `,ca="1",type="telemetry",ioa="16385",name="Voltage \"L1\""} 0` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics lack %q", want)
+		}
+	}
+	if t.Failed() {
+		t.Log(out)
+	}
+}
+
+func TestExporter(t *testing.T) {
+	c := iec_client.NewIEC104Client(config.NewProfile("idle"))
+	e := NewExporter()
+	e.Add(c)
+	e.Add(c)
+
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path, nil))
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		return w.Body.String()
+	}
+
+	out := scrape()
+	if n := strings.Count(out, "iec104_up{"); n != 1 {
+		t.Errorf("%d iec104_up samples for a client added twice", n)
+	}
+	for _, want := range []string{
+		`iec104_up{profile="idle",server="127.0.0.1:2404"} 0`,
+		`iec104_connected_since_seconds{profile="idle",server="127.0.0.1:2404"} 0`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics of an idle client lack %q", want)
+		}
+	}
+	for _, unwanted := range []string{"iec104_last_receive_age_seconds{", "iec104_interrogation_duration_seconds{", "iec104_point_value{"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("metrics of an idle client have %q", unwanted)
+		}
+	}
+
+	e.Remove(c)
+	if out := scrape(); strings.Contains(out, "iec104_up{") {
+		t.Error("removed client is still exported")
+	}
+}
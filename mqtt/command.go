@@ -0,0 +1,48 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iec104/iec_client"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// handleCommand runs a command request received from the broker and
+// publishes its result. The command waits for its confirmation in its own
+// goroutine so the MQTT client is not blocked. Retained commands are
+// dropped: the broker hands them out again on every subscription, which
+// would repeat the command after each reconnect.
+func (g *Gateway) handleCommand(_ paho.Client, msg paho.Message) {
+	if msg.Retained() {
+		g.Logger.Errorf("MQTT command on %s dropped: retained commands are not executed", msg.Topic())
+		return
+	}
+	var req iec_client.CommandRequest
+	if err := json.Unmarshal(msg.Payload(), &req); err != nil {
+		g.publishResult(iec_client.CommandResult{Outcome: iec_client.CommandFailed, Error: fmt.Sprintf("invalid command: %v", err)})
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), g.opts.CommandTimeout)
+		defer cancel()
+
+		result, err := g.client.Execute(ctx, req)
+		if err != nil {
+			g.Logger.Errorf("MQTT command %s to %d: %v", req.Kind, result.Address, err)
+		} else {
+			g.Logger.Infof("MQTT command %s %s to %d confirmed", req.Kind, result.Value, result.Address)
+		}
+		g.publishResult(result)
+	}()
+}
+
+func (g *Gateway) publishResult(r iec_client.CommandResult) {
+	payload, err := marshal(r)
+	if err != nil {
+		return
+	}
+	g.conn.Publish(g.commandTopic+ResultSuffix, g.opts.QoS, false, payload)
+}
@@ -0,0 +1,247 @@
+// Package mqtt publishes the point updates of an IEC104Client to an MQTT
+// broker and turns messages on a command topic into IEC 104 commands.
+package mqtt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iec104/iec_client"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// DefaultTopic is the topic template used when none is given
+const DefaultTopic = "iec104/{ca}/{type}/{ioa}"
+
+// ResultSuffix is appended to the command topic for the command results
+const ResultSuffix = "/result"
+
+var (
+	ErrorNoBroker     = fmt.Errorf("no MQTT broker given")
+	ErrorInvalidQoS   = fmt.Errorf("QoS must be 0, 1 or 2")
+	ErrorInvalidTopic = fmt.Errorf("topic must not contain wildcards")
+)
+
+// Options configures a gateway
+type Options struct {
+	// Broker is the server URL, e.g. tcp://localhost:1883 or ssl://host:8883
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+
+	// Topic is the template updates are published to. {profile}, {ca},
+	// {type}, {ioa} and {name} are replaced by the values of the point.
+	Topic  string
+	QoS    byte
+	Retain bool
+
+	// CommandTopic, if set, is subscribed to for commands; results are
+	// published to CommandTopic + ResultSuffix. {profile} and {ca} are
+	// replaced.
+	CommandTopic   string
+	CommandTimeout time.Duration
+
+	// StatusTopic, if set, holds a retained "online" while the gateway is
+	// connected and "offline" otherwise
+	StatusTopic string
+}
+
+// Gateway bridges one client to an MQTT broker
+type Gateway struct {
+	Logger iec_client.Logger
+
+	client       *iec_client.IEC104Client
+	opts         Options
+	conn         paho.Client
+	sub          *iec_client.Subscription
+	commandTopic string
+	statusTopic  string
+	wg           sync.WaitGroup
+}
+
+// New creates a gateway for the client. Nothing is sent until Start.
+func New(client *iec_client.IEC104Client, opts Options) (*Gateway, error) {
+	if opts.Broker == "" {
+		return nil, ErrorNoBroker
+	}
+	if opts.QoS > 2 {
+		return nil, ErrorInvalidQoS
+	}
+	if opts.Topic == "" {
+		opts.Topic = DefaultTopic
+	}
+	if opts.ClientID == "" {
+		opts.ClientID = "iec104-" + client.Profile().Name
+	}
+	if opts.CommandTimeout <= 0 {
+		opts.CommandTimeout = iec_client.DefaultCommandTimeout
+	}
+
+	g := &Gateway{
+		Logger: nopLogger{},
+		client: client,
+		opts:   opts,
+	}
+	g.commandTopic = g.expand(opts.CommandTopic, nil)
+	g.statusTopic = g.expand(opts.StatusTopic, nil)
+	for _, topic := range []string{opts.Topic, g.commandTopic, g.statusTopic} {
+		if strings.ContainsAny(topic, "+#") {
+			return nil, fmt.Errorf("%w: %s", ErrorInvalidTopic, topic)
+		}
+	}
+
+	po := paho.NewClientOptions().
+		AddBroker(opts.Broker).
+		SetClientID(opts.ClientID).
+		SetUsername(opts.Username).
+		SetPassword(opts.Password).
+		SetCleanSession(true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOrderMatters(false).
+		SetOnConnectHandler(g.onConnect).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			g.Logger.Errorf("MQTT connection lost: %v", err)
+		})
+	if g.statusTopic != "" {
+		po.SetWill(g.statusTopic, "offline", opts.QoS, true)
+	}
+	g.conn = paho.NewClient(po)
+	return g, nil
+}
+
+// Start connects to the broker and publishes the updates of the client
+// until Close. It waits up to timeout for the broker; when it is not
+// reachable the gateway keeps retrying in the background.
+func (g *Gateway) Start(timeout time.Duration) error {
+	g.sub = g.client.Subscribe(iec_client.SubscribeOptions{
+		Buffer: 4096,
+		Policy: iec_client.DropOldest,
+	})
+	g.wg.Add(1)
+	go g.publishUpdates()
+
+	token := g.conn.Connect()
+	if !token.WaitTimeout(timeout) {
+		g.Logger.Errorf("MQTT broker %s not reachable yet, retrying", g.opts.Broker)
+		return nil
+	}
+	return token.Error()
+}
+
+// Close publishes the offline status and disconnects from the broker
+func (g *Gateway) Close() {
+	if g.sub != nil {
+		g.sub.Close()
+		g.wg.Wait()
+	}
+	if g.statusTopic != "" && g.conn.IsConnectionOpen() {
+		g.conn.Publish(g.statusTopic, g.opts.QoS, true, "offline").WaitTimeout(time.Second)
+	}
+	g.conn.Disconnect(250)
+}
+
+// Dropped returns how many updates were discarded because the gateway fell behind
+func (g *Gateway) Dropped() uint64 {
+	if g.sub == nil {
+		return 0
+	}
+	return g.sub.Dropped()
+}
+
+// onConnect announces the gateway and subscribes to the command topic,
+// again after every reconnect as the session is not kept
+func (g *Gateway) onConnect(conn paho.Client) {
+	g.Logger.Infof("MQTT connected to %s", g.opts.Broker)
+	if g.statusTopic != "" {
+		conn.Publish(g.statusTopic, g.opts.QoS, true, "online")
+	}
+	if g.commandTopic == "" {
+		return
+	}
+	token := conn.Subscribe(g.commandTopic, g.opts.QoS, g.handleCommand)
+	go func() {
+		if token.Wait(); token.Error() != nil {
+			g.Logger.Errorf("MQTT subscribe %s: %v", g.commandTopic, token.Error())
+		}
+	}()
+}
+
+// publishUpdates publishes every update of the subscription
+func (g *Gateway) publishUpdates() {
+	defer g.wg.Done()
+
+	profile := g.client.Profile()
+	for u := range g.sub.C {
+		payload, err := marshal(iec_client.NewRecord(profile, u))
+		if err != nil {
+			continue
+		}
+		token := g.conn.Publish(g.expand(g.opts.Topic, &u), g.opts.QoS, g.opts.Retain, payload)
+		select {
+		case <-token.Done():
+			if err := token.Error(); err != nil {
+				g.Logger.Debugf("MQTT publish: %v", err)
+			}
+		default:
+		}
+	}
+}
+
+// expand replaces the placeholders of a topic template. Without an update
+// only {profile} and {ca} are known.
+func (g *Gateway) expand(template string, u *iec_client.Update) string {
+	if template == "" {
+		return ""
+	}
+	profile := g.client.Profile()
+	ca := profile.CommonAddress
+	if u != nil {
+		ca = u.CommonAddr
+	}
+	pairs := []string{
+		"{profile}", topicLevel(profile.Name),
+		"{ca}", strconv.Itoa(ca),
+	}
+	if u != nil {
+		name := profile.PointName(u.Type.PointType(), u.Address)
+		if name == "" {
+			name = strconv.Itoa(u.Address)
+		}
+		pairs = append(pairs,
+			"{type}", strings.ToLower(u.Type.String()),
+			"{ioa}", strconv.Itoa(u.Address),
+			"{name}", topicLevel(name))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// topicLevel keeps a name from adding wildcards to a topic
+func topicLevel(s string) string {
+	return strings.NewReplacer("+", "_", "#", "_").Replace(s)
+}
+
+// marshal encodes a payload as JSON without escaping <, > and &, which
+// appear in type and cause names
+func marshal(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// nopLogger discards the log until the caller sets one
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
@@ -0,0 +1,303 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"iec104/config"
+	"iec104/iec_client"
+	"iec104/simulator"
+	"iec104/simulator/simtest"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// fakeBroker is a paho.Client that records what the gateway publishes and
+// delivers messages to the gateway's subscriptions
+type fakeBroker struct {
+	gw        *Gateway
+	published chan fakeMessage
+
+	mu        sync.Mutex
+	connected bool
+	handlers  map[string]paho.MessageHandler
+}
+
+// newFakeBroker replaces the broker connection of the gateway
+func newFakeBroker(gw *Gateway) *fakeBroker {
+	b := &fakeBroker{gw: gw, published: make(chan fakeMessage, 100), handlers: make(map[string]paho.MessageHandler)}
+	gw.conn = b
+	return b
+}
+
+func (b *fakeBroker) IsConnected() bool { return b.IsConnectionOpen() }
+func (b *fakeBroker) IsConnectionOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.connected
+}
+
+func (b *fakeBroker) Connect() paho.Token {
+	b.mu.Lock()
+	b.connected = true
+	b.mu.Unlock()
+	b.gw.onConnect(b)
+	return &paho.DummyToken{}
+}
+
+func (b *fakeBroker) Disconnect(uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.connected = false
+}
+
+func (b *fakeBroker) Publish(topic string, qos byte, retained bool, payload interface{}) paho.Token {
+	msg := fakeMessage{topic: topic, qos: qos, retained: retained}
+	switch p := payload.(type) {
+	case string:
+		msg.payload = []byte(p)
+	case []byte:
+		msg.payload = p
+	}
+	b.published <- msg
+	return &paho.DummyToken{}
+}
+
+func (b *fakeBroker) Subscribe(topic string, _ byte, callback paho.MessageHandler) paho.Token {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[topic] = callback
+	return &paho.DummyToken{}
+}
+
+func (b *fakeBroker) SubscribeMultiple(filters map[string]byte, callback paho.MessageHandler) paho.Token {
+	for topic, qos := range filters {
+		b.Subscribe(topic, qos, callback)
+	}
+	return &paho.DummyToken{}
+}
+
+func (b *fakeBroker) Unsubscribe(topics ...string) paho.Token {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, topic := range topics {
+		delete(b.handlers, topic)
+	}
+	return &paho.DummyToken{}
+}
+
+func (b *fakeBroker) AddRoute(topic string, callback paho.MessageHandler) {
+	b.Subscribe(topic, 0, callback)
+}
+func (b *fakeBroker) OptionsReader() paho.ClientOptionsReader { return paho.ClientOptionsReader{} }
+
+// deliver hands a message to the subscription of its topic
+func (b *fakeBroker) deliver(t *testing.T, topic, payload string, retained bool) {
+	t.Helper()
+	b.mu.Lock()
+	handler := b.handlers[topic]
+	b.mu.Unlock()
+	if handler == nil {
+		t.Fatalf("no subscription to %s", topic)
+	}
+	handler(b, fakeMessage{topic: topic, retained: retained, payload: []byte(payload)})
+}
+
+// wait returns the next message published to topic
+func (b *fakeBroker) wait(t *testing.T, topic string) fakeMessage {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg := <-b.published:
+			if msg.topic == topic {
+				return msg
+			}
+		case <-timeout:
+			t.Fatalf("no message on %s", topic)
+		}
+	}
+}
+
+// fakeMessage is a message passed through a fakeBroker
+type fakeMessage struct {
+	topic    string
+	qos      byte
+	retained bool
+	payload  []byte
+}
+
+func (m fakeMessage) Duplicate() bool   { return false }
+func (m fakeMessage) Qos() byte         { return m.qos }
+func (m fakeMessage) Retained() bool    { return m.retained }
+func (m fakeMessage) Topic() string     { return m.topic }
+func (m fakeMessage) MessageID() uint16 { return 0 }
+func (m fakeMessage) Payload() []byte   { return m.payload }
+func (m fakeMessage) Ack()              {}
+
+// connectStation starts a simulated station and a client connected to it
+func connectStation(t *testing.T) (*simulator.Server, *iec_client.IEC104Client) {
+	t.Helper()
+	sim := simtest.Station(t,
+		simulator.Point{Address: config.TeleindBaseAddress, Kind: simulator.KindSingle},
+		simulator.Point{Address: config.TelecontrolBaseAddress, Kind: simulator.KindCommand, Feedback: config.TeleindBaseAddress},
+	)
+	client := iec_client.NewIEC104Client(simtest.Profile(t, sim.Addr()))
+	client.Logger = simtest.NopLogger{}
+	t.Cleanup(client.Close)
+	simtest.Connect(t, client)
+	return sim, client
+}
+
+func TestGateway(t *testing.T) {
+	sim, client := connectStation(t)
+	gw, err := New(client, Options{
+		Broker:       "tcp://broker.invalid:1883",
+		Topic:        "plant/{type}/{ioa}",
+		QoS:          1,
+		CommandTopic: "plant/{ca}/command",
+		StatusTopic:  "plant/{profile}/status",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	broker := newFakeBroker(gw)
+	if err := gw.Start(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	defer gw.Close()
+
+	if msg := broker.wait(t, "plant/test/status"); string(msg.payload) != "online" || !msg.retained {
+		t.Errorf("status = %q, retained %v; want retained online", msg.payload, msg.retained)
+	}
+
+	if err := sim.Set(config.TeleindBaseAddress, 1); err != nil {
+		t.Fatal(err)
+	}
+	var record iec_client.Record
+	if err := json.Unmarshal(broker.wait(t, "plant/teleindication/1").payload, &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Address != config.TeleindBaseAddress || record.Value != 1 {
+		t.Errorf("update = %+v, want ioa 1 value 1", record)
+	}
+
+	tests := []struct {
+		name    string
+		request string
+		outcome iec_client.CommandOutcome
+	}{
+		{"confirmed", `{"id":"1","kind":"sc","ioa":24577,"value":"off"}`, iec_client.CommandConfirmed},
+		{"unknown point", `{"id":"2","kind":"sc","ioa":24999,"value":"on"}`, iec_client.CommandNegative},
+		{"invalid kind", `{"id":"3","kind":"xx","ioa":24577,"value":"on"}`, iec_client.CommandFailed},
+		{"invalid json", `{"id":`, iec_client.CommandFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker.deliver(t, "plant/1/command", tt.request, false)
+			var result iec_client.CommandResult
+			if err := json.Unmarshal(broker.wait(t, "plant/1/command"+ResultSuffix).payload, &result); err != nil {
+				t.Fatal(err)
+			}
+			if result.Outcome != tt.outcome {
+				t.Errorf("outcome = %s (%s), want %s", result.Outcome, result.Error, tt.outcome)
+			}
+		})
+	}
+
+	gw.Close()
+	if msg := broker.wait(t, "plant/test/status"); string(msg.payload) != "offline" {
+		t.Errorf("status after Close = %q, want offline", msg.payload)
+	}
+}
+
+func TestRetainedCommand(t *testing.T) {
+	sim, client := connectStation(t)
+	gw, err := New(client, Options{Broker: "tcp://broker.invalid:1883", CommandTopic: "plant/command"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	broker := newFakeBroker(gw)
+	if err := gw.Start(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	defer gw.Close()
+
+	broker.deliver(t, "plant/command", `{"kind":"sc","ioa":24577,"value":"on","select":false}`, true)
+	timeout := time.After(500 * time.Millisecond)
+wait:
+	for {
+		select {
+		case msg := <-broker.published:
+			if msg.topic == "plant/command"+ResultSuffix {
+				t.Fatalf("retained command was run: %s", msg.payload)
+			}
+		case <-timeout:
+			break wait
+		}
+	}
+	if n := len(client.Stats().Commands); n != 0 {
+		t.Errorf("%d commands sent for a retained message", n)
+	}
+	if v, _, _ := sim.Value(config.TeleindBaseAddress); v != 0 {
+		t.Errorf("breaker = %v after a retained command, want 0", v)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	profile := config.NewProfile("plant+1")
+	profile.CommonAddress = 3
+	profile.SetPointName(config.PointTelemetry, config.TelemetryBaseAddress, "Voltage #1")
+	client := iec_client.NewIEC104Client(profile)
+	defer client.Close()
+	gw, err := New(client, Options{Broker: "tcp://broker.invalid:1883"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	named := iec_client.Update{DataPoint: iec_client.DataPoint{Address: config.TelemetryBaseAddress}, CommonAddr: 7, Type: iec_client.Telemetry}
+	unnamed := iec_client.Update{DataPoint: iec_client.DataPoint{Address: config.TeleindBaseAddress}, CommonAddr: 3, Type: iec_client.Teleindication}
+	tests := []struct {
+		template string
+		update   *iec_client.Update
+		want     string
+	}{
+		{DefaultTopic, &named, "iec104/7/telemetry/16385"},
+		{"{profile}/{name}", &named, "plant_1/Voltage _1"},
+		{"{profile}/{type}/{name}", &unnamed, "plant_1/teleindication/1"},
+		{"{profile}/{ca}/command", nil, "plant_1/3/command"},
+		{"{profile}/{type}/{ioa}", nil, "plant_1/{type}/{ioa}"},
+		{"", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := gw.expand(tt.template, tt.update); got != tt.want {
+			t.Errorf("expand(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	client := iec_client.NewIEC104Client(config.NewProfile("test"))
+	defer client.Close()
+
+	tests := []struct {
+		name string
+		opts Options
+		want error
+	}{
+		{"no broker", Options{}, ErrorNoBroker},
+		{"qos", Options{Broker: "tcp://localhost:1883", QoS: 3}, ErrorInvalidQoS},
+		{"wildcard topic", Options{Broker: "tcp://localhost:1883", Topic: "iec104/#"}, ErrorInvalidTopic},
+		{"wildcard command topic", Options{Broker: "tcp://localhost:1883", CommandTopic: "iec104/+/command"}, ErrorInvalidTopic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(client, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("New() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,534 @@
+// IEC 104 client service for test automation. Generate clients for other
+// languages from this file, e.g. with grpcio-tools or protoc-gen-grpc-java.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: iec104.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	IEC104_Connect_FullMethodName            = "/iec104.v1.IEC104/Connect"
+	IEC104_Disconnect_FullMethodName         = "/iec104.v1.IEC104/Disconnect"
+	IEC104_GetStatus_FullMethodName          = "/iec104.v1.IEC104/GetStatus"
+	IEC104_Interrogate_FullMethodName        = "/iec104.v1.IEC104/Interrogate"
+	IEC104_Read_FullMethodName               = "/iec104.v1.IEC104/Read"
+	IEC104_SingleCommand_FullMethodName      = "/iec104.v1.IEC104/SingleCommand"
+	IEC104_DoubleCommand_FullMethodName      = "/iec104.v1.IEC104/DoubleCommand"
+	IEC104_SetpointFloat_FullMethodName      = "/iec104.v1.IEC104/SetpointFloat"
+	IEC104_SetpointScaled_FullMethodName     = "/iec104.v1.IEC104/SetpointScaled"
+	IEC104_SetpointNormalized_FullMethodName = "/iec104.v1.IEC104/SetpointNormalized"
+	IEC104_Subscribe_FullMethodName          = "/iec104.v1.IEC104/Subscribe"
+)
+
+// IEC104Client is the client API for IEC104 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type IEC104Client interface {
+	// Connect starts the connection and waits for the link to come up
+	Connect(ctx context.Context, in *ConnectRequest, opts ...grpc.CallOption) (*ConnectionStatus, error)
+	// Disconnect closes the connection
+	Disconnect(ctx context.Context, in *DisconnectRequest, opts ...grpc.CallOption) (*ConnectionStatus, error)
+	// GetStatus returns the link state and counters
+	GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*ConnectionStatus, error)
+	// Interrogate runs a general or counter interrogation
+	Interrogate(ctx context.Context, in *InterrogateRequest, opts ...grpc.CallOption) (*InterrogateResponse, error)
+	// Read returns the last received values of the matching points
+	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
+	// SingleCommand sends C_SC_NA_1
+	SingleCommand(ctx context.Context, in *SingleCommandRequest, opts ...grpc.CallOption) (*SingleCommandResponse, error)
+	// DoubleCommand sends C_DC_NA_1
+	DoubleCommand(ctx context.Context, in *DoubleCommandRequest, opts ...grpc.CallOption) (*DoubleCommandResponse, error)
+	// SetpointFloat sends C_SE_NC_1
+	SetpointFloat(ctx context.Context, in *SetpointFloatRequest, opts ...grpc.CallOption) (*SetpointFloatResponse, error)
+	// SetpointScaled sends C_SE_NB_1
+	SetpointScaled(ctx context.Context, in *SetpointScaledRequest, opts ...grpc.CallOption) (*SetpointScaledResponse, error)
+	// SetpointNormalized sends C_SE_NA_1
+	SetpointNormalized(ctx context.Context, in *SetpointNormalizedRequest, opts ...grpc.CallOption) (*SetpointNormalizedResponse, error)
+	// Subscribe streams the point updates passing the filter until the call
+	// is cancelled
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (IEC104_SubscribeClient, error)
+}
+
+type iEC104Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIEC104Client(cc grpc.ClientConnInterface) IEC104Client {
+	return &iEC104Client{cc}
+}
+
+func (c *iEC104Client) Connect(ctx context.Context, in *ConnectRequest, opts ...grpc.CallOption) (*ConnectionStatus, error) {
+	out := new(ConnectionStatus)
+	err := c.cc.Invoke(ctx, IEC104_Connect_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iEC104Client) Disconnect(ctx context.Context, in *DisconnectRequest, opts ...grpc.CallOption) (*ConnectionStatus, error) {
+	out := new(ConnectionStatus)
+	err := c.cc.Invoke(ctx, IEC104_Disconnect_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iEC104Client) GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*ConnectionStatus, error) {
+	out := new(ConnectionStatus)
+	err := c.cc.Invoke(ctx, IEC104_GetStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iEC104Client) Interrogate(ctx context.Context, in *InterrogateRequest, opts ...grpc.CallOption) (*InterrogateResponse, error) {
+	out := new(InterrogateResponse)
+	err := c.cc.Invoke(ctx, IEC104_Interrogate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iEC104Client) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error) {
+	out := new(ReadResponse)
+	err := c.cc.Invoke(ctx, IEC104_Read_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iEC104Client) SingleCommand(ctx context.Context, in *SingleCommandRequest, opts ...grpc.CallOption) (*SingleCommandResponse, error) {
+	out := new(SingleCommandResponse)
+	err := c.cc.Invoke(ctx, IEC104_SingleCommand_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iEC104Client) DoubleCommand(ctx context.Context, in *DoubleCommandRequest, opts ...grpc.CallOption) (*DoubleCommandResponse, error) {
+	out := new(DoubleCommandResponse)
+	err := c.cc.Invoke(ctx, IEC104_DoubleCommand_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iEC104Client) SetpointFloat(ctx context.Context, in *SetpointFloatRequest, opts ...grpc.CallOption) (*SetpointFloatResponse, error) {
+	out := new(SetpointFloatResponse)
+	err := c.cc.Invoke(ctx, IEC104_SetpointFloat_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iEC104Client) SetpointScaled(ctx context.Context, in *SetpointScaledRequest, opts ...grpc.CallOption) (*SetpointScaledResponse, error) {
+	out := new(SetpointScaledResponse)
+	err := c.cc.Invoke(ctx, IEC104_SetpointScaled_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iEC104Client) SetpointNormalized(ctx context.Context, in *SetpointNormalizedRequest, opts ...grpc.CallOption) (*SetpointNormalizedResponse, error) {
+	out := new(SetpointNormalizedResponse)
+	err := c.cc.Invoke(ctx, IEC104_SetpointNormalized_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iEC104Client) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (IEC104_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &IEC104_ServiceDesc.Streams[0], IEC104_Subscribe_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &iEC104SubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type IEC104_SubscribeClient interface {
+	Recv() (*Point, error)
+	grpc.ClientStream
+}
+
+type iEC104SubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *iEC104SubscribeClient) Recv() (*Point, error) {
+	m := new(Point)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// IEC104Server is the server API for IEC104 service.
+// All implementations must embed UnimplementedIEC104Server
+// for forward compatibility
+type IEC104Server interface {
+	// Connect starts the connection and waits for the link to come up
+	Connect(context.Context, *ConnectRequest) (*ConnectionStatus, error)
+	// Disconnect closes the connection
+	Disconnect(context.Context, *DisconnectRequest) (*ConnectionStatus, error)
+	// GetStatus returns the link state and counters
+	GetStatus(context.Context, *StatusRequest) (*ConnectionStatus, error)
+	// Interrogate runs a general or counter interrogation
+	Interrogate(context.Context, *InterrogateRequest) (*InterrogateResponse, error)
+	// Read returns the last received values of the matching points
+	Read(context.Context, *ReadRequest) (*ReadResponse, error)
+	// SingleCommand sends C_SC_NA_1
+	SingleCommand(context.Context, *SingleCommandRequest) (*SingleCommandResponse, error)
+	// DoubleCommand sends C_DC_NA_1
+	DoubleCommand(context.Context, *DoubleCommandRequest) (*DoubleCommandResponse, error)
+	// SetpointFloat sends C_SE_NC_1
+	SetpointFloat(context.Context, *SetpointFloatRequest) (*SetpointFloatResponse, error)
+	// SetpointScaled sends C_SE_NB_1
+	SetpointScaled(context.Context, *SetpointScaledRequest) (*SetpointScaledResponse, error)
+	// SetpointNormalized sends C_SE_NA_1
+	SetpointNormalized(context.Context, *SetpointNormalizedRequest) (*SetpointNormalizedResponse, error)
+	// Subscribe streams the point updates passing the filter until the call
+	// is cancelled
+	Subscribe(*SubscribeRequest, IEC104_SubscribeServer) error
+	mustEmbedUnimplementedIEC104Server()
+}
+
+// UnimplementedIEC104Server must be embedded to have forward compatible implementations.
+type UnimplementedIEC104Server struct {
+}
+
+func (UnimplementedIEC104Server) Connect(context.Context, *ConnectRequest) (*ConnectionStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+func (UnimplementedIEC104Server) Disconnect(context.Context, *DisconnectRequest) (*ConnectionStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Disconnect not implemented")
+}
+func (UnimplementedIEC104Server) GetStatus(context.Context, *StatusRequest) (*ConnectionStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedIEC104Server) Interrogate(context.Context, *InterrogateRequest) (*InterrogateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Interrogate not implemented")
+}
+func (UnimplementedIEC104Server) Read(context.Context, *ReadRequest) (*ReadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Read not implemented")
+}
+func (UnimplementedIEC104Server) SingleCommand(context.Context, *SingleCommandRequest) (*SingleCommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SingleCommand not implemented")
+}
+func (UnimplementedIEC104Server) DoubleCommand(context.Context, *DoubleCommandRequest) (*DoubleCommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DoubleCommand not implemented")
+}
+func (UnimplementedIEC104Server) SetpointFloat(context.Context, *SetpointFloatRequest) (*SetpointFloatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetpointFloat not implemented")
+}
+func (UnimplementedIEC104Server) SetpointScaled(context.Context, *SetpointScaledRequest) (*SetpointScaledResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetpointScaled not implemented")
+}
+func (UnimplementedIEC104Server) SetpointNormalized(context.Context, *SetpointNormalizedRequest) (*SetpointNormalizedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetpointNormalized not implemented")
+}
+func (UnimplementedIEC104Server) Subscribe(*SubscribeRequest, IEC104_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedIEC104Server) mustEmbedUnimplementedIEC104Server() {}
+
+// UnsafeIEC104Server may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IEC104Server will
+// result in compilation errors.
+type UnsafeIEC104Server interface {
+	mustEmbedUnimplementedIEC104Server()
+}
+
+func RegisterIEC104Server(s grpc.ServiceRegistrar, srv IEC104Server) {
+	s.RegisterService(&IEC104_ServiceDesc, srv)
+}
+
+func _IEC104_Connect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IEC104Server).Connect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IEC104_Connect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IEC104Server).Connect(ctx, req.(*ConnectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IEC104_Disconnect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IEC104Server).Disconnect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IEC104_Disconnect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IEC104Server).Disconnect(ctx, req.(*DisconnectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IEC104_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IEC104Server).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IEC104_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IEC104Server).GetStatus(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IEC104_Interrogate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InterrogateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IEC104Server).Interrogate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IEC104_Interrogate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IEC104Server).Interrogate(ctx, req.(*InterrogateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IEC104_Read_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IEC104Server).Read(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IEC104_Read_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IEC104Server).Read(ctx, req.(*ReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IEC104_SingleCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SingleCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IEC104Server).SingleCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IEC104_SingleCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IEC104Server).SingleCommand(ctx, req.(*SingleCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IEC104_DoubleCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DoubleCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IEC104Server).DoubleCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IEC104_DoubleCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IEC104Server).DoubleCommand(ctx, req.(*DoubleCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IEC104_SetpointFloat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetpointFloatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IEC104Server).SetpointFloat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IEC104_SetpointFloat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IEC104Server).SetpointFloat(ctx, req.(*SetpointFloatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IEC104_SetpointScaled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetpointScaledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IEC104Server).SetpointScaled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IEC104_SetpointScaled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IEC104Server).SetpointScaled(ctx, req.(*SetpointScaledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IEC104_SetpointNormalized_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetpointNormalizedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IEC104Server).SetpointNormalized(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IEC104_SetpointNormalized_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IEC104Server).SetpointNormalized(ctx, req.(*SetpointNormalizedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IEC104_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IEC104Server).Subscribe(m, &iEC104SubscribeServer{stream})
+}
+
+type IEC104_SubscribeServer interface {
+	Send(*Point) error
+	grpc.ServerStream
+}
+
+type iEC104SubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *iEC104SubscribeServer) Send(m *Point) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// IEC104_ServiceDesc is the grpc.ServiceDesc for IEC104 service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IEC104_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "iec104.v1.IEC104",
+	HandlerType: (*IEC104Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Connect",
+			Handler:    _IEC104_Connect_Handler,
+		},
+		{
+			MethodName: "Disconnect",
+			Handler:    _IEC104_Disconnect_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _IEC104_GetStatus_Handler,
+		},
+		{
+			MethodName: "Interrogate",
+			Handler:    _IEC104_Interrogate_Handler,
+		},
+		{
+			MethodName: "Read",
+			Handler:    _IEC104_Read_Handler,
+		},
+		{
+			MethodName: "SingleCommand",
+			Handler:    _IEC104_SingleCommand_Handler,
+		},
+		{
+			MethodName: "DoubleCommand",
+			Handler:    _IEC104_DoubleCommand_Handler,
+		},
+		{
+			MethodName: "SetpointFloat",
+			Handler:    _IEC104_SetpointFloat_Handler,
+		},
+		{
+			MethodName: "SetpointScaled",
+			Handler:    _IEC104_SetpointScaled_Handler,
+		},
+		{
+			MethodName: "SetpointNormalized",
+			Handler:    _IEC104_SetpointNormalized_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _IEC104_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "iec104.proto",
+}
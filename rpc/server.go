@@ -0,0 +1,402 @@
+// Package rpc serves the IEC104 gRPC service defined in pb/iec104.proto
+// for IEC104Client instances.
+package rpc
+
+//go:generate protoc -I pb --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative iec104.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iec104/iec_client"
+	"iec104/rpc/pb"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	ErrorNoClients      = fmt.Errorf("no connection is served")
+	ErrorUnknownProfile = fmt.Errorf("unknown profile")
+)
+
+// Server implements the IEC104 service for a changing set of clients
+type Server struct {
+	pb.UnimplementedIEC104Server
+
+	// CommandTimeout bounds the wait for confirmations of calls without a deadline
+	CommandTimeout time.Duration
+	// Reflection registers server reflection for tools like grpcurl
+	Reflection bool
+
+	mu      sync.Mutex
+	clients []*iec_client.IEC104Client
+}
+
+// NewServer creates a server without clients
+func NewServer() *Server {
+	return &Server{CommandTimeout: iec_client.DefaultCommandTimeout}
+}
+
+// Add starts serving a client
+func (s *Server) Add(c *iec_client.IEC104Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.clients {
+		if existing == c {
+			return
+		}
+	}
+	s.clients = append(s.clients, c)
+}
+
+// Remove stops serving a client
+func (s *Server) Remove(c *iec_client.IEC104Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.clients {
+		if existing == c {
+			s.clients = append(s.clients[:i], s.clients[i+1:]...)
+			return
+		}
+	}
+}
+
+// Serve listens on addr and serves the service in the background until the
+// returned server is stopped
+func (s *Server) Serve(addr string) (*grpc.Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	srv := grpc.NewServer()
+	pb.RegisterIEC104Server(srv, s)
+	if s.Reflection {
+		reflection.Register(srv)
+	}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	return srv, nil
+}
+
+// client returns the client of the named profile, or the first one
+func (s *Server) client(profile string) (*iec_client.IEC104Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.clients) == 0 {
+		return nil, status.Error(codes.Unavailable, ErrorNoClients.Error())
+	}
+	if profile == "" {
+		return s.clients[0], nil
+	}
+	for _, c := range s.clients {
+		if c.Profile().Name == profile {
+			return c, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "%v %q", ErrorUnknownProfile, profile)
+}
+
+// commandContext applies the command timeout unless the call has a deadline
+func (s *Server) commandContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.CommandTimeout)
+}
+
+func (s *Server) Connect(ctx context.Context, req *pb.ConnectRequest) (*pb.ConnectionStatus, error) {
+	c, err := s.client(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := s.commandContext(ctx)
+	defer cancel()
+	if err := c.ConnectContext(ctx); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return connectionStatus(c), nil
+}
+
+func (s *Server) Disconnect(_ context.Context, req *pb.DisconnectRequest) (*pb.ConnectionStatus, error) {
+	c, err := s.client(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Disconnect(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return connectionStatus(c), nil
+}
+
+func (s *Server) GetStatus(_ context.Context, req *pb.StatusRequest) (*pb.ConnectionStatus, error) {
+	c, err := s.client(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+	return connectionStatus(c), nil
+}
+
+func (s *Server) Interrogate(ctx context.Context, req *pb.InterrogateRequest) (*pb.InterrogateResponse, error) {
+	c, err := s.client(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := s.commandContext(ctx)
+	defer cancel()
+	res, err := c.Interrogate(ctx, iec_client.InterrogationRequest{Group: int(req.Group), Counter: req.Counter})
+	if errors.Is(err, iec_client.ErrorInvalidGroup) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	r := result(err, 0)
+	r.ElapsedMs = res.Elapsed
+	return &pb.InterrogateResponse{Result: r}, nil
+}
+
+func (s *Server) Read(_ context.Context, req *pb.ReadRequest) (*pb.ReadResponse, error) {
+	c, err := s.client(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := newFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ReadResponse{}
+	for _, u := range c.Snapshot() {
+		if filter.Match(u) {
+			resp.Points = append(resp.Points, newPoint(c, u))
+		}
+	}
+	return resp, nil
+}
+
+func (s *Server) SingleCommand(ctx context.Context, req *pb.SingleCommandRequest) (*pb.SingleCommandResponse, error) {
+	r, err := s.execute(ctx, req.Profile, req.Ioa, func(ctx context.Context, c *iec_client.IEC104Client) error {
+		return c.SingleCommandContext(ctx, int(req.Ioa), req.On, req.Select == nil || *req.Select)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SingleCommandResponse{Ioa: req.Ioa, On: req.On, Result: r}, nil
+}
+
+func (s *Server) DoubleCommand(ctx context.Context, req *pb.DoubleCommandRequest) (*pb.DoubleCommandResponse, error) {
+	dco := asdu.DCOOff
+	if req.On {
+		dco = asdu.DCOOn
+	}
+	r, err := s.execute(ctx, req.Profile, req.Ioa, func(ctx context.Context, c *iec_client.IEC104Client) error {
+		return c.DoubleCommandContext(ctx, int(req.Ioa), dco, req.Select == nil || *req.Select)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DoubleCommandResponse{Ioa: req.Ioa, On: req.On, Result: r}, nil
+}
+
+func (s *Server) SetpointFloat(ctx context.Context, req *pb.SetpointFloatRequest) (*pb.SetpointFloatResponse, error) {
+	r, err := s.execute(ctx, req.Profile, req.Ioa, func(ctx context.Context, c *iec_client.IEC104Client) error {
+		return c.SetpointFloatContext(ctx, int(req.Ioa), req.Value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetpointFloatResponse{Ioa: req.Ioa, Value: req.Value, Result: r}, nil
+}
+
+func (s *Server) SetpointScaled(ctx context.Context, req *pb.SetpointScaledRequest) (*pb.SetpointScaledResponse, error) {
+	if req.Value < math.MinInt16 || req.Value > math.MaxInt16 {
+		return nil, status.Errorf(codes.InvalidArgument, "scaled value %d out of range", req.Value)
+	}
+	r, err := s.execute(ctx, req.Profile, req.Ioa, func(ctx context.Context, c *iec_client.IEC104Client) error {
+		return c.SetpointScaledContext(ctx, int(req.Ioa), int16(req.Value))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetpointScaledResponse{Ioa: req.Ioa, Value: req.Value, Result: r}, nil
+}
+
+func (s *Server) SetpointNormalized(ctx context.Context, req *pb.SetpointNormalizedRequest) (*pb.SetpointNormalizedResponse, error) {
+	if req.Value < -1 || req.Value > 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "normalized value %v out of range [-1, 1]", req.Value)
+	}
+	r, err := s.execute(ctx, req.Profile, req.Ioa, func(ctx context.Context, c *iec_client.IEC104Client) error {
+		return c.SetpointNormalContext(ctx, int(req.Ioa), req.Value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetpointNormalizedResponse{Ioa: req.Ioa, Value: req.Value, Result: r}, nil
+}
+
+// execute sends a command and waits for its outcome. Only invalid requests
+// fail the call; rejected or timed out commands are reported in the result.
+func (s *Server) execute(ctx context.Context, profile string, ioa int32, send iec_client.CommandFunc) (*pb.Result, error) {
+	if ioa <= 0 {
+		return nil, status.Error(codes.InvalidArgument, iec_client.ErrorNoAddress.Error())
+	}
+	c, err := s.client(profile)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := s.commandContext(ctx)
+	defer cancel()
+	start := time.Now()
+	err = send(ctx, c)
+	return result(err, time.Since(start)), nil
+}
+
+func (s *Server) Subscribe(req *pb.SubscribeRequest, stream pb.IEC104_SubscribeServer) error {
+	c, err := s.client(req.Profile)
+	if err != nil {
+		return err
+	}
+	filter, err := newFilter(req.Filter)
+	if err != nil {
+		return err
+	}
+	sub := c.Subscribe(iec_client.SubscribeOptions{
+		Filter: filter,
+		Buffer: 1024,
+		Policy: iec_client.DropOldest,
+	})
+	defer sub.Close()
+
+	if req.Snapshot {
+		for _, u := range c.Snapshot() {
+			if !filter.Match(u) {
+				continue
+			}
+			if err := stream.Send(newPoint(c, u)); err != nil {
+				return err
+			}
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case u, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(newPoint(c, u)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func connectionStatus(c *iec_client.IEC104Client) *pb.ConnectionStatus {
+	profile := c.Profile()
+	stats := c.Stats()
+	return &pb.ConnectionStatus{
+		Profile:               profile.Name,
+		Server:                net.JoinHostPort(profile.IPAddress, strconv.Itoa(profile.Port)),
+		CommonAddress:         int32(profile.CommonAddress),
+		Connected:             c.Connected.Load(),
+		ConnectedSince:        timestamp(stats.ConnectedSince),
+		LastReceived:          timestamp(stats.LastReceived),
+		Connects:              stats.Connects,
+		Reconnects:            stats.Reconnects,
+		Disconnects:           stats.Disconnects,
+		FramesReceived:        stats.Received.Total(),
+		FramesSent:            stats.Sent.Total(),
+		Interrogations:        stats.Interrogations,
+		NegativeConfirmations: stats.NegativeConfirmations,
+	}
+}
+
+func result(err error, elapsed time.Duration) *pb.Result {
+	r := &pb.Result{ElapsedMs: iec_client.Milliseconds(elapsed)}
+	switch iec_client.Outcome(err) {
+	case iec_client.CommandConfirmed:
+		r.Outcome = pb.CommandOutcome_COMMAND_OUTCOME_CONFIRMED
+	case iec_client.CommandNegative:
+		r.Outcome = pb.CommandOutcome_COMMAND_OUTCOME_NEGATIVE
+	case iec_client.CommandTimeout:
+		r.Outcome = pb.CommandOutcome_COMMAND_OUTCOME_TIMEOUT
+	default:
+		r.Outcome = pb.CommandOutcome_COMMAND_OUTCOME_FAILED
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// newFilter converts a filter of a request
+func newFilter(f *pb.Filter) (iec_client.Filter, error) {
+	var filter iec_client.Filter
+	if f == nil {
+		return filter, nil
+	}
+	filter.MinAddress = int(f.MinIoa)
+	filter.MaxAddress = int(f.MaxIoa)
+	for _, ca := range f.CommonAddresses {
+		filter.CommonAddrs = append(filter.CommonAddrs, int(ca))
+	}
+	for _, t := range f.Types {
+		typ, ok := dataTypes[t]
+		if !ok {
+			return filter, status.Errorf(codes.InvalidArgument, "invalid point type %s", t)
+		}
+		filter.Types = append(filter.Types, typ)
+	}
+	return filter, nil
+}
+
+var dataTypes = map[pb.PointType]iec_client.DataType{
+	pb.PointType_POINT_TYPE_TELEMETRY:      iec_client.Telemetry,
+	pb.PointType_POINT_TYPE_TELEINDICATION: iec_client.Teleindication,
+	pb.PointType_POINT_TYPE_TELECONTROL:    iec_client.Telecontrol,
+	pb.PointType_POINT_TYPE_TELEREGULATION: iec_client.Teleregulation,
+}
+
+func newPoint(c *iec_client.IEC104Client, u iec_client.Update) *pb.Point {
+	r := iec_client.NewRecord(c.Profile(), u)
+	p := &pb.Point{
+		CommonAddress: int32(u.CommonAddr),
+		Ioa:           int32(u.Address),
+		Name:          r.Name,
+		Value:         u.Value,
+		Raw:           u.Raw,
+		Unit:          r.Unit,
+		State:         r.State,
+		DoublePoint:   u.Double,
+		DoubleState:   int32(u.DoubleState),
+		Quality:       uint32(u.Quality),
+		QualityText:   r.Quality,
+		Cause:         r.Cause,
+		Timestamp:     timestamp(u.Timestamp),
+		Received:      timestamp(u.Received),
+	}
+	for pt, dt := range dataTypes {
+		if dt == u.Type {
+			p.Type = pt
+		}
+	}
+	return p
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
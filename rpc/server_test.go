@@ -0,0 +1,224 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"iec104/config"
+	"iec104/iec_client"
+	"iec104/rpc/pb"
+	"iec104/simulator"
+	"iec104/simulator/simtest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startStation starts a simulated station and a client for it that is not
+// connected yet
+func startStation(t *testing.T) (*simulator.Server, *iec_client.IEC104Client) {
+	t.Helper()
+	points := []simulator.Point{
+		{Address: config.TeleindBaseAddress, Kind: simulator.KindSingle, Value: 1},
+		{Address: config.TelemetryBaseAddress, Kind: simulator.KindFloat, Value: 12.5},
+		{Address: config.TelecontrolBaseAddress, Kind: simulator.KindCommand, Feedback: config.TeleindBaseAddress},
+		{Address: config.TeleregulationBaseAddress, Kind: simulator.KindSetpoint, Feedback: config.TelemetryBaseAddress},
+	}
+	sim := simtest.Station(t, points...)
+	client := iec_client.NewIEC104Client(simtest.Profile(t, sim.Addr()))
+	client.Logger = simtest.NopLogger{}
+	t.Cleanup(client.Close)
+	return sim, client
+}
+
+// dial serves s over an in-memory connection and returns a client for it
+func dial(t *testing.T, s *Server) pb.IEC104Client {
+	t.Helper()
+	l := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterIEC104Server(srv, s)
+	go srv.Serve(l)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return l.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewIEC104Client(conn)
+}
+
+func TestService(t *testing.T) {
+	sim, client := startStation(t)
+	s := NewServer()
+	s.Add(client)
+	rpc := dial(t, s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	st, err := rpc.GetStatus(ctx, &pb.StatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Connected {
+		t.Fatal("connected before Connect")
+	}
+	// a Connect while the link is still coming up keeps the pending
+	// attempt instead of starting a second link
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rpc.Connect(ctx, &pb.ConnectRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if st, err = rpc.GetStatus(ctx, &pb.StatusRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if !st.Connected || st.Profile != "test" || st.Connects != 1 {
+		t.Errorf("status after Connect = %+v, want connected once", st)
+	}
+
+	ir, err := rpc.Interrogate(ctx, &pb.InterrogateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ir.Result.Outcome != pb.CommandOutcome_COMMAND_OUTCOME_CONFIRMED {
+		t.Fatalf("interrogation = %+v", ir.Result)
+	}
+
+	read, err := rpc.Read(ctx, &pb.ReadRequest{Filter: &pb.Filter{Types: []pb.PointType{pb.PointType_POINT_TYPE_TELEMETRY}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Points) != 1 || read.Points[0].Ioa != config.TelemetryBaseAddress || read.Points[0].Value != 12.5 {
+		t.Errorf("read telemetry = %+v", read.Points)
+	}
+	read, err = rpc.Read(ctx, &pb.ReadRequest{Filter: &pb.Filter{CommonAddresses: []int32{2}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Points) != 0 {
+		t.Errorf("read of another common address = %+v", read.Points)
+	}
+
+	stream, err := rpc.Subscribe(ctx, &pb.SubscribeRequest{Filter: &pb.Filter{Types: []pb.PointType{pb.PointType_POINT_TYPE_TELEINDICATION}}, Snapshot: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := stream.Recv(); err != nil || p.Ioa != config.TeleindBaseAddress || p.Value != 1 {
+		t.Fatalf("snapshot = %+v, %v", p, err)
+	}
+
+	sc, err := rpc.SingleCommand(ctx, &pb.SingleCommandRequest{Ioa: config.TelecontrolBaseAddress, On: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Result.Outcome != pb.CommandOutcome_COMMAND_OUTCOME_CONFIRMED {
+		t.Errorf("single command = %+v", sc.Result)
+	}
+	// the simulator reflects the command into the indication
+	if p, err := stream.Recv(); err != nil || p.Ioa != config.TeleindBaseAddress || p.Value != 0 {
+		t.Errorf("update = %+v, %v; want the indication off", p, err)
+	}
+
+	sp, err := rpc.SetpointFloat(ctx, &pb.SetpointFloatRequest{Ioa: config.TeleregulationBaseAddress, Value: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sp.Result.Outcome != pb.CommandOutcome_COMMAND_OUTCOME_CONFIRMED {
+		t.Errorf("setpoint = %+v", sp.Result)
+	}
+
+	sc, err = rpc.SingleCommand(ctx, &pb.SingleCommandRequest{Ioa: 24999, On: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Result.Outcome != pb.CommandOutcome_COMMAND_OUTCOME_NEGATIVE {
+		t.Errorf("command to an unknown point = %+v", sc.Result)
+	}
+
+	if _, err := sim.RandomChange(); err != nil {
+		t.Fatal(err)
+	}
+	if st, err = rpc.Disconnect(ctx, &pb.DisconnectRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if st.Connected {
+		t.Error("connected after Disconnect")
+	}
+}
+
+func TestInvalidRequests(t *testing.T) {
+	_, client := startStation(t)
+	s := NewServer()
+	s.Add(client)
+	rpc := dial(t, s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"unknown profile", func() error {
+			_, err := rpc.GetStatus(ctx, &pb.StatusRequest{Profile: "other"})
+			return err
+		}, codes.NotFound},
+		{"no address", func() error {
+			_, err := rpc.SingleCommand(ctx, &pb.SingleCommandRequest{On: true})
+			return err
+		}, codes.InvalidArgument},
+		{"scaled out of range", func() error {
+			_, err := rpc.SetpointScaled(ctx, &pb.SetpointScaledRequest{Ioa: 25089, Value: 40000})
+			return err
+		}, codes.InvalidArgument},
+		{"normalized out of range", func() error {
+			_, err := rpc.SetpointNormalized(ctx, &pb.SetpointNormalizedRequest{Ioa: 25089, Value: 2})
+			return err
+		}, codes.InvalidArgument},
+		{"interrogation group", func() error {
+			_, err := rpc.Interrogate(ctx, &pb.InterrogateRequest{Group: 17})
+			return err
+		}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != tt.code {
+				t.Errorf("code = %s, want %s", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestReflection(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		s := NewServer()
+		s.Reflection = enabled
+		srv, err := s.Serve("127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		registered := false
+		for name := range srv.GetServiceInfo() {
+			if strings.HasPrefix(name, "grpc.reflection.") {
+				registered = true
+			}
+		}
+		srv.Stop()
+		if registered != enabled {
+			t.Errorf("Reflection %v: reflection registered = %v", enabled, registered)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"iec104/config"
+	"os"
+	"strings"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// PointKind is the information object type of a simulated point
+type PointKind string
+
+const (
+	// KindSingle is a single point indication, M_SP_NA_1 or M_SP_TB_1
+	KindSingle PointKind = "single"
+	// KindDouble is a double point indication, M_DP_NA_1 or M_DP_TB_1
+	KindDouble PointKind = "double"
+	// KindNormalized is a normalized measurement, M_ME_NA_1 or M_ME_TD_1
+	KindNormalized PointKind = "normalized"
+	// KindScaled is a scaled measurement, M_ME_NB_1 or M_ME_TE_1
+	KindScaled PointKind = "scaled"
+	// KindFloat is a short floating point measurement, M_ME_NC_1 or M_ME_TF_1
+	KindFloat PointKind = "float"
+	// KindCounter is an integrated total, M_IT_NA_1 or M_IT_TB_1
+	KindCounter PointKind = "counter"
+	// KindCommand accepts single and double commands
+	KindCommand PointKind = "command"
+	// KindSetpoint accepts normalized, scaled and floating point setpoints
+	KindSetpoint PointKind = "setpoint"
+)
+
+// PointKinds lists all point kinds
+var PointKinds = []PointKind{KindSingle, KindDouble, KindNormalized, KindScaled, KindFloat, KindCounter, KindCommand, KindSetpoint}
+
+// monitored reports whether the kind is reported by the station
+func (k PointKind) monitored() bool {
+	return k != KindCommand && k != KindSetpoint
+}
+
+// Point is a simulated information object
+type Point struct {
+	Address int       `json:"ioa"`
+	Kind    PointKind `json:"type"`
+	Name    string    `json:"name,omitempty"`
+	// Value is the initial raw value: 0 or 1 for single points, 0-3 for
+	// double points and -1..1 for normalized measurements
+	Value float64 `json:"value,omitempty"`
+	// Quality lists the initial quality flags, e.g. "iv,nt"
+	Quality string `json:"quality,omitempty"`
+	// Group is the interrogation group 1-16, or the counter group 1-4 for
+	// counters; all points answer the station interrogation
+	Group int `json:"group,omitempty"`
+	// TimeTag sends spontaneous changes with a CP56Time2a time tag
+	TimeTag bool `json:"time_tag,omitempty"`
+	// Feedback is the monitored point a command or setpoint is reflected into
+	Feedback int `json:"feedback,omitempty"`
+	// Reject answers commands with a negative confirmation
+	Reject bool `json:"reject,omitempty"`
+}
+
+// Database is the point database of a simulated station
+type Database struct {
+	CommonAddress int     `json:"common_address"`
+	Points        []Point `json:"points"`
+}
+
+// LoadDatabase reads and validates a point database from a JSON file
+func LoadDatabase(path string) (*Database, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	db := &Database{}
+	if err := json.Unmarshal(data, db); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := db.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+// DatabaseFromProfile creates a database matching a client profile: float
+// measurements and single points for the configured counts and points, and
+// 100 commands and setpoints reflected into the indication and measurement
+// with the same offset
+func DatabaseFromProfile(p *config.Profile) *Database {
+	db := &Database{CommonAddress: p.CommonAddress}
+	seen := make(map[int]bool)
+	add := func(point Point) {
+		if !seen[point.Address] {
+			seen[point.Address] = true
+			db.Points = append(db.Points, point)
+		}
+	}
+
+	for i := 0; i < p.TelemetryCount; i++ {
+		add(Point{Address: config.TelemetryBaseAddress + i, Kind: KindFloat})
+	}
+	for i := 0; i < p.TeleindCount; i++ {
+		add(Point{Address: config.TeleindBaseAddress + i, Kind: KindSingle})
+	}
+	for _, point := range p.Points {
+		switch point.Type {
+		case config.PointTelemetry:
+			add(Point{Address: point.Address, Kind: KindFloat, Name: point.Name})
+		case config.PointTeleindication:
+			add(Point{Address: point.Address, Kind: KindSingle, Name: point.Name})
+		}
+	}
+	for i := 0; i < 100; i++ {
+		command := Point{Address: config.TelecontrolBaseAddress + i, Kind: KindCommand}
+		if i < p.TeleindCount {
+			command.Feedback = config.TeleindBaseAddress + i
+		}
+		add(command)
+
+		setpoint := Point{Address: config.TeleregulationBaseAddress + i, Kind: KindSetpoint}
+		if i < p.TelemetryCount {
+			setpoint.Feedback = config.TelemetryBaseAddress + i
+		}
+		add(setpoint)
+	}
+	return db
+}
+
+// Validate checks addresses, kinds, groups and feedback targets
+func (db *Database) Validate() error {
+	if db.CommonAddress < 1 || db.CommonAddress > config.MaxCommonAddress {
+		return fmt.Errorf("common address %d out of range 1-%d", db.CommonAddress, config.MaxCommonAddress)
+	}
+
+	kinds := make(map[int]PointKind, len(db.Points))
+	for _, point := range db.Points {
+		if point.Address < 1 || point.Address > config.MaxInfoObjAddr {
+			return fmt.Errorf("ioa %d out of range 1-%d", point.Address, config.MaxInfoObjAddr)
+		}
+		if _, ok := kinds[point.Address]; ok {
+			return fmt.Errorf("duplicate ioa %d", point.Address)
+		}
+		if !validKind(point.Kind) {
+			return fmt.Errorf("ioa %d: unknown type %q", point.Address, point.Kind)
+		}
+		maxGroup := 16
+		if point.Kind == KindCounter {
+			maxGroup = 4
+		}
+		if point.Group < 0 || point.Group > maxGroup {
+			return fmt.Errorf("ioa %d: group %d out of range 1-%d", point.Address, point.Group, maxGroup)
+		}
+		if _, err := ParseQuality(point.Quality); err != nil {
+			return fmt.Errorf("ioa %d: %w", point.Address, err)
+		}
+		kinds[point.Address] = point.Kind
+	}
+
+	for _, point := range db.Points {
+		if point.Feedback == 0 {
+			continue
+		}
+		target, ok := kinds[point.Feedback]
+		switch {
+		case point.Kind.monitored():
+			return fmt.Errorf("ioa %d: only commands and setpoints have feedback", point.Address)
+		case !ok:
+			return fmt.Errorf("ioa %d: feedback ioa %d does not exist", point.Address, point.Feedback)
+		case point.Kind == KindCommand && target != KindSingle && target != KindDouble:
+			return fmt.Errorf("ioa %d: command feedback ioa %d is not an indication", point.Address, point.Feedback)
+		case point.Kind == KindSetpoint && target != KindNormalized && target != KindScaled && target != KindFloat:
+			return fmt.Errorf("ioa %d: setpoint feedback ioa %d is not a measurement", point.Address, point.Feedback)
+		}
+	}
+	return nil
+}
+
+func validKind(k PointKind) bool {
+	for _, v := range PointKinds {
+		if v == k {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseQuality parses comma separated quality flags: iv, nt, sb, bl and ov
+func ParseQuality(s string) (asdu.QualityDescriptor, error) {
+	var q asdu.QualityDescriptor
+	for _, flag := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return r == ',' || r == '|' || r == ' ' }) {
+		switch flag {
+		case "iv":
+			q |= asdu.QDSInvalid
+		case "nt":
+			q |= asdu.QDSNotTopical
+		case "sb":
+			q |= asdu.QDSSubstituted
+		case "bl":
+			q |= asdu.QDSBlocked
+		case "ov":
+			q |= asdu.QDSOverflow
+		default:
+			return 0, fmt.Errorf("unknown quality flag %q", flag)
+		}
+	}
+	return q, nil
+}
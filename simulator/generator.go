@@ -0,0 +1,324 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+// GeneratorKind is the pattern a generator drives a point with
+type GeneratorKind string
+
+const (
+	// GenSine oscillates between Min and Max over Period
+	GenSine GeneratorKind = "sine"
+	// GenRamp rises from Min to Max over Period and starts over
+	GenRamp GeneratorKind = "ramp"
+	// GenRandomWalk moves by up to Step per tick within Min and Max
+	GenRandomWalk GeneratorKind = "random_walk"
+	// GenStep cycles through Values, or between Min and Max, every tick
+	GenStep GeneratorKind = "step"
+	// GenToggle toggles an indication every tick
+	GenToggle GeneratorKind = "toggle"
+	// GenBurst toggles an indication Count times, Spacing apart, every tick
+	GenBurst GeneratorKind = "burst"
+	// GenQuality sets the Quality flags for Duration every tick
+	GenQuality GeneratorKind = "quality"
+)
+
+// GeneratorKinds lists all generator kinds
+var GeneratorKinds = []GeneratorKind{GenSine, GenRamp, GenRandomWalk, GenStep, GenToggle, GenBurst, GenQuality}
+
+// analog reports whether the generator produces measured values
+func (k GeneratorKind) analog() bool {
+	return k == GenSine || k == GenRamp || k == GenRandomWalk || k == GenStep
+}
+
+// Generator drives a monitored point with spontaneous changes. Times are
+// in seconds.
+type Generator struct {
+	Address int           `json:"ioa"`
+	Kind    GeneratorKind `json:"generator"`
+	// Interval is the time between ticks, 1 by default
+	Interval float64 `json:"interval,omitempty"`
+	// Period is the length of a sine or ramp cycle, 60 by default
+	Period float64 `json:"period,omitempty"`
+	// Min and Max bound the raw value of analog generators; both zero
+	// selects a default range for the point type
+	Min float64 `json:"min,omitempty"`
+	Max float64 `json:"max,omitempty"`
+	// Step is the largest change of a random walk, 1% of the range by default
+	Step float64 `json:"step,omitempty"`
+	// Values are the levels of a step generator
+	Values []float64 `json:"values,omitempty"`
+	// Count and Spacing shape a burst, 5 toggles 0.05 apart by default
+	Count   int     `json:"count,omitempty"`
+	Spacing float64 `json:"spacing,omitempty"`
+	// Quality, Duration and Probability describe injected quality faults:
+	// the flags are set for Duration with Probability (default 1) per tick
+	Quality     string  `json:"quality,omitempty"`
+	Duration    float64 `json:"duration,omitempty"`
+	Probability float64 `json:"probability,omitempty"`
+	// TimeTag overrides the time tag setting of the point
+	TimeTag *bool `json:"time_tag,omitempty"`
+}
+
+// GeneratorFile is the JSON file the generators are configured in
+type GeneratorFile struct {
+	Generators []Generator `json:"generators"`
+}
+
+// LoadGenerators reads generators from a JSON file; they are validated
+// against the database by StartGenerators
+func LoadGenerators(path string) ([]Generator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file GeneratorFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return file.Generators, nil
+}
+
+// validate checks a generator against the kind of its point
+func (g *Generator) validate(kind PointKind) error {
+	indication := kind == KindSingle || kind == KindDouble
+	switch {
+	case !validGenerator(g.Kind):
+		return fmt.Errorf("unknown generator %q", g.Kind)
+	case !kind.monitored():
+		return ErrorNotMonitored
+	case g.Kind.analog() && indication:
+		return fmt.Errorf("%s generator needs a measurement or counter, not %s", g.Kind, kind)
+	case (g.Kind == GenToggle || g.Kind == GenBurst) && !indication:
+		return fmt.Errorf("%s generator needs an indication, not %s", g.Kind, kind)
+	case g.Interval < 0 || g.Period < 0 || g.Step < 0 || g.Count < 0 || g.Spacing < 0 || g.Duration < 0:
+		return fmt.Errorf("negative time or count")
+	case g.Min > g.Max:
+		return fmt.Errorf("min %g above max %g", g.Min, g.Max)
+	case g.Probability < 0 || g.Probability > 1:
+		return fmt.Errorf("probability %g out of range 0-1", g.Probability)
+	case g.Kind == GenBurst && float64(g.count())*g.spacing() >= g.interval().Seconds():
+		return fmt.Errorf("burst of %d toggles does not fit into the interval", g.count())
+	case g.Kind == GenQuality && g.Duration >= g.interval().Seconds():
+		return fmt.Errorf("fault duration %g not shorter than the interval", g.Duration)
+	}
+	if g.Kind == GenQuality {
+		q, err := ParseQuality(g.Quality)
+		if err != nil {
+			return err
+		}
+		if q == 0 {
+			return fmt.Errorf("quality generator without quality flags")
+		}
+	}
+	return nil
+}
+
+func validGenerator(k GeneratorKind) bool {
+	for _, v := range GeneratorKinds {
+		if v == k {
+			return true
+		}
+	}
+	return false
+}
+
+func seconds(s, def float64) time.Duration {
+	if s <= 0 {
+		s = def
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+func (g *Generator) interval() time.Duration {
+	return seconds(g.Interval, 1)
+}
+
+func (g *Generator) period() time.Duration {
+	return seconds(g.Period, 60)
+}
+
+func (g *Generator) spacing() float64 {
+	return seconds(g.Spacing, 0.05).Seconds()
+}
+
+func (g *Generator) count() int {
+	if g.Count <= 0 {
+		return 5
+	}
+	return g.Count
+}
+
+// limits returns the value range of an analog generator
+func (g *Generator) limits(kind PointKind) (float64, float64) {
+	if g.Min != 0 || g.Max != 0 {
+		return g.Min, g.Max
+	}
+	switch kind {
+	case KindNormalized:
+		return -1, 1
+	case KindScaled:
+		return 0, 1000
+	case KindCounter:
+		return 0, 100000
+	default:
+		return 0, 100
+	}
+}
+
+// generator is a running generator with its state
+type generator struct {
+	Generator
+	kind  PointKind
+	start time.Time
+	step  int
+}
+
+// StartGenerators validates the generators against the database and runs
+// them until the server is closed. A point may have one value generator
+// and any number of quality generators.
+func (s *Server) StartGenerators(generators []Generator) error {
+	running := make([]*generator, 0, len(generators))
+	driven := make(map[int]bool)
+	for _, g := range generators {
+		s.mu.Lock()
+		p, ok := s.points[g.Address]
+		var kind PointKind
+		if ok {
+			kind = p.Kind
+		}
+		s.mu.Unlock()
+
+		if !ok {
+			return fmt.Errorf("generator for ioa %d: %w", g.Address, ErrorUnknownPoint)
+		}
+		if err := g.validate(kind); err != nil {
+			return fmt.Errorf("generator for ioa %d: %w", g.Address, err)
+		}
+		if g.Kind != GenQuality {
+			if driven[g.Address] {
+				return fmt.Errorf("generator for ioa %d: more than one value generator", g.Address)
+			}
+			driven[g.Address] = true
+		}
+		running = append(running, &generator{Generator: g, kind: kind})
+	}
+
+	for _, g := range running {
+		go s.runGenerator(g)
+	}
+	return nil
+}
+
+// runGenerator ticks a generator until the server is closed
+func (s *Server) runGenerator(g *generator) {
+	g.start = time.Now()
+	ticker := time.NewTicker(g.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			if err := s.tick(g, now); err != nil {
+				s.Logger.Errorf("Generator %s for %d: %v", g.Kind, g.Address, err)
+			}
+		}
+	}
+}
+
+// tick advances a generator by one interval
+func (s *Server) tick(g *generator, now time.Time) error {
+	switch g.Kind {
+	case GenToggle:
+		return s.updateTagged(g.Address, asdu.Spontaneous, g.TimeTag, func(p *state) {
+			p.toggle()
+		})
+	case GenBurst:
+		for i := 0; i < g.count(); i++ {
+			if i > 0 && !s.sleep(seconds(g.spacing(), 0)) {
+				return nil
+			}
+			if err := s.updateTagged(g.Address, asdu.Spontaneous, g.TimeTag, func(p *state) {
+				p.toggle()
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case GenQuality:
+		if g.Probability > 0 && rand.Float64() >= g.Probability {
+			return nil
+		}
+		flags, _ := ParseQuality(g.Quality)
+		if err := s.updateTagged(g.Address, asdu.Spontaneous, g.TimeTag, func(p *state) {
+			p.quality |= flags
+		}); err != nil {
+			return err
+		}
+		if !s.sleep(seconds(g.Duration, 0)) {
+			return nil
+		}
+		return s.updateTagged(g.Address, asdu.Spontaneous, g.TimeTag, func(p *state) {
+			p.quality &^= flags
+		})
+	default:
+		return s.updateTagged(g.Address, asdu.Spontaneous, g.TimeTag, func(p *state) {
+			p.value = g.next(p.value, now.Sub(g.start))
+		})
+	}
+}
+
+// next returns the next value of an analog generator
+func (g *generator) next(current float64, elapsed time.Duration) float64 {
+	min, max := g.limits(g.kind)
+	phase := math.Mod(elapsed.Seconds(), g.period().Seconds()) / g.period().Seconds()
+
+	var value float64
+	switch g.Kind {
+	case GenSine:
+		value = (min+max)/2 + (max-min)/2*math.Sin(2*math.Pi*phase)
+	case GenRamp:
+		value = min + (max-min)*phase
+	case GenRandomWalk:
+		step := g.Step
+		if step == 0 {
+			step = (max - min) / 100
+		}
+		value = math.Max(min, math.Min(max, current+(rand.Float64()*2-1)*step))
+	case GenStep:
+		levels := g.Values
+		if len(levels) == 0 {
+			levels = []float64{min, max}
+		}
+		value = levels[g.step%len(levels)]
+		g.step++
+	}
+
+	if g.kind == KindScaled || g.kind == KindCounter {
+		value = math.Round(value)
+	}
+	return value
+}
+
+// sleep waits for d and reports false if the server was closed meanwhile
+func (s *Server) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-s.done:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
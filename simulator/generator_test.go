@@ -0,0 +1,146 @@
+package simulator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+func TestGeneratorNext(t *testing.T) {
+	period := 60 * time.Second
+	tests := []struct {
+		name    string
+		gen     Generator
+		kind    PointKind
+		elapsed time.Duration
+		want    float64
+	}{
+		{"sine start", Generator{Kind: GenSine, Min: 0, Max: 100}, KindFloat, 0, 50},
+		{"sine quarter", Generator{Kind: GenSine, Min: 0, Max: 100}, KindFloat, period / 4, 100},
+		{"sine three quarters", Generator{Kind: GenSine, Min: 0, Max: 100}, KindFloat, 3 * period / 4, 0},
+		{"ramp half", Generator{Kind: GenRamp, Min: 10, Max: 20}, KindFloat, period / 2, 15},
+		{"ramp starts over", Generator{Kind: GenRamp, Min: 10, Max: 20}, KindFloat, period + period/10, 11},
+		{"ramp period", Generator{Kind: GenRamp, Period: 10}, KindFloat, 5 * time.Second, 50},
+		{"default range of scaled", Generator{Kind: GenRamp}, KindScaled, period / 2, 500},
+		{"default range of normalized", Generator{Kind: GenRamp}, KindNormalized, period / 4, -0.5},
+		{"scaled rounded", Generator{Kind: GenRamp, Min: 0, Max: 10}, KindScaled, period / 3, 3},
+		{"float not rounded", Generator{Kind: GenRamp, Min: 0, Max: 10}, KindFloat, period / 4, 2.5},
+	}
+	for _, tt := range tests {
+		g := &generator{Generator: tt.gen, kind: tt.kind}
+		if got := g.next(0, tt.elapsed); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("%s: next = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+
+	step := &generator{Generator: Generator{Kind: GenStep, Values: []float64{1, 5, 9}}, kind: KindFloat}
+	var levels []float64
+	for i := 0; i < 5; i++ {
+		levels = append(levels, step.next(0, 0))
+	}
+	if want := []float64{1, 5, 9, 1, 5}; !equalValues(levels, want) {
+		t.Errorf("step levels = %v, want %v", levels, want)
+	}
+	pair := &generator{Generator: Generator{Kind: GenStep, Min: 2, Max: 4}, kind: KindFloat}
+	if a, b, c := pair.next(0, 0), pair.next(0, 0), pair.next(0, 0); a != 2 || b != 4 || c != 2 {
+		t.Errorf("step without values = %g, %g, %g, want min and max in turn", a, b, c)
+	}
+
+	walk := &generator{Generator: Generator{Kind: GenRandomWalk, Min: 0, Max: 10, Step: 2}, kind: KindFloat}
+	value := 9.5
+	for i := 0; i < 1000; i++ {
+		next := walk.next(value, 0)
+		if next < 0 || next > 10 || next-value > 2 || value-next > 2 {
+			t.Fatalf("random walk from %g to %g", value, next)
+		}
+		value = next
+	}
+}
+
+func equalValues(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStartGenerators(t *testing.T) {
+	tests := []struct {
+		name string
+		gens []Generator
+		want string
+	}{
+		{"valid", []Generator{{Address: 16385, Kind: GenSine}, {Address: 1, Kind: GenToggle}, {Address: 16385, Kind: GenQuality, Quality: "iv", Duration: 0.5}}, ""},
+		{"unknown point", []Generator{{Address: 99, Kind: GenSine}}, "unknown"},
+		{"unknown kind", []Generator{{Address: 16385, Kind: "square"}}, "unknown generator"},
+		{"command", []Generator{{Address: 24577, Kind: GenToggle}}, "ioa 24577"},
+		{"analog on indication", []Generator{{Address: 1, Kind: GenRamp}}, "needs a measurement"},
+		{"toggle on measurement", []Generator{{Address: 16385, Kind: GenToggle}}, "needs an indication"},
+		{"negative interval", []Generator{{Address: 16385, Kind: GenSine, Interval: -1}}, "negative"},
+		{"min above max", []Generator{{Address: 16385, Kind: GenSine, Min: 5, Max: 1}}, "min 5 above max 1"},
+		{"probability", []Generator{{Address: 16385, Kind: GenQuality, Quality: "iv", Probability: 2}}, "probability"},
+		{"burst too long", []Generator{{Address: 1, Kind: GenBurst, Count: 10, Spacing: 0.2}}, "does not fit"},
+		{"fault too long", []Generator{{Address: 16385, Kind: GenQuality, Quality: "iv", Duration: 1}}, "not shorter"},
+		{"no quality flags", []Generator{{Address: 16385, Kind: GenQuality}}, "without quality flags"},
+		{"two value generators", []Generator{{Address: 16385, Kind: GenSine}, {Address: 16385, Kind: GenRamp}}, "more than one"},
+	}
+	for _, tt := range tests {
+		s, err := NewServer(testDatabase())
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = s.StartGenerators(tt.gens)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: StartGenerators = %v", tt.name, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%s: StartGenerators = %v, want an error about %q", tt.name, err, tt.want)
+		}
+		close(s.done)
+	}
+}
+
+func TestTick(t *testing.T) {
+	s, err := NewServer(testDatabase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	toggle := &generator{Generator: Generator{Address: 1, Kind: GenToggle}, kind: KindSingle}
+	for _, want := range []float64{0, 1} {
+		if err := s.tick(toggle, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		if v, _, _ := s.Value(1); v != want {
+			t.Errorf("toggled single point = %g, want %g", v, want)
+		}
+	}
+
+	burst := &generator{Generator: Generator{Address: 2, Kind: GenBurst, Count: 3, Spacing: 0.001}, kind: KindDouble}
+	if err := s.tick(burst, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if v, _, _ := s.Value(2); v != float64(asdu.DPIDeterminedOff) {
+		t.Errorf("double point after 3 toggles from on = %g, want off", v)
+	}
+
+	// the quality flags are cleared again after the fault, keeping others
+	fault := &generator{Generator: Generator{Address: 16385, Kind: GenQuality, Quality: "nt"}, kind: KindFloat}
+	if err := s.tick(fault, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, q, _ := s.Value(16385); q != asdu.QDSInvalid {
+		t.Errorf("quality after the fault = %v, want invalid as configured", q)
+	}
+}
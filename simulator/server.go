@@ -0,0 +1,746 @@
+package simulator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+	"github.com/thinkgos/go-iecp5/cs104"
+)
+
+var (
+	ErrorUnknownPoint  = fmt.Errorf("unknown point")
+	ErrorNotMonitored  = fmt.Errorf("point is a command or setpoint")
+	ErrorNoMonitored   = fmt.Errorf("no monitored points")
+	ErrorNotListening  = fmt.Errorf("server is not listening")
+	ErrorStartTimedOut = fmt.Errorf("server did not start listening")
+)
+
+// maxObjects is the number of information objects sent per ASDU, which
+// keeps every monitored type within the maximum ASDU size
+const maxObjects = 30
+
+// Logger receives the station's protocol events
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+// Command is a command or setpoint received by the station. Value is 1 or
+// 0 for on and off commands and the raw value for setpoints.
+type Command struct {
+	Type     asdu.TypeID
+	Address  int
+	Value    float64
+	Select   bool
+	Received time.Time
+}
+
+// CommandHandler is called for every accepted command before it is confirmed
+type CommandHandler func(Command)
+
+// state is the current value of a simulated point
+type state struct {
+	Point
+	value   float64
+	quality asdu.QualityDescriptor
+}
+
+// Server is a simulated controlled station serving a point database over
+// IEC 104. It answers interrogations, reads, clock synchronisation and
+// commands, and sends spontaneous changes made with Set and SetQuality.
+type Server struct {
+	Logger Logger
+
+	commonAddr asdu.CommonAddr
+	mu         sync.Mutex
+	points     map[int]*state
+	order      []int
+	handler    CommandHandler
+
+	srv       *cs104.Server
+	addr      string
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// started receives the probe connection of Start, failed the error the
+	// listener stopped with
+	startMu sync.Mutex
+	started chan asdu.Connect
+	probe   asdu.Connect
+	failed  chan error
+}
+
+// NewServer creates a station for a validated copy of the database
+func NewServer(db *Database) (*Server, error) {
+	if err := db.Validate(); err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		Logger:     nopLogger{},
+		commonAddr: asdu.CommonAddr(db.CommonAddress),
+		points:     make(map[int]*state, len(db.Points)),
+		done:       make(chan struct{}),
+	}
+	for _, point := range db.Points {
+		quality, _ := ParseQuality(point.Quality)
+		s.points[point.Address] = &state{Point: point, value: point.Value, quality: quality}
+		s.order = append(s.order, point.Address)
+	}
+
+	s.srv = cs104.NewServer(s)
+	s.srv.SetOnConnectionHandler(func(c asdu.Connect) {
+		if s.isProbe(c) {
+			return
+		}
+		s.Logger.Infof("Client connected")
+	})
+	s.srv.SetConnectionLostHandler(func(c asdu.Connect) {
+		s.startMu.Lock()
+		probe := c == s.probe
+		s.startMu.Unlock()
+		if !probe {
+			s.Logger.Infof("Client disconnected")
+		}
+	})
+	s.srv.SetLogProvider(serverLog{s})
+	s.srv.LogMode(true)
+	return s, nil
+}
+
+// RegisterCommandHandler sets the function called for accepted commands
+func (s *Server) RegisterCommandHandler(handler CommandHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handler = handler
+}
+
+// startAttempts bounds the free ports tried when Start is given port 0
+const startAttempts = 5
+
+// Start listens on addr in the background and returns once connections
+// are accepted, or with the error listening failed with. A port of 0
+// picks a free port, see Addr.
+func (s *Server) Start(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		listenAddr := addr
+		if port == "0" {
+			if listenAddr, err = freePort(host); err != nil {
+				return err
+			}
+		}
+		err = s.listen(listenAddr)
+		if err == nil || port != "0" || attempt == startAttempts {
+			return err
+		}
+		// someone else took the free port in the meantime
+	}
+}
+
+// listen runs the library's listener and waits until a probe connection
+// reaches the station, so the port is known to be ours
+func (s *Server) listen(addr string) error {
+	s.startMu.Lock()
+	s.started = make(chan asdu.Connect, 1)
+	s.failed = make(chan error, 1)
+	started, failed := s.started, s.failed
+	s.startMu.Unlock()
+	defer func() {
+		s.startMu.Lock()
+		s.started, s.failed = nil, nil
+		s.startMu.Unlock()
+	}()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.srv.ListenAndServer(addr)
+	}()
+
+	deadline := time.NewTimer(2 * time.Second)
+	defer deadline.Stop()
+	retry := time.NewTicker(10 * time.Millisecond)
+	defer retry.Stop()
+	var probe net.Conn
+	defer func() {
+		if probe != nil {
+			probe.Close()
+		}
+	}()
+	for {
+		select {
+		case <-started:
+			s.addr = addr
+			return nil
+		case <-stopped:
+			select {
+			case err := <-failed:
+				return err
+			default:
+				return ErrorNotListening
+			}
+		case <-deadline.C:
+			_ = s.srv.Close()
+			return ErrorStartTimedOut
+		case <-retry.C:
+			if probe != nil {
+				continue
+			}
+			if conn, err := net.DialTimeout("tcp", addr, 100*time.Millisecond); err == nil {
+				probe = conn
+			}
+		}
+	}
+}
+
+// isProbe reports whether a new connection is the probe of Start
+func (s *Server) isProbe(c asdu.Connect) bool {
+	s.startMu.Lock()
+	defer s.startMu.Unlock()
+
+	if s.started == nil {
+		return false
+	}
+	s.probe = c
+	select {
+	case s.started <- c:
+	default:
+	}
+	return true
+}
+
+// freePort returns an address on host with a port that is free right now
+func freePort(host string) (string, error) {
+	l, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+	return l.Addr().String(), nil
+}
+
+// serverLog passes the errors of the library to the station's log. While
+// Start waits, an error means listening failed.
+type serverLog struct {
+	s *Server
+}
+
+func (l serverLog) Critical(format string, v ...interface{}) {
+	l.Error(format, v...)
+}
+
+func (l serverLog) Error(format string, v ...interface{}) {
+	err := fmt.Errorf(format, v...)
+	l.s.startMu.Lock()
+	failed := l.s.failed
+	l.s.startMu.Unlock()
+	if failed != nil {
+		select {
+		case failed <- err:
+		default:
+		}
+		return
+	}
+	select {
+	case <-l.s.done:
+		// the listener stopping after Close is expected
+	default:
+		l.s.Logger.Errorf("%v", err)
+	}
+}
+
+func (l serverLog) Warn(format string, v ...interface{}) {
+	l.s.Logger.Debugf(format, v...)
+}
+
+func (serverLog) Debug(string, ...interface{}) {}
+
+// Addr returns the address the server listens on, e.g. for tests that
+// started it on port 0
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Close stops the generators, stops listening and drops all connections
+func (s *Server) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	if s.addr == "" {
+		return ErrorNotListening
+	}
+	return s.srv.Close()
+}
+
+// Value returns the current raw value and quality of a point
+func (s *Server) Value(ioa int) (float64, asdu.QualityDescriptor, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.points[ioa]
+	if !ok {
+		return 0, 0, false
+	}
+	return p.value, p.quality, true
+}
+
+// Set changes the raw value of a monitored point and sends it spontaneously
+func (s *Server) Set(ioa int, value float64) error {
+	return s.update(ioa, asdu.Spontaneous, func(p *state) {
+		p.value = value
+	})
+}
+
+// SetQuality changes the quality of a monitored point and sends it spontaneously
+func (s *Server) SetQuality(ioa int, quality asdu.QualityDescriptor) error {
+	return s.update(ioa, asdu.Spontaneous, func(p *state) {
+		p.quality = quality
+	})
+}
+
+// RandomChange changes a random monitored point: indications toggle,
+// measurements move by up to 5% and counters count up. It returns the
+// address of the changed point.
+func (s *Server) RandomChange() (int, error) {
+	s.mu.Lock()
+	var monitored []int
+	for _, ioa := range s.order {
+		if s.points[ioa].Kind.monitored() {
+			monitored = append(monitored, ioa)
+		}
+	}
+	s.mu.Unlock()
+	if len(monitored) == 0 {
+		return 0, ErrorNoMonitored
+	}
+
+	ioa := monitored[rand.Intn(len(monitored))]
+	return ioa, s.update(ioa, asdu.Spontaneous, func(p *state) {
+		switch p.Kind {
+		case KindSingle, KindDouble:
+			p.toggle()
+		case KindCounter:
+			p.value += float64(1 + rand.Intn(10))
+		case KindNormalized:
+			p.value = math.Max(-1, math.Min(1, p.value+(rand.Float64()-0.5)*0.1))
+		default:
+			step := math.Max(math.Abs(p.value)*0.05, 1)
+			p.value += (rand.Float64()*2 - 1) * step
+			if p.Kind == KindScaled {
+				p.value = math.Round(p.value)
+			}
+		}
+	})
+}
+
+// toggle switches an indication between on and off; intermediate and
+// faulty double points switch on
+func (p *state) toggle() {
+	switch {
+	case p.Kind == KindSingle:
+		p.value = 1 - math.Min(p.value, 1)
+	case p.value == float64(asdu.DPIDeterminedOn):
+		p.value = float64(asdu.DPIDeterminedOff)
+	default:
+		p.value = float64(asdu.DPIDeterminedOn)
+	}
+}
+
+// update modifies a monitored point and sends it to all clients
+func (s *Server) update(ioa int, cause asdu.Cause, change func(p *state)) error {
+	return s.updateTagged(ioa, cause, nil, change)
+}
+
+// updateTagged is update with the point's time tag setting overridden
+// unless timeTag is nil
+func (s *Server) updateTagged(ioa int, cause asdu.Cause, timeTag *bool, change func(p *state)) error {
+	s.mu.Lock()
+	p, ok := s.points[ioa]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %d", ErrorUnknownPoint, ioa)
+	}
+	if !p.Kind.monitored() {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %d", ErrorNotMonitored, ioa)
+	}
+	change(p)
+	snapshot := *p
+	s.mu.Unlock()
+
+	if timeTag != nil {
+		snapshot.TimeTag = *timeTag
+	}
+	return s.sendPoints(s.srv, cause, []state{snapshot}, true)
+}
+
+// snapshot copies the monitored points selected by the filter, in database order
+func (s *Server) snapshot(filter func(p *state) bool) []state {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var points []state
+	for _, ioa := range s.order {
+		if p := s.points[ioa]; p.Kind.monitored() && filter(p) {
+			points = append(points, *p)
+		}
+	}
+	return points
+}
+
+// sendPoints sends points grouped by type in ASDUs of at most maxObjects.
+// Points with TimeTag use the CP56Time2a types if timeTags is set, which
+// the standard only allows for spontaneous, requested and returned values.
+func (s *Server) sendPoints(c asdu.Connect, cause asdu.Cause, points []state, timeTags bool) error {
+	type batchKey struct {
+		kind    PointKind
+		timeTag bool
+	}
+	var keys []batchKey
+	batches := make(map[batchKey][]state)
+	for _, p := range points {
+		key := batchKey{p.Kind, timeTags && p.TimeTag}
+		if _, ok := batches[key]; !ok {
+			keys = append(keys, key)
+		}
+		batches[key] = append(batches[key], p)
+	}
+
+	for _, key := range keys {
+		batch := batches[key]
+		for start := 0; start < len(batch); start += maxObjects {
+			end := start + maxObjects
+			if end > len(batch) {
+				end = len(batch)
+			}
+			if err := s.send(c, cause, key.kind, key.timeTag, batch[start:end]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// send sends points of one kind in a single ASDU
+func (s *Server) send(c asdu.Connect, cause asdu.Cause, kind PointKind, timeTag bool, points []state) error {
+	coa := asdu.CauseOfTransmission{Cause: cause}
+	ca := s.commonAddr
+	now := time.Now()
+
+	switch kind {
+	case KindSingle:
+		infos := make([]asdu.SinglePointInfo, len(points))
+		for i, p := range points {
+			infos[i] = asdu.SinglePointInfo{Ioa: asdu.InfoObjAddr(p.Address), Value: p.value != 0, Qds: p.quality, Time: now}
+		}
+		if timeTag {
+			return asdu.SingleCP56Time2a(c, coa, ca, infos...)
+		}
+		return asdu.Single(c, false, coa, ca, infos...)
+	case KindDouble:
+		infos := make([]asdu.DoublePointInfo, len(points))
+		for i, p := range points {
+			infos[i] = asdu.DoublePointInfo{Ioa: asdu.InfoObjAddr(p.Address), Value: asdu.DoublePoint(clamp(p.value, 0, 3)), Qds: p.quality, Time: now}
+		}
+		if timeTag {
+			return asdu.DoubleCP56Time2a(c, coa, ca, infos...)
+		}
+		return asdu.Double(c, false, coa, ca, infos...)
+	case KindNormalized:
+		infos := make([]asdu.MeasuredValueNormalInfo, len(points))
+		for i, p := range points {
+			infos[i] = asdu.MeasuredValueNormalInfo{Ioa: asdu.InfoObjAddr(p.Address), Value: asdu.Normalize(clamp(p.value*32768, math.MinInt16, math.MaxInt16)), Qds: p.quality, Time: now}
+		}
+		if timeTag {
+			return asdu.MeasuredValueNormalCP56Time2a(c, coa, ca, infos...)
+		}
+		return asdu.MeasuredValueNormal(c, false, coa, ca, infos...)
+	case KindScaled:
+		infos := make([]asdu.MeasuredValueScaledInfo, len(points))
+		for i, p := range points {
+			infos[i] = asdu.MeasuredValueScaledInfo{Ioa: asdu.InfoObjAddr(p.Address), Value: int16(clamp(p.value, math.MinInt16, math.MaxInt16)), Qds: p.quality, Time: now}
+		}
+		if timeTag {
+			return asdu.MeasuredValueScaledCP56Time2a(c, coa, ca, infos...)
+		}
+		return asdu.MeasuredValueScaled(c, false, coa, ca, infos...)
+	case KindFloat:
+		infos := make([]asdu.MeasuredValueFloatInfo, len(points))
+		for i, p := range points {
+			infos[i] = asdu.MeasuredValueFloatInfo{Ioa: asdu.InfoObjAddr(p.Address), Value: float32(p.value), Qds: p.quality, Time: now}
+		}
+		if timeTag {
+			return asdu.MeasuredValueFloatCP56Time2a(c, coa, ca, infos...)
+		}
+		return asdu.MeasuredValueFloat(c, false, coa, ca, infos...)
+	case KindCounter:
+		infos := make([]asdu.BinaryCounterReadingInfo, len(points))
+		for i, p := range points {
+			infos[i] = asdu.BinaryCounterReadingInfo{Ioa: asdu.InfoObjAddr(p.Address), Value: asdu.BinaryCounterReading{
+				CounterReading: int32(clamp(p.value, math.MinInt32, math.MaxInt32)),
+				IsInvalid:      p.quality&asdu.QDSInvalid != 0,
+			}, Time: now}
+		}
+		if timeTag {
+			return asdu.IntegratedTotalsCP56Time2a(c, coa, ca, infos...)
+		}
+		return asdu.IntegratedTotals(c, false, coa, ca, infos...)
+	default:
+		return fmt.Errorf("%w: %s", ErrorNotMonitored, kind)
+	}
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, math.Round(v)))
+}
+
+// acceptsCA reports whether a request is addressed to this station
+func (s *Server) acceptsCA(a *asdu.ASDU) bool {
+	return a.CommonAddr == s.commonAddr || a.CommonAddr == asdu.GlobalCommonAddr
+}
+
+// reply answers a system command with a fresh ASDU, since the library has
+// already decoded the information object of the request
+func reply(c asdu.Connect, a *asdu.ASDU, cause asdu.Cause, negative bool, body ...byte) error {
+	u := asdu.NewASDU(c.Params(), asdu.Identifier{
+		Type:       a.Type,
+		Variable:   asdu.VariableStruct{Number: 1},
+		Coa:        asdu.CauseOfTransmission{Cause: cause, IsNegative: negative},
+		OrigAddr:   a.OrigAddr,
+		CommonAddr: a.CommonAddr,
+	})
+	if err := u.AppendInfoObjAddr(asdu.InfoObjAddrIrrelevant); err != nil {
+		return err
+	}
+	u.AppendBytes(body...)
+	return c.Send(u)
+}
+
+// mirror answers a command with a copy of it taken before decoding
+func mirror(c asdu.Connect, request *asdu.ASDU, cause asdu.Cause, negative bool) error {
+	r := request.Reply(cause, request.CommonAddr)
+	r.Coa.IsNegative = negative
+	return c.Send(r)
+}
+
+// InterrogationHandler answers station and group interrogations
+func (s *Server) InterrogationHandler(c asdu.Connect, a *asdu.ASDU, qoi asdu.QualifierOfInterrogation) error {
+	switch {
+	case !s.acceptsCA(a):
+		return reply(c, a, asdu.UnknownCA, true, byte(qoi))
+	case a.Coa.Cause == asdu.Deactivation:
+		return reply(c, a, asdu.DeactivationCon, false, byte(qoi))
+	case qoi < asdu.QOIStation || qoi > asdu.QOIGroup16:
+		return reply(c, a, asdu.ActivationCon, true, byte(qoi))
+	}
+
+	group := int(qoi - asdu.QOIStation)
+	s.Logger.Infof("Interrogation, qualifier %d", qoi)
+	if err := reply(c, a, asdu.ActivationCon, false, byte(qoi)); err != nil {
+		return err
+	}
+	points := s.snapshot(func(p *state) bool {
+		return p.Kind != KindCounter && (group == 0 || p.Group == group)
+	})
+	if err := s.sendPoints(c, asdu.InterrogatedByStation+asdu.Cause(group), points, false); err != nil {
+		return err
+	}
+	return reply(c, a, asdu.ActivationTerm, false, byte(qoi))
+}
+
+// CounterInterrogationHandler answers general and group counter interrogations
+func (s *Server) CounterInterrogationHandler(c asdu.Connect, a *asdu.ASDU, qcc asdu.QualifierCountCall) error {
+	switch {
+	case !s.acceptsCA(a):
+		return reply(c, a, asdu.UnknownCA, true, qcc.Value())
+	case qcc.Request < asdu.QCCGroup1 || qcc.Request > asdu.QCCTotal:
+		return reply(c, a, asdu.ActivationCon, true, qcc.Value())
+	}
+
+	s.Logger.Infof("Counter interrogation, request %d", qcc.Request)
+	if err := reply(c, a, asdu.ActivationCon, false, qcc.Value()); err != nil {
+		return err
+	}
+	cause := asdu.RequestByGeneralCounter
+	group := 0
+	if qcc.Request != asdu.QCCTotal {
+		group = int(qcc.Request)
+		cause = asdu.RequestByGroup1Counter + asdu.Cause(group-1)
+	}
+	points := s.snapshot(func(p *state) bool {
+		return p.Kind == KindCounter && (group == 0 || p.Group == group)
+	})
+	if err := s.sendPoints(c, cause, points, false); err != nil {
+		return err
+	}
+	return reply(c, a, asdu.ActivationTerm, false, qcc.Value())
+}
+
+// ReadHandler sends the current value of a single point
+func (s *Server) ReadHandler(c asdu.Connect, a *asdu.ASDU, ioa asdu.InfoObjAddr) error {
+	points := s.snapshot(func(p *state) bool {
+		return p.Address == int(ioa)
+	})
+	if !s.acceptsCA(a) || len(points) == 0 {
+		u := asdu.NewASDU(c.Params(), a.Identifier)
+		u.Coa = asdu.CauseOfTransmission{Cause: asdu.UnknownIOA, IsNegative: true}
+		if !s.acceptsCA(a) {
+			u.Coa.Cause = asdu.UnknownCA
+		}
+		if err := u.AppendInfoObjAddr(ioa); err != nil {
+			return err
+		}
+		return c.Send(u)
+	}
+	return s.sendPoints(c, asdu.Request, points, true)
+}
+
+// ClockSyncHandler confirms a clock synchronisation with the station time
+func (s *Server) ClockSyncHandler(c asdu.Connect, a *asdu.ASDU, t time.Time) error {
+	s.Logger.Infof("Clock synchronisation to %s", t.Format(time.RFC3339Nano))
+	return reply(c, a, asdu.ActivationCon, !s.acceptsCA(a), asdu.CP56Time2a(time.Now(), c.Params().InfoObjTimeZone)...)
+}
+
+// ResetProcessHandler confirms a reset process command
+func (s *Server) ResetProcessHandler(c asdu.Connect, a *asdu.ASDU, qrp asdu.QualifierOfResetProcessCmd) error {
+	s.Logger.Infof("Reset process, qualifier %d", qrp)
+	return reply(c, a, asdu.ActivationCon, !s.acceptsCA(a), byte(qrp))
+}
+
+// DelayAcquisitionHandler confirms a delay acquisition command
+func (s *Server) DelayAcquisitionHandler(c asdu.Connect, a *asdu.ASDU, msec uint16) error {
+	body := make([]byte, 2)
+	binary.LittleEndian.PutUint16(body, msec)
+	return reply(c, a, asdu.ActivationCon, !s.acceptsCA(a), body...)
+}
+
+// ASDUHandler handles commands and setpoints: a select is confirmed, an
+// execute is confirmed, reflected into the feedback point and terminated
+func (s *Server) ASDUHandler(c asdu.Connect, a *asdu.ASDU) error {
+	request := a.Clone()
+	if !s.acceptsCA(a) {
+		return mirror(c, request, asdu.UnknownCA, true)
+	}
+
+	cmd := Command{Type: a.Type, Received: time.Now()}
+	kind := KindCommand
+	switch a.Type {
+	case asdu.C_SC_NA_1, asdu.C_SC_TA_1:
+		info := a.GetSingleCmd()
+		cmd.Address, cmd.Select = int(info.Ioa), info.Qoc.InSelect
+		if info.Value {
+			cmd.Value = 1
+		}
+	case asdu.C_DC_NA_1, asdu.C_DC_TA_1:
+		info := a.GetDoubleCmd()
+		cmd.Address, cmd.Select = int(info.Ioa), info.Qoc.InSelect
+		if info.Value == asdu.DCOOn {
+			cmd.Value = 1
+		}
+	case asdu.C_SE_NA_1, asdu.C_SE_TA_1:
+		info := a.GetSetpointNormalCmd()
+		cmd.Address, cmd.Select, cmd.Value = int(info.Ioa), info.Qos.InSelect, info.Value.Float64()
+		kind = KindSetpoint
+	case asdu.C_SE_NB_1, asdu.C_SE_TB_1:
+		info := a.GetSetpointCmdScaled()
+		cmd.Address, cmd.Select, cmd.Value = int(info.Ioa), info.Qos.InSelect, float64(info.Value)
+		kind = KindSetpoint
+	case asdu.C_SE_NC_1, asdu.C_SE_TC_1:
+		info := a.GetSetpointFloatCmd()
+		cmd.Address, cmd.Select, cmd.Value = int(info.Ioa), info.Qos.InSelect, float64(info.Value)
+		kind = KindSetpoint
+	default:
+		return fmt.Errorf("unsupported type %s", a.Type)
+	}
+
+	s.mu.Lock()
+	p, ok := s.points[cmd.Address]
+	var point Point
+	if ok {
+		point = p.Point
+	}
+	handler := s.handler
+	s.mu.Unlock()
+
+	switch {
+	case !ok || point.Kind != kind:
+		s.Logger.Errorf("%s for unknown address %d", a.Type, cmd.Address)
+		return mirror(c, request, asdu.UnknownIOA, true)
+	case a.Coa.Cause == asdu.Deactivation:
+		return mirror(c, request, asdu.DeactivationCon, false)
+	case a.Coa.Cause != asdu.Activation:
+		return mirror(c, request, asdu.UnknownCOT, true)
+	case point.Reject:
+		s.Logger.Infof("Rejected %s to %d", a.Type, cmd.Address)
+		return mirror(c, request, asdu.ActivationCon, true)
+	}
+
+	phase := "execute"
+	if cmd.Select {
+		phase = "select"
+	}
+	s.Logger.Infof("%s %s to %d, value %g", a.Type, phase, cmd.Address, cmd.Value)
+	if handler != nil {
+		handler(cmd)
+	}
+	if err := mirror(c, request, asdu.ActivationCon, false); err != nil {
+		return err
+	}
+	if cmd.Select {
+		return nil
+	}
+	if point.Feedback != 0 {
+		if err := s.reflect(point.Feedback, cmd.Value); err != nil {
+			s.Logger.Errorf("Feedback to %d: %v", point.Feedback, err)
+		}
+	}
+	return mirror(c, request, asdu.ActivationTerm, false)
+}
+
+// reflect writes an executed command into its feedback point: on and off
+// into indications, setpoint values into measurements
+func (s *Server) reflect(ioa int, value float64) error {
+	s.mu.Lock()
+	p, ok := s.points[ioa]
+	var kind PointKind
+	if ok {
+		kind = p.Kind
+	}
+	s.mu.Unlock()
+
+	switch kind {
+	case KindSingle:
+		return s.update(ioa, asdu.ReturnInfoRemote, func(p *state) {
+			p.value = value
+		})
+	case KindDouble:
+		return s.update(ioa, asdu.ReturnInfoRemote, func(p *state) {
+			p.value = float64(asdu.DPIDeterminedOff)
+			if value != 0 {
+				p.value = float64(asdu.DPIDeterminedOn)
+			}
+		})
+	default:
+		return s.update(ioa, asdu.Spontaneous, func(p *state) {
+			p.value = value
+		})
+	}
+}
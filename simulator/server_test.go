@@ -0,0 +1,163 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/thinkgos/go-iecp5/asdu"
+)
+
+func testDatabase() *Database {
+	return &Database{CommonAddress: 1, Points: []Point{
+		{Address: 1, Kind: KindSingle, Value: 1},
+		{Address: 2, Kind: KindDouble, Value: 2},
+		{Address: 16385, Kind: KindFloat, Value: 12.5, Quality: "iv"},
+		{Address: 24577, Kind: KindCommand, Feedback: 1},
+		{Address: 25089, Kind: KindSetpoint, Feedback: 16385},
+	}}
+}
+
+func TestStart(t *testing.T) {
+	s, err := NewServer(testDatabase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); !errors.Is(err, ErrorNotListening) {
+		t.Errorf("Close before Start = %v, want %v", err, ErrorNotListening)
+	}
+
+	s, _ = NewServer(testDatabase())
+	log := connLogger(make(chan string, 4))
+	s.Logger = log
+	if err := s.Start("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.HasSuffix(s.Addr(), ":0") {
+		t.Fatalf("Addr = %s, want the picked port", s.Addr())
+	}
+	conn, err := net.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatalf("dial %s: %v", s.Addr(), err)
+	}
+	// the library must have taken the connection before Close, which
+	// otherwise races with its accept loop
+	if msg := <-log; msg != "Client connected" {
+		t.Errorf("log = %q, want the client connecting", msg)
+	}
+	conn.Close()
+
+	// a second station on the same port reports the listen error
+	other, _ := NewServer(testDatabase())
+	if err := other.Start(s.Addr()); err == nil {
+		other.Close()
+		t.Fatal("second station started on a busy port")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if conn, err := net.Dial("tcp", s.Addr()); err == nil {
+		conn.Close()
+		t.Error("still listening after Close")
+	}
+}
+
+func TestStartInvalidAddress(t *testing.T) {
+	s, _ := NewServer(testDatabase())
+	if err := s.Start("localhost"); err == nil {
+		t.Error("Start without a port succeeded")
+	}
+}
+
+func TestSet(t *testing.T) {
+	s, err := NewServer(testDatabase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		ioa  int
+		err  error
+	}{
+		{"indication", 1, nil},
+		{"measurement", 16385, nil},
+		{"command", 24577, ErrorNotMonitored},
+		{"unknown", 99, ErrorUnknownPoint},
+	}
+	for _, tt := range tests {
+		if err := s.Set(tt.ioa, 0); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Set = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	if v, q, ok := s.Value(16385); !ok || v != 0 || q != asdu.QDSInvalid {
+		t.Errorf("Value(16385) = %v, %v, %v; want 0, invalid", v, q, ok)
+	}
+	if err := s.SetQuality(16385, asdu.QDSGood); err != nil {
+		t.Fatal(err)
+	}
+	if _, q, _ := s.Value(16385); q != asdu.QDSGood {
+		t.Errorf("quality after SetQuality = %v", q)
+	}
+
+	ioa, err := s.RandomChange()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ioa != 1 && ioa != 2 && ioa != 16385 {
+		t.Errorf("RandomChange changed %d, which is not monitored", ioa)
+	}
+
+	empty, _ := NewServer(&Database{CommonAddress: 1, Points: []Point{{Address: 24577, Kind: KindCommand}}})
+	if _, err := empty.RandomChange(); !errors.Is(err, ErrorNoMonitored) {
+		t.Errorf("RandomChange without monitored points = %v, want %v", err, ErrorNoMonitored)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(db *Database)
+		want   string
+	}{
+		{"valid", func(*Database) {}, ""},
+		{"common address", func(db *Database) { db.CommonAddress = 0 }, "common address"},
+		{"ioa", func(db *Database) { db.Points[0].Address = 0 }, "out of range"},
+		{"duplicate", func(db *Database) { db.Points[1].Address = 1 }, "duplicate ioa 1"},
+		{"kind", func(db *Database) { db.Points[0].Kind = "switch" }, "unknown type"},
+		{"group", func(db *Database) { db.Points[0].Group = 17 }, "group 17"},
+		{"quality", func(db *Database) { db.Points[0].Quality = "xx" }, "ioa 1"},
+		{"feedback on indication", func(db *Database) { db.Points[0].Feedback = 2 }, "only commands"},
+		{"missing feedback", func(db *Database) { db.Points[3].Feedback = 3 }, "does not exist"},
+		{"command feedback", func(db *Database) { db.Points[3].Feedback = 16385 }, "not an indication"},
+		{"setpoint feedback", func(db *Database) { db.Points[4].Feedback = 1 }, "not a measurement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testDatabase()
+			tt.change(db)
+			err := db.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Validate = %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("Validate = %v, want an error about %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// connLogger passes the station's info messages on
+type connLogger chan string
+
+func (connLogger) Debugf(string, ...interface{}) {}
+func (connLogger) Errorf(string, ...interface{}) {}
+func (l connLogger) Infof(format string, args ...interface{}) {
+	select {
+	case l <- fmt.Sprintf(format, args...):
+	default:
+	}
+}
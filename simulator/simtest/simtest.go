@@ -0,0 +1,92 @@
+// Package simtest starts simulated stations for tests, the way httptest
+// starts HTTP servers.
+package simtest
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"iec104/config"
+	"iec104/simulator"
+)
+
+// CommonAddress is the common address of the stations and their profiles
+const CommonAddress = 1
+
+// ConnectTimeout bounds the wait for a client's link in Connect
+const ConnectTimeout = 5 * time.Second
+
+// Points is the database served when Station is given no points: two
+// indications, two measurements, a command reflected into the single
+// point, a command that is always rejected and a setpoint reflected into
+// the float measurement
+var Points = []simulator.Point{
+	{Address: config.TeleindBaseAddress, Kind: simulator.KindSingle, Value: 1},
+	{Address: config.TeleindBaseAddress + 1, Kind: simulator.KindDouble, Value: 2},
+	{Address: config.TelemetryBaseAddress, Kind: simulator.KindFloat, Value: 12.5},
+	{Address: config.TelemetryBaseAddress + 1, Kind: simulator.KindScaled, Value: 300},
+	{Address: config.TelecontrolBaseAddress, Kind: simulator.KindCommand, Feedback: config.TeleindBaseAddress},
+	{Address: config.TelecontrolBaseAddress + 1, Kind: simulator.KindCommand, Reject: true},
+	{Address: config.TeleregulationBaseAddress, Kind: simulator.KindSetpoint, Feedback: config.TelemetryBaseAddress},
+}
+
+// Station starts a station serving points, or Points when none are given,
+// on a free loopback port. It is closed with the test.
+func Station(t testing.TB, points ...simulator.Point) *simulator.Server {
+	t.Helper()
+	if len(points) == 0 {
+		points = Points
+	}
+	sim, err := simulator.NewServer(&simulator.Database{CommonAddress: CommonAddress, Points: points})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.Start("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sim.Close() })
+	return sim
+}
+
+// Profile returns a profile named "test" for the station at addr
+func Profile(t testing.TB, addr string) *config.Profile {
+	t.Helper()
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := config.NewProfile("test")
+	profile.IPAddress = host
+	if profile.Port, err = strconv.Atoi(port); err != nil {
+		t.Fatal(err)
+	}
+	profile.CommonAddress = CommonAddress
+	return profile
+}
+
+// Connector is a client that can wait for its link to come up, i.e. an
+// iec_client.IEC104Client
+type Connector interface {
+	ConnectContext(ctx context.Context) error
+}
+
+// Connect connects the client and fails the test unless its link is up
+// within ConnectTimeout
+func Connect(t testing.TB, c Connector) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), ConnectTimeout)
+	defer cancel()
+	if err := c.ConnectContext(ctx); err != nil {
+		t.Fatalf("connecting to the simulated station: %v", err)
+	}
+}
+
+// NopLogger discards the messages of clients and stations
+type NopLogger struct{}
+
+func (NopLogger) Debugf(string, ...interface{}) {}
+func (NopLogger) Infof(string, ...interface{})  {}
+func (NopLogger) Errorf(string, ...interface{}) {}
@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"iec104/iec_client"
+)
+
+// alarmHeaders are the columns of the alarm list
+var alarmHeaders = []string{"Raised", "Type", "IOA", "Name", "Alarm", "Message", "Value", "State"}
+
+// alarmList is the virtual content of the alarm list, newest alarm first
+type alarmList struct {
+	tview.TableContentReadOnly
+
+	w      *workspace
+	alarms []iec_client.Alarm
+	count  uint64
+}
+
+// GetRowCount returns the number of alarms plus the header row
+func (l *alarmList) GetRowCount() int {
+	return len(l.alarms) + 1
+}
+
+// GetColumnCount returns the number of columns
+func (l *alarmList) GetColumnCount() int {
+	return len(alarmHeaders)
+}
+
+// GetCell renders a single cell of an alarm. Active alarms are shown in
+// their severity color, alarms back to normal in green, and unacknowledged
+// alarms in bold.
+func (l *alarmList) GetCell(row, column int) *tview.TableCell {
+	if column < 0 || column >= len(alarmHeaders) {
+		return nil
+	}
+	if row == 0 {
+		return tview.NewTableCell(alarmHeaders[column]).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false).
+			SetExpansion(1)
+	}
+	if row > len(l.alarms) {
+		return nil
+	}
+
+	a := l.alarms[row-1]
+	var cell *tview.TableCell
+	switch column {
+	case 0:
+		cell = tview.NewTableCell(formatListTime(a.Raised))
+	case 1:
+		cell = tview.NewTableCell(a.Type.String())
+	case 2:
+		cell = tview.NewTableCell(strconv.Itoa(a.Address))
+	case 3:
+		cell = tview.NewTableCell(l.w.profile.PointName(a.Type.PointType(), a.Address))
+	case 4:
+		cell = tview.NewTableCell(a.Condition.String())
+	case 5:
+		cell = tview.NewTableCell(a.Message)
+	case 6:
+		cell = tview.NewTableCell(l.w.currentValue(a.Type, a.Address))
+	default:
+		cell = tview.NewTableCell(alarmStatus(a))
+	}
+
+	cell.SetTextColor(alarmColor(a))
+	if !a.Acknowledged() {
+		cell.SetAttributes(tcell.AttrBold)
+	}
+	return cell
+}
+
+// reload fetches the alarms from the client if they changed, or always
+// when force is set
+func (l *alarmList) reload(force bool) {
+	count := l.w.client.AlarmCount()
+	if !force && count == l.count {
+		return
+	}
+	l.count = count
+	l.alarms = l.w.client.Alarms()
+}
+
+// unacknowledged returns the number of alarms waiting for acknowledgement
+func (l *alarmList) unacknowledged() int {
+	n := 0
+	for _, a := range l.alarms {
+		if !a.Acknowledged() {
+			n++
+		}
+	}
+	return n
+}
+
+// alarmStatus describes whether an alarm is active and acknowledged
+func alarmStatus(a iec_client.Alarm) string {
+	switch {
+	case a.Active() && a.Acknowledged():
+		return "Active, acked"
+	case a.Active():
+		return "Active"
+	default:
+		return "Cleared"
+	}
+}
+
+// alarmColor returns the color of an alarm by severity, green once cleared
+func alarmColor(a iec_client.Alarm) tcell.Color {
+	switch {
+	case !a.Active():
+		return tcell.ColorGreen
+	case a.Condition.Critical():
+		return tcell.ColorRed
+	default:
+		return tcell.ColorOrange
+	}
+}
+
+// currentValue formats the last known value of a monitored point
+func (w *workspace) currentValue(typ iec_client.DataType, ioa int) string {
+	u, ok := w.client.Point(typ, ioa)
+	if !ok {
+		return "-"
+	}
+	point := w.profile.FindPoint(typ.PointType(), ioa)
+	switch {
+	case typ == iec_client.Telemetry:
+		return point.FormatValue(u.Value)
+	case u.Double:
+		text, _ := point.DoubleStateLabel(int(u.DoubleState))
+		return text
+	default:
+		text, _ := point.StateLabel(u.State)
+		return text
+	}
+}
+
+// alarmHighlight colors a grid or list value cell of a point in alarm:
+// unacknowledged alarms get a red background, acknowledged ones the
+// severity color
+func (w *workspace) alarmHighlight(cell *tview.TableCell, typ iec_client.DataType, ioa int) *tview.TableCell {
+	a, ok := w.client.PointAlarm(typ, ioa)
+	if !ok || !a.Active() {
+		return cell
+	}
+	if a.Acknowledged() {
+		return cell.SetTextColor(alarmColor(a))
+	}
+	return cell.SetTextColor(tcell.ColorWhite).SetBackgroundColor(tcell.ColorDarkRed)
+}
+
+// setupAlarmsView creates the alarm list table
+func (w *workspace) setupAlarmsView() {
+	w.alarms = &alarmList{w: w}
+	w.alarmsTable = tview.NewTable().
+		SetContent(w.alarms).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	w.alarmsTable.SetBorder(true)
+	w.updateAlarmsTitle()
+
+	w.alarmsTable.SetSelectedFunc(func(row, column int) {
+		w.acknowledgeRow(row)
+	})
+	w.alarmsTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'a':
+			row, _ := w.alarmsTable.GetSelection()
+			w.acknowledgeRow(row)
+		case 'A':
+			n := w.client.AcknowledgeAll()
+			w.logger.Infof("Acknowledged %d alarms", n)
+			w.alarmsChanged()
+		case 'b':
+			w.profile.AlarmBell = !w.profile.AlarmBell
+			if err := w.ui.config.Save(); err != nil {
+				w.logger.Errorf("Error saving config: %v", err)
+			}
+			w.logger.Infof("Alarm bell %s", onOff(w.profile.AlarmBell))
+			w.updateAlarmsTitle()
+		default:
+			return event
+		}
+		return nil
+	})
+}
+
+// acknowledgeRow acknowledges the alarm shown in a row of the alarm list
+func (w *workspace) acknowledgeRow(row int) {
+	if row < 1 || row > len(w.alarms.alarms) {
+		return
+	}
+	a := w.alarms.alarms[row-1]
+	if w.client.Acknowledge(a.Type, a.Address) {
+		w.logger.Infof("Acknowledged %s alarm of %s %d", a.Condition, a.Type, a.Address)
+	}
+	w.alarmsChanged()
+}
+
+// alarmsChanged updates the alarm list and the highlighted cells after
+// alarms were acknowledged
+func (w *workspace) alarmsChanged() {
+	w.alarms.reload(true)
+	w.updateAlarmsTitle()
+	w.refreshData()
+	w.ui.updateTabBar()
+}
+
+// updateAlarmsTitle shows the alarm counts and bell setting in the border
+func (w *workspace) updateAlarmsTitle() {
+	w.alarmsTable.SetTitle(fmt.Sprintf("Alarms: %d, %d unacknowledged, bell %s (Enter/a: ack, A: ack all, b: bell)",
+		len(w.alarms.alarms), w.alarms.unacknowledged(), onOff(w.profile.AlarmBell)))
+}
+
+// checkAlarms picks up alarm changes and reports whether a new alarm was
+// raised since the last check
+func (w *workspace) checkAlarms() bool {
+	if w.client.AlarmCount() == w.alarms.count {
+		return false
+	}
+	w.alarms.reload(false)
+	w.updateAlarmsTitle()
+
+	raised := false
+	for _, a := range w.alarms.alarms {
+		if a.Raised.After(w.alarmsSeen) {
+			w.alarmsSeen = a.Raised
+			raised = true
+		}
+	}
+	if !w.alarmsMode && !w.eventsMode && !w.monitorMode && !w.statsMode {
+		w.refreshData()
+	}
+	return raised
+}
+
+// toggleAlarms switches between the alarm list and the data view
+func (w *workspace) toggleAlarms() {
+	w.alarmsMode = !w.alarmsMode
+	w.eventsMode = false
+	w.monitorMode = false
+	w.statsMode = false
+	if w.alarmsMode {
+		w.alarms.reload(true)
+		w.updateAlarmsTitle()
+	}
+	w.showView()
+}
+
+// refreshAlarms checks every workspace for alarm changes and rings the
+// terminal bell for new alarms of profiles with the alarm bell enabled
+func (a *App) refreshAlarms() {
+	changed := false
+	for _, w := range a.workspaces {
+		before := w.alarms.count
+		if w.checkAlarms() && w.profile.AlarmBell {
+			a.bell = true
+		}
+		changed = changed || w.alarms.count != before
+	}
+	if changed {
+		a.updateTabBar()
+	}
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
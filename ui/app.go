@@ -4,42 +4,136 @@ import (
 	"fmt"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"google.golang.org/grpc"
+	"iec104/api"
 	"iec104/config"
 	"iec104/iec_client"
-	"math"
-	"strconv"
-	"sync/atomic"
+	"iec104/metrics"
+	"iec104/rpc"
+	"net/http"
 )
 
 // App represents the main application UI
 type App struct {
 	app           *tview.Application
 	config        *config.Config
-	iecClient     *iec_client.IEC104Client
 	logger        *Logger
 	pages         *tview.Pages
 	operationForm *tview.Form
-	dataTable     *tview.Table
+	dataPages     *tview.Pages
 	logView       *tview.TextView
+	workspaceBar  *tview.TextView
 	tabBar        *tview.TextView
-	currentTab    iec_client.DataType
 	statusBar     *tview.TextView
+	options       Options
+
+	workspaces      []*workspace
+	active          *workspace
+	nextWorkspaceID int
+	overview        *tview.Table
+	showOverview    bool
+	closer          chan struct{}
+	// exporter serves the metrics of every workspace when enabled
+	exporter      *metrics.Exporter
+	metricsServer *http.Server
+	// apiServer serves the HTTP JSON API for every workspace when enabled
+	apiServer *api.Server
+	apiHTTP   *http.Server
+	// rpcServer serves the gRPC service for every workspace when enabled
+	rpcServer *rpc.Server
+	rpcGRPC   *grpc.Server
+	// bell rings the terminal bell on the next draw
+	bell bool
+}
 
-	started atomic.Bool
+// Options controls how the application starts
+type Options struct {
+	LogLevel       LoggerLevel
+	StartConnected bool
+	// Replay is a capture file replayed into the first workspace
+	Replay string
+	// Metrics is the address to serve Prometheus metrics on, if any
+	Metrics string
+	// API is the address to serve the HTTP JSON API on, if any
+	API string
+	// APIToken, if set, is required by the API for commands
+	APIToken string
+	// APIOrigins are other origins allowed to open the API's WebSocket,
+	// separated by commas
+	APIOrigins string
+	// GRPC is the address to serve the gRPC service on, if any
+	GRPC string
+	// GRPCReflection enables server reflection on the gRPC service
+	GRPCReflection bool
 }
 
 // NewApp creates a new application UI
-func NewApp(cfg *config.Config) *App {
+func NewApp(cfg *config.Config, opts Options) *App {
+	if opts.LogLevel == "" {
+		opts.LogLevel = LoggerLevelInfo
+	}
+
 	app := &App{
-		app:        tview.NewApplication(),
-		config:     cfg,
-		iecClient:  iec_client.NewIEC104Client(cfg),
-		currentTab: iec_client.Telemetry,
+		app:     tview.NewApplication(),
+		config:  cfg,
+		options: opts,
+		closer:  make(chan struct{}),
 	}
 
 	// Initialize UI components
 	app.setupUI()
 
+	if opts.Metrics != "" {
+		app.exporter = metrics.NewExporter()
+		srv, err := app.exporter.Serve(opts.Metrics)
+		if err != nil {
+			app.logger.Errorf("Error serving metrics: %v", err)
+			app.exporter = nil
+		} else {
+			app.metricsServer = srv
+			app.logger.Infof("Serving metrics on http://%s%s", opts.Metrics, metrics.Path)
+		}
+	}
+
+	if opts.API != "" {
+		app.apiServer = api.NewServer()
+		app.apiServer.Token = opts.APIToken
+		app.apiServer.Origins = api.ParseOrigins(opts.APIOrigins)
+		srv, err := app.apiServer.Serve(opts.API)
+		if err != nil {
+			app.logger.Errorf("Error serving API: %v", err)
+			app.apiServer = nil
+		} else {
+			app.apiHTTP = srv
+			app.logger.Infof("Serving API on http://%s%s", opts.API, api.Prefix)
+		}
+	}
+
+	if opts.GRPC != "" {
+		app.rpcServer = rpc.NewServer()
+		app.rpcServer.Reflection = opts.GRPCReflection
+		srv, err := app.rpcServer.Serve(opts.GRPC)
+		if err != nil {
+			app.logger.Errorf("Error serving gRPC: %v", err)
+			app.rpcServer = nil
+		} else {
+			app.rpcGRPC = srv
+			app.logger.Infof("Serving gRPC on %s", opts.GRPC)
+		}
+	}
+
+	// Open the active profile in the first workspace
+	app.openWorkspace(cfg.Profile)
+
+	if opts.Replay != "" {
+		app.active.replay(opts.Replay)
+	} else if opts.StartConnected {
+		app.active.toggleConnection()
+	}
+
+	go app.refreshOverview()
+	go app.refreshActive()
+
 	return app
 }
 
@@ -52,14 +146,18 @@ func (a *App) setupUI() {
 	a.setupLogView()
 
 	// Create logger
-	a.logger = NewLogger(a.logView, LoggerLevelInfo)
+	a.logger = NewLogger(a.logView, a.options.LogLevel)
 	a.logger.Infof("Application started")
 
 	// Setup config form
 	a.setupConfigForm()
 
-	// Setup data table
-	a.setupDataTable()
+	// Setup pages holding one data table per workspace and the overview
+	a.dataPages = tview.NewPages()
+	a.setupOverview()
+
+	// Setup workspace bar
+	a.setupWorkspaceBar()
 
 	// Setup tab bar
 	a.setupTabBar()
@@ -72,8 +170,9 @@ func (a *App) setupUI() {
 	flex.SetDirection(tview.FlexRow).
 		AddItem(a.operationForm, 5, 1, false).
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(a.workspaceBar, 1, 1, false).
 			AddItem(a.tabBar, 1, 1, false).
-			AddItem(a.dataTable, 0, 10, true).
+			AddItem(a.dataPages, 0, 10, true).
 			AddItem(a.logView, 5, 1, false).
 			AddItem(a.statusBar, 1, 1, false),
 			0, 8, false)
@@ -84,65 +183,13 @@ func (a *App) setupUI() {
 	// Set up key bindings
 	a.setupKeyBindings()
 
-	a.iecClient.RegisterConnectionStateHandler(func(b bool) {
-		a.app.QueueUpdateDraw(func() {
-			a.updateStatusBar()
-		})
-	})
-
-	a.iecClient.RegisterDataHandler(func(typ iec_client.DataType, iot int, data interface{}) {
-		if typ != a.currentTab {
-			return
+	a.app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		if a.bell {
+			a.bell = false
+			_ = screen.Beep()
 		}
-
-		var (
-			rowMax  int
-			address int
-		)
-
-		switch typ {
-		case iec_client.Telemetry:
-			rowMax = int(math.Ceil(float64(a.config.TelemetryCount) / 10))
-			address = iot - 0x4000 - 1
-			if address > a.config.TelemetryCount {
-				return
-			}
-		case iec_client.Teleindication:
-			rowMax = int(math.Ceil(float64(a.config.TeleindCount) / 10))
-			address = iot - 1
-			if address > a.config.TeleindCount {
-				return
-			}
-		default:
-			return
-		}
-		if address < 0 {
-			a.logger.Errorf("Invalid address: %d", address)
-			return
-		}
-
-		row := (address/10 + 1) * 2
-		col := address%10 + 1
-
-		if row > rowMax*2 {
-			return
-		}
-
-		a.app.QueueUpdateDraw(func() {
-			switch val := data.(type) {
-			case float64:
-				a.dataTable.SetCell(row, col, tview.NewTableCell(fmt.Sprintf("%.2f", val)))
-			case bool:
-				if val {
-					a.dataTable.SetCell(row, col, tview.NewTableCell("ON"))
-				} else {
-					a.dataTable.SetCell(row, col, tview.NewTableCell("OFF"))
-				}
-			}
-		})
-
+		return false
 	})
-	a.iecClient.Logger = a.logger
 }
 
 // setupConfigForm creates the configuration form
@@ -156,55 +203,23 @@ func (a *App) setupConfigForm() {
 	})
 
 	a.operationForm.AddButton("Start", func() {
-		a.toggleConnection()
+		a.active.toggleConnection()
 	})
 
-}
-
-// setupDataTable creates the data table
-func (a *App) setupDataTable() {
-	a.dataTable = tview.NewTable().SetBorders(true)
-	a.dataTable.SetBorder(true).SetTitle("Data")
-
-	// Make headers fixed so they don't disappear when scrolling
-	a.dataTable.SetFixed(1, 0)
-	a.dataTable.SetDoneFunc(func(key tcell.Key) {
-		if key == tcell.KeyEnter {
-			a.dataTable.SetSelectable(true, true)
-		}
+	a.operationForm.AddButton("Profiles", func() {
+		a.showProfileDialog()
 	})
 
-	// Initialize table headers
-	a.updateTableHeaders()
-
-	// Initialize table data
-	a.updateTableData()
-
-	// Also keep the selected func for double-clicks
-	a.dataTable.SetSelectedFunc(func(row, column int) {
-		if row == 0 {
-			// Header row, do nothing
-			return
-		}
-
-		// Handle cell selection based on current tab
-		switch a.currentTab {
-		case iec_client.Telecontrol:
-			// Only respond to clicks on the action column (column 3)
-			a.logger.Infof("Selected Telecontrol row %d, column %d", row-1, column-1)
-			a.showTelecontrolDialog(row, column)
-		case iec_client.Teleregulation:
-			// Only respond to clicks on the action column (column 4)
-			a.logger.Infof("Selected Telecontrol row %d, column %d", row-1, column-1)
-			a.showTeleregulationDialog(row, column)
-		case iec_client.Telemetry, iec_client.Teleindication:
-			// Only respond to clicks on the action column (column 0)
-			a.logger.Infof("Selected %s row %d, column %d", a.currentTab, row-1, column-1)
-			a.showDescriptionDialog(row, column)
+	a.operationForm.AddButton("Close Workspace", func() {
+		a.closeWorkspace(a.active)
+	})
 
-		}
+	a.operationForm.AddButton("Points", func() {
+		a.showPointsDialog()
+	})
 
-		a.dataTable.SetSelectable(false, false)
+	a.operationForm.AddButton("Capture", func() {
+		a.active.toggleCapture()
 	})
 }
 
@@ -214,8 +229,6 @@ func (a *App) setupTabBar() {
 		SetDynamicColors(true).
 		SetRegions(true).
 		SetWrap(false)
-
-	a.updateTabBar()
 }
 
 // setupLogView creates the log view
@@ -233,7 +246,6 @@ func (a *App) setupStatusBar() {
 	a.statusBar = tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
-	a.updateStatusBar()
 }
 
 // setupKeyBindings sets up global key bindings
@@ -252,188 +264,135 @@ func (a *App) setupKeyBindings() {
 		} else if event.Key() == tcell.KeyF4 {
 			a.switchTab(iec_client.Teleregulation)
 			return nil
-		} else if event.Key() == tcell.KeyEscape {
-			a.iecClient.Close()
-			a.app.Stop()
+		} else if event.Key() == tcell.KeyF5 {
+			a.toggleOverview()
 			return nil
-		}
-		return event
-	})
-}
-
-// updateTableHeaders updates the table headers based on the current tab
-func (a *App) updateTableHeaders() {
-	a.dataTable.Clear()
-
-	for col := 0; col < 10; col++ {
-		a.dataTable.SetCell(0, col+1, tview.NewTableCell(fmt.Sprintf("%-10d", col)).SetAlign(tview.AlignCenter).SetSelectable(false).SetTextColor(tcell.ColorYellow))
-	}
-}
-
-// updateTableData updates the table data based on the current tab
-func (a *App) updateTableData() {
-	a.logger.Debugf("Config: %+v", a.config)
-	// Clear existing data rows but keep headers
-	for row := 1; row < a.dataTable.GetRowCount(); row++ {
-		for col := 0; col < a.dataTable.GetColumnCount(); col++ {
-			a.dataTable.SetCell(row, col, tview.NewTableCell(""))
-		}
-	}
-
-	// Populate data based on current tab
-	switch a.currentTab {
-	case iec_client.Telemetry:
-		rowMax := int(math.Ceil(float64(a.config.TelemetryCount) / 10))
-		for row := 0; row < rowMax; row++ {
-			a.dataTable.SetCell((row)*2+1, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
-			a.dataTable.SetCell((row+1)*2, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
-		}
-		for index, desc := range a.config.TelemetryDescriptions {
-			row := (index/10+1)*2 - 1
-			col := index%10 + 1
-			if row > rowMax*2 {
-				continue
-			}
-			if row < 1 {
-				a.logger.Errorf("Invalid telemetry row: %d", row)
-				continue
-			}
-			a.dataTable.SetCell(row, col, tview.NewTableCell(desc).SetTextColor(tcell.ColorGreen).SetSelectable(false))
-		}
-		for address, point := range a.iecClient.Telemetry {
-			address = address - 0x4000 - 1
-			if address < 0 {
-				a.logger.Errorf("Invalid telemetry address: %d", address)
-				continue
-			}
-			if address >= a.config.TelemetryCount {
-				continue
+		} else if event.Key() == tcell.KeyF6 {
+			a.cycleWorkspace(-1)
+			return nil
+		} else if event.Key() == tcell.KeyF7 {
+			a.cycleWorkspace(1)
+			return nil
+		} else if event.Key() == tcell.KeyF10 {
+			if a.showOverview {
+				a.toggleOverview()
 			}
-			row := (address/10 + 1) * 2
-			col := address%10 + 1
-
-			if row > rowMax*2 {
-				continue
+			a.active.toggleEvents()
+			return nil
+		} else if event.Key() == tcell.KeyF11 {
+			if a.showOverview {
+				a.toggleOverview()
 			}
-
-			a.dataTable.SetCell(row, col, tview.NewTableCell(fmt.Sprintf("%.2f", point.Value)))
-		}
-	case iec_client.Teleindication:
-		rowMax := int(math.Ceil(float64(a.config.TeleindCount) / 10))
-		for row := 0; row < rowMax; row++ {
-			a.dataTable.SetCell((row)*2+1, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
-			a.dataTable.SetCell((row+1)*2, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
-		}
-		for index, desc := range a.config.TeleindDescriptions {
-			row := (index/10+1)*2 - 1
-			col := index%10 + 1
-			if row > rowMax*2 {
-				continue
+			a.active.toggleAlarms()
+			return nil
+		} else if event.Key() == tcell.KeyF12 {
+			if a.showOverview {
+				a.toggleOverview()
 			}
-			if row < 1 {
-				a.logger.Errorf("Invalid teleindication row: %d", row)
-				continue
+			a.active.toggleMonitor()
+			return nil
+		} else if event.Key() == tcell.KeyCtrlT {
+			if a.showOverview {
+				a.toggleOverview()
 			}
-			a.dataTable.SetCell(row, col, tview.NewTableCell(desc).SetTextColor(tcell.ColorGreen).SetSelectable(false))
-		}
-		for address, point := range a.iecClient.Teleindication {
-			address = address - 1
-			if address < 0 {
-				a.logger.Errorf("Invalid teleindication address: %d", address)
-				continue
+			a.active.toggleStats()
+			return nil
+		} else if event.Key() == tcell.KeyF9 {
+			a.active.toggleTrend()
+			return nil
+		} else if event.Key() == tcell.KeyF8 {
+			if a.showOverview {
+				a.toggleOverview()
 			}
-			if address >= a.config.TeleindCount {
-				continue
+			a.active.toggleListView()
+			return nil
+		} else if event.Key() == tcell.KeyEscape {
+			if a.active != nil && a.active.filterBar.HasFocus() {
+				return event
 			}
-
-			row := (address/10 + 1) * 2
-			col := address%10 + 1
-
-			if row > rowMax*2 {
-				continue
+			close(a.closer)
+			for _, w := range a.workspaces {
+				w.close()
 			}
-
-			val := "OFF"
-			if point.Value {
-				val = "ON"
+			if a.metricsServer != nil {
+				_ = a.metricsServer.Close()
 			}
-			a.dataTable.SetCell(row, col, tview.NewTableCell(val))
-		}
-	case iec_client.Telecontrol:
-		rowMax := 10
-		// Add sample telecontrol points or actual ones
-		for row := 0; row < rowMax+1; row++ {
-			a.dataTable.SetCell(row+1, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
-			for col := 0; col < 11; col++ {
-				if row == 0 || col == 0 {
-					continue
-				}
-				index := (row-1)*10 + col - 1
-				if v, ok := a.iecClient.Telecontrol[index]; ok {
-					val := "OFF"
-					if v.Value {
-						val = "ON"
-					}
-
-					a.dataTable.SetCell(row, col, tview.NewTableCell(val))
-				} else {
-					a.dataTable.SetCell(row, col, tview.NewTableCell("OFF"))
-				}
+			if a.apiHTTP != nil {
+				_ = a.apiHTTP.Close()
 			}
-		}
-	case iec_client.Teleregulation:
-		// Add sample teleregulation points or actual ones
-		rowMax := 10
-		for row := 0; row < rowMax+1; row++ {
-			a.dataTable.SetCell(row+1, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
-			for col := 0; col < 11; col++ {
-				if row == 0 || col == 0 {
-					continue
-				}
-				index := (row-1)*10 + col - 1
-				if v, ok := a.iecClient.Teleregulation[index]; ok {
-					a.dataTable.SetCell(row, col, tview.NewTableCell(fmt.Sprintf("%.2f", v.Value)))
-				} else {
-					a.dataTable.SetCell(row, col, tview.NewTableCell("0.00"))
-				}
+			if a.rpcGRPC != nil {
+				a.rpcGRPC.Stop()
 			}
+			a.app.Stop()
+			return nil
 		}
-	}
+		return event
+	})
 }
 
 // updateTabBar updates the tab bar based on the current tab
 func (a *App) updateTabBar() {
 	a.tabBar.Clear()
-	fmt.Fprintf(a.tabBar, "%s F1 Telemetry %s | %s F2 Teleindication %s | %s F3 Telecontrol %s | %s F4 Teleregulation %s",
-		getTabHighlight(a.currentTab == iec_client.Telemetry),
+	fmt.Fprintf(a.tabBar, "%s F1 Telemetry %s|%s F2 Teleindication %s|%s F3 Telecontrol %s|%s F4 Teleregulation %s|%s F8 List %s|%s F9 Trend %s|%s F10 Events %s|%s F11 Alarms%s %s|%s F12 Monitor %s|%s ^T Stats %s",
+		getTabHighlight(a.active.currentTab == iec_client.Telemetry),
+		getTabHighlight(false),
+		getTabHighlight(a.active.currentTab == iec_client.Teleindication),
 		getTabHighlight(false),
-		getTabHighlight(a.currentTab == iec_client.Teleindication),
+		getTabHighlight(a.active.currentTab == iec_client.Telecontrol),
 		getTabHighlight(false),
-		getTabHighlight(a.currentTab == iec_client.Telecontrol),
+		getTabHighlight(a.active.currentTab == iec_client.Teleregulation),
 		getTabHighlight(false),
-		getTabHighlight(a.currentTab == iec_client.Teleregulation),
+		getTabHighlight(a.active.listMode),
+		getTabHighlight(false),
+		getTabHighlight(a.active.trendVisible),
+		getTabHighlight(false),
+		getTabHighlight(a.active.eventsMode),
+		getTabHighlight(false),
+		getTabHighlight(a.active.alarmsMode),
+		alarmBadge(a.active.alarms.unacknowledged()),
+		getTabHighlight(false),
+		getTabHighlight(a.active.monitorMode),
+		getTabHighlight(false),
+		getTabHighlight(a.active.statsMode),
 		getTabHighlight(false))
 }
 
-// updateStatusBar updates the status bar
+// updateStatusBar updates the status bar for the active workspace
 func (a *App) updateStatusBar() {
 	status := "Disconnected"
 	color := "red"
-	if a.iecClient.Connected.Load() {
+	if a.active.client.Connected.Load() {
 		status = "Connected"
 		color = "green"
 	}
+	if a.active.replaying.Load() {
+		status = "Replaying"
+		color = "yellow"
+	}
 	a.statusBar.Clear()
-	fmt.Fprintf(a.statusBar, "Status: [%s]%s[white] | Server: %s:%d | Common Address: %d",
-		color, status, a.config.IPAddress, a.config.Port, a.config.CommonAddress)
+	fmt.Fprintf(a.statusBar, "Status: [%s]%s[white] | Profile: %s | Server: %s:%d | Common Address: %d",
+		color, status, a.active.profile.Name, a.active.profile.IPAddress, a.active.profile.Port, a.active.profile.CommonAddress)
+	if a.active.capturePath != "" {
+		fmt.Fprintf(a.statusBar, " | [red]Capturing[white] %s", a.active.capturePath)
+	}
 }
 
 // switchTab switches to the specified data type tab
 func (a *App) switchTab(tab iec_client.DataType) {
-	a.currentTab = tab
+	if a.showOverview {
+		a.toggleOverview()
+	}
+	a.active.currentTab = tab
+	if a.active.eventsMode || a.active.alarmsMode || a.active.monitorMode || a.active.statsMode {
+		a.active.eventsMode = false
+		a.active.alarmsMode = false
+		a.active.monitorMode = false
+		a.active.statsMode = false
+		a.active.showView()
+	}
 	a.updateTabBar()
-	a.updateTableHeaders()
-	a.updateTableData()
+	a.active.updateTableHeaders()
+	a.active.refreshData()
 	a.logger.Infof("Switched to %s tab", getTabName(tab))
 }
 
@@ -445,43 +404,31 @@ func (a *App) saveConfig() {
 		return
 	}
 
-	a.iecClient.UpdateConfig(a.config)
+	a.active.client.UpdateConfig(a.active.profile)
+	a.updateStatusBar()
+	a.updateWorkspaceBar()
 
 	a.logger.Infof("Configuration saved successfully")
 }
 
-// toggleConnection toggles the connection state
-func (a *App) toggleConnection() {
-	if a.started.Load() {
-		err := a.iecClient.Disconnect()
-		if err != nil {
-			a.logger.Infof("Error disconnecting: %v", err)
-			return
-		}
-		a.logger.Infof("Disconnected from server")
-		a.started.Store(false)
-	} else {
-		err := a.iecClient.Connect()
-		if err != nil {
-			a.logger.Infof("Error connecting: %v", err)
-			return
-		}
-		a.logger.Infof("Connecting to server %s:%d", a.config.IPAddress, a.config.Port)
-		a.started.Store(true)
-	}
-
-	a.updateConnectButton()
-}
-
 func (a *App) updateConnectButton() {
 	// Update the connect button text
 	buttonText := "Start"
-	if a.started.Load() {
+	if a.active.started.Load() {
 		buttonText = "Stop"
 	}
 	a.operationForm.GetButton(1).SetLabel(buttonText)
 }
 
+// updateCaptureButton shows whether the active workspace is capturing
+func (a *App) updateCaptureButton() {
+	buttonText := "Capture"
+	if a.active.capturePath != "" {
+		buttonText = "Stop Capture"
+	}
+	a.operationForm.GetButton(5).SetLabel(buttonText)
+}
+
 func (a *App) showConfigDialog() {
 	// Create form for telecontrol
 	form := tview.NewForm()
@@ -516,6 +463,9 @@ func (a *App) showConfigDialog() {
 		fmt.Sscanf(text, "%d", &ii)
 		a.config.InterrogationInterval = ii
 	})
+	form.AddCheckbox("Alarm Bell", a.config.AlarmBell, func(checked bool) {
+		a.config.AlarmBell = checked
+	})
 
 	// Add buttons
 	form.AddButton("Save", func() {
@@ -535,186 +485,7 @@ func (a *App) showConfigDialog() {
 			AddItem(nil, 0, 1, false).
 			AddItem(form, 60, 1, true).
 			AddItem(nil, 0, 1, false),
-			20, 1, true).
-		AddItem(nil, 0, 1, false)
-
-	// Add the page and show it
-	a.pages.AddPage("dialog", modal, true, true)
-}
-
-// showTelecontrolDialog shows a dialog for sending telecontrol commands
-func (a *App) showTelecontrolDialog(row, col int) {
-	index := (row-1)*10 + col - 1
-	// Create form for telecontrol
-	form := tview.NewForm()
-	form.SetBorder(true).SetTitle("Send Telecontrol Command")
-
-	// Add address field (read-only)
-	form.AddInputField("Offset", fmt.Sprintf("%d", index), 10, nil, nil).
-		SetFieldBackgroundColor(tcell.ColorDarkGray)
-
-	// Add value field
-	value := false
-	form.AddCheckbox("Value", value, func(checked bool) {
-		value = checked
-	})
-
-	// Add buttons
-	form.AddButton("Send", func() {
-		err := a.iecClient.SendTelecontrol(index, value)
-		if err != nil {
-			a.logger.Infof("Error sending telecontrol: %v", err)
-		} else {
-			a.logger.Infof("Telecontrol command sent to address %d, value: %v", index, value)
-
-			v := "OFF"
-			if value {
-				v = "ON"
-			}
-			a.dataTable.SetCell(row, col, tview.NewTableCell(v))
-
-			a.iecClient.Telecontrol[index] = iec_client.TelecontrolPoint{
-				Value: value,
-			}
-		}
-		a.pages.RemovePage("dialog")
-	})
-	form.AddButton("Cancel", func() {
-		a.pages.RemovePage("dialog")
-	})
-
-	// Create a modal for the form
-	modal := tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(nil, 0, 1, false).
-		AddItem(tview.NewFlex().
-			SetDirection(tview.FlexColumn).
-			AddItem(nil, 0, 1, false).
-			AddItem(form, 40, 1, true).
-			AddItem(nil, 0, 1, false),
-			10, 1, true).
-		AddItem(nil, 0, 1, false)
-
-	// Add the page and show it
-	a.pages.AddPage("dialog", modal, true, true)
-}
-
-// showTeleregulationDialog shows a dialog for sending teleregulation setpoints
-func (a *App) showTeleregulationDialog(row, col int) {
-	index := (row-1)*10 + col - 1
-	// Create form for teleregulation
-	form := tview.NewForm()
-	form.SetBorder(true).SetTitle("Send Teleregulation Setpoint")
-
-	// Add address field (read-only)
-	form.AddInputField("Offset", fmt.Sprintf("%d", index), 10, nil, nil).
-		SetFieldBackgroundColor(tcell.ColorDarkGray)
-
-	// Add value field
-	valueStr := "0.0"
-	form.AddInputField("Value", valueStr, 10, nil, func(text string) {
-		valueStr = text
-	})
-
-	// Add buttons
-	form.AddButton("Send", func() {
-		var value float64
-		fmt.Sscanf(valueStr, "%f", &value)
-		err := a.iecClient.SendTelemetry(index, value)
-		if err != nil {
-			a.logger.Infof("Error sending teleregulation: %v", err)
-		} else {
-			a.logger.Infof("Teleregulation setpoint sent to address %d, value: %v", index, value)
-			a.dataTable.SetCell(row, col, tview.NewTableCell(fmt.Sprintf("%.2f", value)))
-
-			a.iecClient.Teleregulation[index] = iec_client.TeleregulationPoint{
-				Value: value,
-			}
-		}
-		a.pages.RemovePage("dialog")
-	})
-	form.AddButton("Cancel", func() {
-		a.pages.RemovePage("dialog")
-	})
-
-	// Create a modal for the form
-	modal := tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(nil, 0, 1, false).
-		AddItem(tview.NewFlex().
-			SetDirection(tview.FlexColumn).
-			AddItem(nil, 0, 1, false).
-			AddItem(form, 40, 1, true).
-			AddItem(nil, 0, 1, false),
-			10, 1, true).
-		AddItem(nil, 0, 1, false)
-
-	// Add the page and show it
-	a.pages.AddPage("dialog", modal, true, true)
-}
-
-// showDescriptionDialog shows a dialog for editing point descriptions
-func (a *App) showDescriptionDialog(row, col int) {
-	index := (row-1)/2*10 + col - 1
-
-	var currentDesc string
-	switch a.currentTab {
-	case iec_client.Telemetry:
-		currentDesc = a.config.TelemetryDescriptions[index]
-	case iec_client.Teleindication:
-		currentDesc = a.config.TeleindDescriptions[index]
-	}
-
-	// Create form for description
-	form := tview.NewForm()
-	form.SetBorder(true).SetTitle("Edit Point Description")
-
-	// Add address field (read-only)
-	form.AddInputField("Offset", fmt.Sprintf("%d", index), 10, nil, nil).
-		SetFieldBackgroundColor(tcell.ColorDarkGray)
-
-	// Add description field
-	form.AddInputField("Description", currentDesc, 40, nil, func(text string) {
-		currentDesc = text
-	})
-
-	// Add buttons
-	form.AddButton("Save", func() {
-		// 保存描述
-		switch a.currentTab {
-		case iec_client.Telemetry:
-			a.config.TelemetryDescriptions[index] = currentDesc
-		case iec_client.Teleindication:
-			a.config.TeleindDescriptions[index] = currentDesc
-		}
-
-		// 保存配置
-		if err := a.config.Save(); err != nil {
-			a.logger.Errorf("Error saving description: %v", err)
-		} else {
-			a.logger.Infof("Description saved for offset %d", index)
-			if row-1 < 1 {
-				a.logger.Errorf("Invalid offset %d", index)
-				return
-			}
-			a.dataTable.SetCell(row-1, col, tview.NewTableCell(currentDesc).SetTextColor(tcell.ColorGreen).SetSelectable(false))
-		}
-		a.pages.RemovePage("dialog")
-	})
-	form.AddButton("Cancel", func() {
-		a.pages.RemovePage("dialog")
-	})
-
-	// Create a modal for the form
-	modal := tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(nil, 0, 1, false).
-		AddItem(tview.NewFlex().
-			SetDirection(tview.FlexColumn).
-			AddItem(nil, 0, 1, false).
-			AddItem(form, 40, 1, true).
-			AddItem(nil, 0, 1, false),
-			10, 1, true).
+			22, 1, true).
 		AddItem(nil, 0, 1, false)
 
 	// Add the page and show it
@@ -736,6 +507,14 @@ func getTabHighlight(active bool) string {
 	return "[white:black]"
 }
 
+// alarmBadge shows the number of unacknowledged alarms in the tab bar
+func alarmBadge(unacknowledged int) string {
+	if unacknowledged == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [red]%d[-]", unacknowledged)
+}
+
 // getTabName returns the name of a tab
 func getTabName(tab iec_client.DataType) string {
 	switch tab {
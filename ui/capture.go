@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"iec104/capture"
+	"strings"
+	"time"
+)
+
+// toggleCapture starts or stops recording the workspace's frames to a pcap
+// file named after the profile
+func (w *workspace) toggleCapture() {
+	if w.capturePath != "" {
+		w.stopCapture()
+	} else {
+		name := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`/\:*?"<>| `, r) {
+				return '_'
+			}
+			return r
+		}, w.profile.Name)
+		path := fmt.Sprintf("%s-%s.pcap", name, time.Now().Format("20060102-150405"))
+		cw, err := capture.Create(path, capture.ServerAddr(w.profile.IPAddress, w.profile.Port))
+		if err != nil {
+			w.logger.Errorf("Error starting capture: %v", err)
+			return
+		}
+		w.capture, w.capturePath = cw, path
+		w.client.SetRecorder(cw)
+		w.logger.Infof("Capturing frames to %s", path)
+	}
+
+	if w.ui.active == w {
+		w.ui.updateCaptureButton()
+		w.ui.updateStatusBar()
+	}
+}
+
+// stopCapture stops recording and closes the capture file
+func (w *workspace) stopCapture() {
+	if w.capturePath == "" {
+		return
+	}
+	w.client.SetRecorder(nil)
+	if err := w.capture.Close(); err != nil {
+		w.logger.Errorf("Error closing capture %s: %v", w.capturePath, err)
+	} else {
+		w.logger.Infof("Capture saved to %s", w.capturePath)
+	}
+	w.capture, w.capturePath = nil, ""
+}
+
+// replay reads a capture file and feeds its frames through the client in
+// the background, so the views show the recorded points, events and frames
+func (w *workspace) replay(path string) {
+	conversations, err := capture.ReadFile(path, uint16(w.profile.Port))
+	if err != nil {
+		w.logger.Errorf("Error reading capture: %v", err)
+		return
+	}
+	frames := capture.Frames(conversations)
+	w.logger.Infof("Replaying %d frames of %d connection(s) from %s", len(frames), len(conversations), path)
+
+	w.replaying.Store(true)
+	go func() {
+		err := w.client.Replay(context.Background(), frames, 0)
+		w.replaying.Store(false)
+		w.ui.app.QueueUpdateDraw(func() {
+			if err != nil {
+				w.logger.Errorf("Error replaying capture: %v", err)
+			} else {
+				w.logger.Infof("Replay of %s finished", path)
+			}
+			if w.ui.active == w {
+				w.ui.updateStatusBar()
+			}
+			w.refreshData()
+		})
+	}()
+}
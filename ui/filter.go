@@ -0,0 +1,372 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/thinkgos/go-iecp5/asdu"
+	"iec104/iec_client"
+)
+
+// filterHelp is shown in the empty filter bar
+const filterHelp = "name, ioa:100-200, value:>10, value:1..5, q:bad, q:iv, changed:30s"
+
+// pointFilter selects points by name, address, value, quality and age.
+// The zero value matches everything.
+type pointFilter struct {
+	terms    []string
+	minIOA   int
+	maxIOA   int
+	minValue *float64
+	maxValue *float64
+	quality  string
+	changed  time.Duration
+}
+
+// parsePointFilter parses space separated filter terms. Terms with a
+// prefix select by field, bare words must occur in the point name or
+// equal its address.
+func parsePointFilter(s string) (pointFilter, error) {
+	var f pointFilter
+	for _, term := range strings.Fields(s) {
+		key, arg, ok := strings.Cut(term, ":")
+		if !ok {
+			f.terms = append(f.terms, strings.ToLower(term))
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "ioa":
+			lo, hi, _ := strings.Cut(arg, "-")
+			min, err := strconv.Atoi(lo)
+			if err != nil {
+				return f, fmt.Errorf("invalid ioa %q", arg)
+			}
+			max := min
+			if hi != "" {
+				if max, err = strconv.Atoi(hi); err != nil {
+					return f, fmt.Errorf("invalid ioa %q", arg)
+				}
+			}
+			f.minIOA, f.maxIOA = min, max
+		case "value", "v":
+			if err := f.parseValueRange(arg); err != nil {
+				return f, err
+			}
+		case "q", "quality":
+			q := strings.ToLower(arg)
+			switch q {
+			case "ok", "bad", "iv", "nt", "sb", "bl", "ov":
+				f.quality = q
+			default:
+				return f, fmt.Errorf("invalid quality %q", arg)
+			}
+		case "changed":
+			d, err := time.ParseDuration(arg)
+			if err != nil {
+				seconds, serr := strconv.Atoi(arg)
+				if serr != nil {
+					return f, fmt.Errorf("invalid duration %q", arg)
+				}
+				d = time.Duration(seconds) * time.Second
+			}
+			f.changed = d
+		default:
+			return f, fmt.Errorf("unknown filter %q", key)
+		}
+	}
+	return f, nil
+}
+
+// parseValueRange parses ">x", "<x", "a..b" or an exact value
+func (f *pointFilter) parseValueRange(arg string) error {
+	parse := func(s string) (*float64, error) {
+		if s == "" {
+			return nil, nil
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", arg)
+		}
+		return &v, nil
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(arg, ">="), strings.HasPrefix(arg, ">"):
+		f.minValue, err = parse(strings.TrimLeft(arg, ">="))
+	case strings.HasPrefix(arg, "<="), strings.HasPrefix(arg, "<"):
+		f.maxValue, err = parse(strings.TrimLeft(arg, "<="))
+	case strings.Contains(arg, ".."):
+		lo, hi, _ := strings.Cut(arg, "..")
+		if f.minValue, err = parse(lo); err == nil {
+			f.maxValue, err = parse(hi)
+		}
+	default:
+		f.minValue, err = parse(arg)
+		f.maxValue = f.minValue
+	}
+	return err
+}
+
+// empty reports whether the filter matches everything
+func (f pointFilter) empty() bool {
+	return len(f.terms) == 0 && f.minIOA == 0 && f.maxIOA == 0 &&
+		f.minValue == nil && f.maxValue == nil && f.quality == "" && f.changed == 0
+}
+
+// pointInfo is what a filter looks at for one point
+type pointInfo struct {
+	ioa      int
+	name     string
+	value    float64
+	hasValue bool
+	quality  asdu.QualityDescriptor
+	received time.Time
+}
+
+// match reports whether a point passes the filter
+func (f pointFilter) match(p pointInfo, now time.Time) bool {
+	name := strings.ToLower(p.name)
+	for _, term := range f.terms {
+		if !strings.Contains(name, term) && term != strconv.Itoa(p.ioa) {
+			return false
+		}
+	}
+	if (f.minIOA != 0 || f.maxIOA != 0) && (p.ioa < f.minIOA || p.ioa > f.maxIOA) {
+		return false
+	}
+	if f.minValue != nil || f.maxValue != nil {
+		if !p.hasValue ||
+			(f.minValue != nil && p.value < *f.minValue) ||
+			(f.maxValue != nil && p.value > *f.maxValue) {
+			return false
+		}
+	}
+	if f.quality != "" {
+		if p.received.IsZero() || !matchQuality(f.quality, p.quality) {
+			return false
+		}
+	}
+	if f.changed > 0 && (p.received.IsZero() || now.Sub(p.received) > f.changed) {
+		return false
+	}
+	return true
+}
+
+func matchQuality(want string, q asdu.QualityDescriptor) bool {
+	switch want {
+	case "ok":
+		return q == asdu.QDSGood
+	case "bad":
+		return q != asdu.QDSGood
+	case "iv":
+		return q&asdu.QDSInvalid != 0
+	case "nt":
+		return q&asdu.QDSNotTopical != 0
+	case "sb":
+		return q&asdu.QDSSubstituted != 0
+	case "bl":
+		return q&asdu.QDSBlocked != 0
+	default:
+		return q&asdu.QDSOverflow != 0
+	}
+}
+
+// pointInfo collects the filterable properties of a point of the current tab
+func (w *workspace) pointInfo(ioa int) pointInfo {
+	pointType := w.currentTab.PointType()
+	info := pointInfo{ioa: ioa, name: w.profile.PointName(pointType, ioa)}
+	if u, ok := w.client.Point(w.currentTab, ioa); ok {
+		info.value, info.hasValue = u.Value, true
+		info.quality = u.Quality
+		info.received = u.Received
+	}
+	return info
+}
+
+// matches reports whether a point of the current tab passes the filter
+func (w *workspace) matches(ioa int) bool {
+	if w.filter.empty() {
+		return true
+	}
+	return w.filter.match(w.pointInfo(ioa), time.Now())
+}
+
+// setupFilterBar creates the search and filter input above the data views
+func (w *workspace) setupFilterBar() {
+	w.filterBar = tview.NewInputField().
+		SetLabel("Filter (/): ").
+		SetPlaceholder(filterHelp).
+		SetPlaceholderTextColor(tcell.ColorGray).
+		SetFieldBackgroundColor(tcell.ColorBlack)
+
+	w.filterBar.SetChangedFunc(func(text string) {
+		f, err := parsePointFilter(text)
+		if err != nil {
+			w.filterBar.SetLabelColor(tcell.ColorRed)
+			return
+		}
+		w.filterBar.SetLabelColor(tcell.ColorYellow)
+		w.filter = f
+		w.refreshData()
+	})
+	w.filterBar.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			w.nextMatch(1)
+		case tcell.KeyEscape:
+			w.filterBar.SetText("")
+		}
+		w.ui.app.SetFocus(w.focusTarget())
+	})
+	w.filterBar.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyDown:
+			w.nextMatch(1)
+			return nil
+		case tcell.KeyUp:
+			w.nextMatch(-1)
+			return nil
+		}
+		return event
+	})
+}
+
+// handleViewKey handles the search and trend keys of the grid and list tables
+func (w *workspace) handleViewKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Rune() {
+	case '/':
+		w.ui.app.SetFocus(w.filterBar)
+		return nil
+	case 'n':
+		w.nextMatch(1)
+		return nil
+	case 'N':
+		w.nextMatch(-1)
+		return nil
+	case 't':
+		if ioa, ok := w.selectedAddress(); ok && w.currentTab == iec_client.Telemetry {
+			w.toggleTrendPoint(ioa)
+		}
+		return nil
+	case 'w':
+		w.trend.cycleWindow()
+		return nil
+	}
+	return event
+}
+
+// gridAddresses returns the addresses shown in the grid, in grid order
+func (w *workspace) gridAddresses() []int {
+	pointType := w.currentTab.PointType()
+	count := 100
+	switch w.currentTab {
+	case iec_client.Telemetry:
+		count = w.profile.TelemetryCount
+	case iec_client.Teleindication:
+		count = w.profile.TeleindCount
+	}
+	addresses := make([]int, count)
+	for i := range addresses {
+		addresses[i] = pointType.BaseAddress() + i
+	}
+	return addresses
+}
+
+// gridCell returns the grid position of the value cell of an offset
+func (w *workspace) gridCell(index int) (int, int) {
+	switch w.currentTab {
+	case iec_client.Telemetry, iec_client.Teleindication:
+		return (index/10 + 1) * 2, index%10 + 1
+	default:
+		return index/10 + 1, index%10 + 1
+	}
+}
+
+// nextMatch moves the selection to the next or previous matching point
+func (w *workspace) nextMatch(step int) {
+	if w.listMode {
+		// The list only contains matches, so step through its rows
+		if len(w.list.rows) == 0 {
+			return
+		}
+		row, _ := w.listTable.GetSelection()
+		row += step
+		if row < 1 {
+			row = len(w.list.rows)
+		} else if row > len(w.list.rows) {
+			row = 1
+		}
+		w.listTable.Select(row, 0)
+		return
+	}
+
+	addresses := w.gridAddresses()
+	if len(addresses) == 0 {
+		return
+	}
+	base := w.currentTab.PointType().BaseAddress()
+	current := -1
+	if row, col := w.dataTable.GetSelection(); row > 0 && col > 0 {
+		switch w.currentTab {
+		case iec_client.Telemetry, iec_client.Teleindication:
+			current = (row-1)/2*10 + col - 1
+		default:
+			current = (row-1)*10 + col - 1
+		}
+	}
+	if current < 0 && step < 0 {
+		current = len(addresses)
+	}
+
+	for i := 1; i <= len(addresses); i++ {
+		index := ((current+step*i)%len(addresses) + len(addresses)) % len(addresses)
+		if w.matches(base + index) {
+			row, col := w.gridCell(index)
+			w.dataTable.SetSelectable(true, true)
+			w.dataTable.Select(row, col)
+			return
+		}
+	}
+	w.logger.Infof("No matching points")
+}
+
+// highlightMatch marks a grid value cell when a filter is set and the point matches
+func (w *workspace) highlightMatch(cell *tview.TableCell, ioa int) *tview.TableCell {
+	if !w.filter.empty() && w.matches(ioa) {
+		cell.SetBackgroundColor(tcell.ColorDarkBlue)
+	}
+	return cell
+}
+
+// refreshActive periodically refreshes the time dependent parts of the
+// active workspace: filters on recent changes, the event list and the trend
+// chart, and the alarms of all workspaces
+func (a *App) refreshActive() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.app.QueueUpdateDraw(func() {
+				if a.active != nil && a.active.filter.changed > 0 {
+					a.active.refreshData()
+				}
+				if a.active != nil {
+					a.active.refreshEvents()
+					a.active.refreshMonitor()
+					a.active.refreshStats()
+				}
+				a.refreshAlarms()
+				// Redrawing is enough for the trend, it reads the history itself
+			})
+		case <-a.closer:
+			return
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"iec104/iec_client"
+)
+
+// listHeaders are the columns of the list view
+var listHeaders = []string{"IOA", "Name", "Value", "Quality", "Timestamp", "Received"}
+
+// pointList is the content of the list view: one row per configured or
+// received point of the current tab. Cells are rendered on demand, so only
+// the visible rows cost anything no matter how many points there are.
+type pointList struct {
+	tview.TableContentReadOnly
+
+	w     *workspace
+	rows  []int
+	index map[int]struct{}
+}
+
+// GetRowCount returns the number of points plus the header row
+func (l *pointList) GetRowCount() int {
+	return len(l.rows) + 1
+}
+
+// GetColumnCount returns the number of columns
+func (l *pointList) GetColumnCount() int {
+	return len(listHeaders)
+}
+
+// GetCell renders a single cell from the latest client data
+func (l *pointList) GetCell(row, column int) *tview.TableCell {
+	if column < 0 || column >= len(listHeaders) {
+		return nil
+	}
+	if row == 0 {
+		return tview.NewTableCell(listHeaders[column]).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false).
+			SetExpansion(1)
+	}
+	if row > len(l.rows) {
+		return nil
+	}
+
+	w := l.w
+	ioa := l.rows[row-1]
+	pointType := w.currentTab.PointType()
+	update, received := w.client.Point(w.currentTab, ioa)
+
+	switch column {
+	case 0:
+		return tview.NewTableCell(fmt.Sprintf("%d", ioa))
+	case 1:
+		return tview.NewTableCell(w.profile.PointName(pointType, ioa)).SetTextColor(tcell.ColorGreen)
+	case 2:
+		return l.valueCell(ioa, update, received)
+	case 3:
+		if !received {
+			return tview.NewTableCell("-")
+		}
+		cell := tview.NewTableCell(iec_client.QualityString(update.Quality))
+		if update.Quality != 0 {
+			cell.SetTextColor(tcell.ColorRed)
+		}
+		return cell
+	case 4:
+		return tview.NewTableCell(formatListTime(update.Timestamp))
+	default:
+		return tview.NewTableCell(formatListTime(update.Received))
+	}
+}
+
+// valueCell renders the value column for the current tab
+func (l *pointList) valueCell(ioa int, update iec_client.Update, received bool) *tview.TableCell {
+	w := l.w
+	pointType := w.currentTab.PointType()
+
+	switch w.currentTab {
+	case iec_client.Telemetry:
+		if received {
+			return w.valueCell(pointType, ioa, update.Value)
+		}
+	case iec_client.Teleindication:
+		if received && update.Double {
+			return w.doubleStateCell(ioa, update.DoubleState)
+		} else if received {
+			return w.stateCell(pointType, ioa, update.State)
+		}
+	case iec_client.Telecontrol:
+		if received {
+			return w.stateCell(pointType, ioa, update.State)
+		}
+	case iec_client.Teleregulation:
+		if received {
+			return w.valueCell(pointType, ioa, update.Value)
+		}
+	}
+	return tview.NewTableCell("-")
+}
+
+// reload rebuilds the row index for the current tab
+func (l *pointList) reload() {
+	w := l.w
+	l.rows = l.rows[:0]
+	l.index = make(map[int]struct{})
+
+	pointType := w.currentTab.PointType()
+	for _, point := range w.profile.PointsOf(pointType) {
+		l.add(point.Address)
+	}
+	for _, u := range w.client.Points(w.currentTab) {
+		l.add(u.Address)
+	}
+	sort.Ints(l.rows)
+}
+
+// add appends an address to the rows if it is not listed yet and
+// passes the workspace filter
+func (l *pointList) add(ioa int) bool {
+	if _, ok := l.index[ioa]; ok {
+		return false
+	}
+	if !l.w.matches(ioa) {
+		return false
+	}
+	l.index[ioa] = struct{}{}
+	l.rows = append(l.rows, ioa)
+	return true
+}
+
+// ensure inserts a newly received address at its sorted position
+func (l *pointList) ensure(ioa int) {
+	if !l.add(ioa) {
+		return
+	}
+	sort.Ints(l.rows)
+}
+
+// formatListTime formats a timestamp for the list view, or "-" if unset
+func formatListTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05.000")
+}
+
+// setupListView creates the virtual list table
+func (w *workspace) setupListView() {
+	w.list = &pointList{w: w, index: make(map[int]struct{})}
+	w.listTable = tview.NewTable().
+		SetContent(w.list).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	w.listTable.SetBorder(true)
+	w.listTable.SetInputCapture(w.handleViewKey)
+
+	w.listTable.SetSelectedFunc(func(row, _ int) {
+		if row < 1 || row > len(w.list.rows) {
+			return
+		}
+		w.showPointDialog(w.list.rows[row-1] - w.currentTab.PointType().BaseAddress())
+	})
+	w.listTable.SetSelectionChangedFunc(func(row, _ int) {
+		w.updateListTitle()
+	})
+}
+
+// updateListTitle shows the point count and current page in the list border
+func (w *workspace) updateListTitle() {
+	row, _ := w.listTable.GetSelection()
+	_, _, _, height := w.listTable.GetInnerRect()
+	pageSize := height - 1
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	pages := (len(w.list.rows) + pageSize - 1) / pageSize
+	if pages < 1 {
+		pages = 1
+	}
+	page := 1
+	if row > 0 {
+		page = (row-1)/pageSize + 1
+	}
+	w.listTable.SetTitle(fmt.Sprintf("%s: %d points, page %d/%d (PgUp/PgDn)",
+		getTabName(w.currentTab), len(w.list.rows), page, pages))
+}
+
+// toggleListView switches the workspace between the grid and the list view
+func (w *workspace) toggleListView() {
+	w.listMode = !w.listMode
+	w.eventsMode = false
+	w.alarmsMode = false
+	w.monitorMode = false
+	w.statsMode = false
+	w.refreshData()
+	w.showView()
+}
+
+// focusTarget returns the table of the current view
+func (w *workspace) focusTarget() tview.Primitive {
+	if w.eventsMode {
+		return w.eventsTable
+	}
+	if w.alarmsMode {
+		return w.alarmsTable
+	}
+	if w.monitorMode {
+		return w.framesTable
+	}
+	if w.statsMode {
+		return w.statsTable
+	}
+	if w.listMode {
+		return w.listTable
+	}
+	return w.dataTable
+}
+
+// refreshData redraws the current view from the profile and client data
+func (w *workspace) refreshData() {
+	if w.listMode {
+		w.list.reload()
+		w.updateListTitle()
+		return
+	}
+	w.updateTableData()
+}
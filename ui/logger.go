@@ -79,3 +79,24 @@ func (l *Logger) Clear() {
 
 	l.textView.SetText("")
 }
+
+// workspaceLogger prefixes log entries with the workspace's profile name
+type workspaceLogger struct {
+	logger *Logger
+	ws     *workspace
+}
+
+// Infof adds a log entry for the workspace
+func (l *workspaceLogger) Infof(format string, args ...interface{}) {
+	l.logger.Infof("%s: %s", l.ws.profile.Name, fmt.Sprintf(format, args...))
+}
+
+// Debugf adds a debug log entry for the workspace
+func (l *workspaceLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Debugf("%s: %s", l.ws.profile.Name, fmt.Sprintf(format, args...))
+}
+
+// Errorf adds an error log entry for the workspace
+func (l *workspaceLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Errorf("%s: %s", l.ws.profile.Name, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/thinkgos/go-iecp5/asdu"
+	"iec104/iec_client"
+)
+
+// monitorHeaders are the columns of the protocol monitor
+var monitorHeaders = []string{"Time", "Dir", "Frame", "N(S)", "N(R)", "Type", "COT", "CA", "Objects"}
+
+// monitorFormats are the frame format filters, in cycle order
+var monitorFormats = []string{"All", "I", "S", "U"}
+
+// frameList is the virtual content of the protocol monitor, oldest frame first
+type frameList struct {
+	tview.TableContentReadOnly
+
+	w      *workspace
+	frames []iec_client.Frame
+	count  uint64
+	paused bool
+	// format is the index into monitorFormats; typ, if set, only shows
+	// I frames of that ASDU type
+	format int
+	typ    asdu.TypeID
+}
+
+// GetRowCount returns the number of frames plus the header row
+func (l *frameList) GetRowCount() int {
+	return len(l.frames) + 1
+}
+
+// GetColumnCount returns the number of columns
+func (l *frameList) GetColumnCount() int {
+	return len(monitorHeaders)
+}
+
+// GetCell renders a single cell of a frame. Received frames are white,
+// sent ones cyan, S and U frames gray and negative confirmations red.
+func (l *frameList) GetCell(row, column int) *tview.TableCell {
+	if column < 0 || column >= len(monitorHeaders) {
+		return nil
+	}
+	if row == 0 {
+		return tview.NewTableCell(monitorHeaders[column]).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false)
+	}
+	if row > len(l.frames) {
+		return nil
+	}
+
+	f := l.frames[row-1]
+	var text string
+	switch column {
+	case 0:
+		text = f.Time.Format("15:04:05.000")
+	case 1:
+		text = f.Direction.String()
+	case 2:
+		text = f.Format.String()
+	case 3:
+		if f.Format == iec_client.FrameI {
+			text = strconv.Itoa(int(f.SendSeq))
+		}
+	case 4:
+		if f.Format != iec_client.FrameU {
+			text = strconv.Itoa(int(f.RecvSeq))
+		}
+	case 5:
+		switch {
+		case f.Format == iec_client.FrameU:
+			text = f.Function
+		case f.Format == iec_client.FrameI && f.Error == "":
+			text = f.Identifier.Type.String()
+		}
+	case 6:
+		if f.Format == iec_client.FrameI && f.Error == "" {
+			text = iec_client.CauseString(f.Identifier.Coa.Cause)
+			if f.Identifier.Coa.IsNegative {
+				text += " neg"
+			}
+		}
+	case 7:
+		if f.Format == iec_client.FrameI && f.Error == "" {
+			text = strconv.Itoa(int(f.Identifier.CommonAddr))
+		}
+	default:
+		text = objectsText(f)
+	}
+
+	cell := tview.NewTableCell(text).SetTextColor(frameColor(f))
+	if column == len(monitorHeaders)-1 {
+		cell.SetExpansion(1)
+	}
+	return cell
+}
+
+// objectsText lists the objects of an I frame, or its decoding error
+func objectsText(f iec_client.Frame) string {
+	if f.Error != "" {
+		return f.Error
+	}
+	parts := make([]string, len(f.Objects))
+	for i, o := range f.Objects {
+		parts[i] = fmt.Sprintf("%d=%s", o.Address, o.Text)
+		if o.Quality != asdu.QDSGood {
+			parts[i] += " " + iec_client.QualityString(o.Quality)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func frameColor(f iec_client.Frame) tcell.Color {
+	switch {
+	case f.Error != "" || f.Format == iec_client.FrameI && f.Identifier.Coa.IsNegative:
+		return tcell.ColorRed
+	case f.Format != iec_client.FrameI:
+		return tcell.ColorGray
+	case f.Direction == iec_client.FrameSent:
+		return tcell.ColorDarkCyan
+	default:
+		return tcell.ColorWhite
+	}
+}
+
+// reload fetches the frames from the client unless paused, or always when
+// force is set, applies the filters and reports whether it reloaded
+func (l *frameList) reload(force bool) bool {
+	count := l.w.client.FrameCount()
+	if !force && (l.paused || count == l.count) {
+		return false
+	}
+	l.count = count
+
+	frames := l.w.client.Frames()
+	l.frames = frames[:0]
+	for _, f := range frames {
+		if l.matches(f) {
+			l.frames = append(l.frames, f)
+		}
+	}
+	return true
+}
+
+// matches reports whether a frame passes the format and type filters
+func (l *frameList) matches(f iec_client.Frame) bool {
+	if l.format > 0 && f.Format.String() != monitorFormats[l.format] {
+		return false
+	}
+	return l.typ == 0 || f.Format == iec_client.FrameI && f.Identifier.Type == l.typ
+}
+
+// frameDetail shows the hex dump and decoded content of a frame
+func frameDetail(f iec_client.Frame) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]%s %s %s-frame, %d bytes[white]\n", f.Time.Format("2006-01-02 15:04:05.000"), f.Direction, f.Format, len(f.Raw))
+	switch f.Format {
+	case iec_client.FrameI:
+		fmt.Fprintf(&b, "APCI: N(S)=%d N(R)=%d\n", f.SendSeq, f.RecvSeq)
+	case iec_client.FrameS:
+		fmt.Fprintf(&b, "APCI: N(R)=%d\n", f.RecvSeq)
+	default:
+		fmt.Fprintf(&b, "APCI: %s\n", f.Function)
+	}
+	if f.Format == iec_client.FrameI {
+		if f.Error != "" {
+			fmt.Fprintf(&b, "[red]ASDU error: %s[white]\n", f.Error)
+		} else {
+			id := f.Identifier
+			fmt.Fprintf(&b, "ASDU: %s, SQ=%t, %d objects, COT %s%s%s, OA %d, CA %d\n",
+				id.Type, id.Variable.IsSequence, id.Variable.Number, iec_client.CauseString(id.Coa.Cause),
+				flagText(id.Coa.IsNegative, " negative"), flagText(id.Coa.IsTest, " test"), id.OrigAddr, id.CommonAddr)
+			for _, o := range f.Objects {
+				fmt.Fprintf(&b, "  IOA %d: %s, quality %s", o.Address, o.Text, iec_client.QualityString(o.Quality))
+				if !o.Time.IsZero() {
+					fmt.Fprintf(&b, ", time %s", o.Time.Format("2006-01-02 15:04:05.000"))
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+	b.WriteString(tview.Escape(hex.Dump(f.Raw)))
+	return b.String()
+}
+
+func flagText(set bool, text string) string {
+	if set {
+		return text
+	}
+	return ""
+}
+
+// setupMonitorView creates the protocol monitor: the frame list above the
+// details of the selected frame
+func (w *workspace) setupMonitorView() {
+	w.frames = &frameList{w: w}
+	w.framesTable = tview.NewTable().
+		SetContent(w.frames).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	w.framesTable.SetBorder(true)
+	w.frameDetail = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	w.frameDetail.SetBorder(true).SetTitle("Frame")
+	w.monitor = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(w.framesTable, 0, 2, true).
+		AddItem(w.frameDetail, 0, 1, false)
+	w.updateMonitorTitle()
+
+	w.framesTable.SetSelectionChangedFunc(func(row, column int) {
+		w.showFrameDetail(row)
+	})
+	w.framesTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'p':
+			w.frames.paused = !w.frames.paused
+		case 'f':
+			w.frames.format = (w.frames.format + 1) % len(monitorFormats)
+		case 't':
+			if w.frames.typ != 0 {
+				w.frames.typ = 0
+				break
+			}
+			row, _ := w.framesTable.GetSelection()
+			if row >= 1 && row <= len(w.frames.frames) && w.frames.frames[row-1].Format == iec_client.FrameI {
+				w.frames.typ = w.frames.frames[row-1].Identifier.Type
+			}
+		case 'c':
+			w.client.ClearFrames()
+		default:
+			return event
+		}
+		w.reloadFrames(true)
+		return nil
+	})
+}
+
+// reloadFrames updates the frame list and keeps following the newest
+// frame while the last row is selected
+func (w *workspace) reloadFrames(force bool) {
+	row, _ := w.framesTable.GetSelection()
+	following := row >= len(w.frames.frames)
+	if !w.frames.reload(force) {
+		return
+	}
+	if following && len(w.frames.frames) > 0 {
+		w.framesTable.Select(len(w.frames.frames), 0)
+	}
+	w.updateMonitorTitle()
+	row, _ = w.framesTable.GetSelection()
+	w.showFrameDetail(row)
+}
+
+// showFrameDetail shows the frame of a row in the detail pane
+func (w *workspace) showFrameDetail(row int) {
+	if row < 1 || row > len(w.frames.frames) {
+		w.frameDetail.SetText("")
+		return
+	}
+	w.frameDetail.SetText(frameDetail(w.frames.frames[row-1])).ScrollToBeginning()
+}
+
+// updateMonitorTitle shows the frame count and filters in the border
+func (w *workspace) updateMonitorTitle() {
+	state := "live"
+	if w.frames.paused {
+		state = "paused"
+	}
+	typ := "all types"
+	if w.frames.typ != 0 {
+		typ = w.frames.typ.String()
+	}
+	w.framesTable.SetTitle(fmt.Sprintf("Monitor: %d frames, %s, %s frames, %s (p: pause, f: frame filter, t: type filter, c: clear)",
+		len(w.frames.frames), state, monitorFormats[w.frames.format], typ))
+}
+
+// refreshMonitor picks up new frames while the monitor is shown
+func (w *workspace) refreshMonitor() {
+	if !w.monitorMode {
+		return
+	}
+	w.reloadFrames(false)
+}
+
+// toggleMonitor switches between the protocol monitor and the data view
+func (w *workspace) toggleMonitor() {
+	w.monitorMode = !w.monitorMode
+	w.eventsMode = false
+	w.alarmsMode = false
+	w.statsMode = false
+	if w.monitorMode {
+		w.reloadFrames(true)
+	}
+	w.showView()
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"iec104/iec_client"
+)
+
+// setupOverview creates the overview table listing all workspaces
+func (a *App) setupOverview() {
+	a.overview = tview.NewTable().SetBorders(false).SetSelectable(true, false)
+	a.overview.SetBorder(true).SetTitle("Overview")
+	a.overview.SetFixed(1, 0)
+
+	a.overview.SetSelectedFunc(func(row, column int) {
+		if row < 1 || row > len(a.workspaces) {
+			return
+		}
+		a.activateWorkspace(a.workspaces[row-1])
+	})
+
+	a.dataPages.AddPage("overview", a.overview, true, false)
+}
+
+// toggleOverview switches between the overview and the active workspace
+func (a *App) toggleOverview() {
+	if a.showOverview {
+		a.activateWorkspace(a.active)
+		return
+	}
+
+	a.showOverview = true
+	a.updateOverview()
+	a.dataPages.SwitchToPage("overview")
+	a.app.SetFocus(a.overview)
+	a.updateWorkspaceBar()
+}
+
+// updateOverview fills the overview table with the state of every workspace
+func (a *App) updateOverview() {
+	a.overview.Clear()
+
+	headers := []string{"#", "Profile", "Server", "CA", "State", "Last Received", "Telemetry", "Teleindication", "Alarms"}
+	for col, h := range headers {
+		a.overview.SetCell(0, col, tview.NewTableCell(h).SetTextColor(tcell.ColorYellow).SetSelectable(false).SetExpansion(1))
+	}
+
+	now := time.Now()
+	for i, w := range a.workspaces {
+		row := i + 1
+
+		state, color := "Stopped", tcell.ColorGray
+		if w.client.Connected.Load() {
+			state, color = "Connected", tcell.ColorGreen
+		} else if w.started.Load() {
+			state, color = "Connecting", tcell.ColorRed
+		}
+
+		lastReceived := "-"
+		if t := w.client.LastReceived(); !t.IsZero() {
+			lastReceived = fmt.Sprintf("%s (%s ago)", t.Format("15:04:05"), now.Sub(t).Truncate(time.Second))
+		}
+
+		var telemetry, teleind int
+		for _, u := range w.client.Snapshot() {
+			if u.Type == iec_client.Telemetry {
+				telemetry++
+			} else {
+				teleind++
+			}
+		}
+
+		a.overview.SetCell(row, 0, tview.NewTableCell(fmt.Sprintf("%d", row)))
+		a.overview.SetCell(row, 1, tview.NewTableCell(w.profile.Name))
+		a.overview.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%s:%d", w.profile.IPAddress, w.profile.Port)))
+		a.overview.SetCell(row, 3, tview.NewTableCell(fmt.Sprintf("%d", w.profile.CommonAddress)))
+		a.overview.SetCell(row, 4, tview.NewTableCell(state).SetTextColor(color))
+		a.overview.SetCell(row, 5, tview.NewTableCell(lastReceived))
+		a.overview.SetCell(row, 6, tview.NewTableCell(fmt.Sprintf("%d", telemetry)))
+		a.overview.SetCell(row, 7, tview.NewTableCell(fmt.Sprintf("%d", teleind)))
+
+		alarms := tview.NewTableCell(fmt.Sprintf("%d", len(w.alarms.alarms)))
+		if n := w.alarms.unacknowledged(); n > 0 {
+			alarms.SetText(fmt.Sprintf("%d (%d unacked)", len(w.alarms.alarms), n)).SetTextColor(tcell.ColorRed)
+		}
+		a.overview.SetCell(row, 8, alarms)
+	}
+}
+
+// refreshOverview periodically redraws the overview while it is shown
+func (a *App) refreshOverview() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.app.QueueUpdateDraw(func() {
+				if a.showOverview {
+					a.updateOverview()
+				}
+			})
+		case <-a.closer:
+			return
+		}
+	}
+}
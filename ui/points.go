@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// showPointsDialog shows a dialog for importing and exporting the active
+// profile's point list as CSV
+func (a *App) showPointsDialog() {
+	profile := a.active.profile
+	path := profile.Name + "-points.csv"
+
+	report := tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
+	report.SetBorder(true).SetTitle("Report")
+
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle(fmt.Sprintf("Points (%s)", profile.Name))
+	form.AddInputField("File", path, 40, nil, func(text string) {
+		path = text
+	})
+
+	importPoints := func(merge bool) {
+		f, err := os.Open(path)
+		if err != nil {
+			a.logger.Errorf("Error opening %s: %v", path, err)
+			return
+		}
+		defer f.Close()
+
+		points, result, err := profile.ReadPointsCSV(f)
+		report.Clear()
+		if err != nil {
+			fmt.Fprintf(report, "[red]%v[white]\n", tview.Escape(err.Error()))
+			return
+		}
+		fmt.Fprint(report, tview.Escape(result.String()))
+		if result.HasErrors() {
+			a.logger.Errorf("Import of %s rejected, see report", path)
+			return
+		}
+
+		profile.ReplacePoints(points, merge)
+		a.saveProfiles()
+		a.active.client.Rescale()
+		a.active.refreshData()
+		a.logger.Infof("Imported %d points from %s", len(points), path)
+	}
+
+	form.AddButton("Import", func() {
+		importPoints(false)
+	})
+	form.AddButton("Merge", func() {
+		importPoints(true)
+	})
+	form.AddButton("Export", func() {
+		f, err := os.Create(path)
+		if err != nil {
+			a.logger.Errorf("Error creating %s: %v", path, err)
+			return
+		}
+		defer f.Close()
+		if err := profile.ExportPointsCSV(f); err != nil {
+			a.logger.Errorf("Error exporting points: %v", err)
+			return
+		}
+		report.Clear()
+		fmt.Fprintf(report, "%d points written to %s\n", len(profile.Points), tview.Escape(path))
+		a.logger.Infof("Exported %d points to %s", len(profile.Points), path)
+	})
+	form.AddButton("Close", func() {
+		a.pages.RemovePage("dialog")
+	})
+
+	content := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 7, 1, true).
+		AddItem(report, 0, 1, false)
+	content.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab && report.HasFocus() {
+			a.app.SetFocus(form)
+			return nil
+		}
+		return event
+	})
+
+	// Create a modal for the dialog
+	modal := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			SetDirection(tview.FlexColumn).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 80, 1, true).
+			AddItem(nil, 0, 1, false),
+			24, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	// Add the page and show it
+	a.pages.AddPage("dialog", modal, true, true)
+}
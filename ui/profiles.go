@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// showProfileDialog shows a dialog for picking and managing connection profiles
+func (a *App) showProfileDialog() {
+	list := tview.NewList().ShowSecondaryText(true)
+	list.SetBorder(true).SetTitle("Profiles")
+
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle("Profile")
+
+	selected := a.config.ActiveProfile
+	name := ""
+
+	var refresh func()
+	refresh = func() {
+		list.Clear()
+		current := 0
+		for i, p := range a.config.Profiles {
+			mainText := p.Name
+			if p.Name == a.config.ActiveProfile {
+				mainText = "* " + p.Name
+			}
+			list.AddItem(mainText, fmt.Sprintf("%s:%d CA %d", p.IPAddress, p.Port, p.CommonAddress), 0, nil)
+			if p.Name == selected {
+				current = i
+			}
+		}
+		list.SetCurrentItem(current)
+	}
+	list.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		if index >= 0 && index < len(a.config.Profiles) {
+			selected = a.config.Profiles[index].Name
+		}
+	})
+	list.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		a.openWorkspace(a.config.Profiles[index])
+		a.pages.RemovePage("dialog")
+	})
+
+	form.AddInputField("New Name", "", 30, nil, func(text string) {
+		name = text
+	})
+	form.AddButton("Open", func() {
+		a.openWorkspace(a.config.FindProfile(selected))
+		a.pages.RemovePage("dialog")
+	})
+	form.AddButton("Switch", func() {
+		a.switchProfile(selected)
+		a.pages.RemovePage("dialog")
+	})
+	form.AddButton("Clone", func() {
+		if err := a.config.CloneProfile(selected, name); err != nil {
+			a.logger.Errorf("Error cloning profile: %v", err)
+			return
+		}
+		a.logger.Infof("Profile %s cloned to %s", selected, name)
+		selected = name
+		a.saveProfiles()
+		refresh()
+	})
+	form.AddButton("Rename", func() {
+		if err := a.config.RenameProfile(selected, name); err != nil {
+			a.logger.Errorf("Error renaming profile: %v", err)
+			return
+		}
+		a.logger.Infof("Profile %s renamed to %s", selected, name)
+		selected = name
+		a.saveProfiles()
+		a.updateStatusBar()
+		a.updateWorkspaceBar()
+		refresh()
+	})
+	form.AddButton("Delete", func() {
+		if a.workspaceOpen(a.config.FindProfile(selected)) {
+			a.logger.Errorf("Profile %s is open in a workspace, close it first", selected)
+			return
+		}
+		if err := a.config.DeleteProfile(selected); err != nil {
+			a.logger.Errorf("Error deleting profile: %v", err)
+			return
+		}
+		a.logger.Infof("Profile %s deleted", selected)
+		selected = a.config.ActiveProfile
+		a.saveProfiles()
+		refresh()
+	})
+	form.AddButton("Close", func() {
+		a.pages.RemovePage("dialog")
+	})
+
+	refresh()
+
+	content := tview.NewFlex().
+		AddItem(list, 0, 1, true).
+		AddItem(form, 0, 1, false)
+	content.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab && list.HasFocus() {
+			a.app.SetFocus(form)
+			return nil
+		}
+		return event
+	})
+
+	// Create a modal for the dialog
+	modal := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			SetDirection(tview.FlexColumn).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 80, 1, true).
+			AddItem(nil, 0, 1, false),
+			20, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	// Add the page and show it
+	a.pages.AddPage("dialog", modal, true, true)
+}
+
+// switchProfile loads another profile into the active workspace,
+// reconnecting if a connection was running
+func (a *App) switchProfile(name string) {
+	profile := a.config.FindProfile(name)
+	if profile == nil || profile == a.active.profile {
+		return
+	}
+	if a.workspaceOpen(profile) {
+		a.logger.Errorf("Profile %s is already open in another workspace", name)
+		return
+	}
+
+	a.active.setProfile(profile)
+	a.activateWorkspace(a.active)
+	a.saveProfiles()
+	a.logger.Infof("Switched to profile %s", name)
+}
+
+// saveProfiles persists profile changes
+func (a *App) saveProfiles() {
+	if err := a.config.Save(); err != nil {
+		a.logger.Errorf("Error saving configuration: %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"iec104/iec_client"
+)
+
+// soeHeaders are the columns of the sequence-of-events view
+var soeHeaders = []string{"Device Time", "Received", "Type", "IOA", "Name", "Event", "COT", "Quality"}
+
+// soeSortKeys are the columns the events can be sorted by, in cycle order
+var soeSortKeys = []string{"Device Time", "Received", "IOA", "Name"}
+
+// soeList is the virtual content of the sequence-of-events view
+type soeList struct {
+	tview.TableContentReadOnly
+
+	w          *workspace
+	events     []iec_client.Event
+	count      uint64
+	sortBy     int
+	descending bool
+}
+
+// GetRowCount returns the number of events plus the header row
+func (l *soeList) GetRowCount() int {
+	return len(l.events) + 1
+}
+
+// GetColumnCount returns the number of columns
+func (l *soeList) GetColumnCount() int {
+	return len(soeHeaders)
+}
+
+// GetCell renders a single cell of an event
+func (l *soeList) GetCell(row, column int) *tview.TableCell {
+	if column < 0 || column >= len(soeHeaders) {
+		return nil
+	}
+	if row == 0 {
+		return tview.NewTableCell(soeHeaders[column]).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false).
+			SetExpansion(1)
+	}
+	if row > len(l.events) {
+		return nil
+	}
+
+	e := l.events[row-1]
+	switch column {
+	case 0:
+		return tview.NewTableCell(formatListTime(e.Timestamp))
+	case 1:
+		return tview.NewTableCell(formatListTime(e.Received))
+	case 2:
+		return tview.NewTableCell(e.Type.String())
+	case 3:
+		return tview.NewTableCell(strconv.Itoa(e.Address))
+	case 4:
+		return tview.NewTableCell(l.w.profile.PointName(e.Type.PointType(), e.Address)).SetTextColor(tcell.ColorGreen)
+	case 5:
+		cell := tview.NewTableCell(e.Message)
+		if e.Kind == iec_client.EventThreshold {
+			cell.SetTextColor(tcell.ColorOrange)
+		}
+		return cell
+	case 6:
+		return tview.NewTableCell(iec_client.CauseString(e.Cause))
+	default:
+		cell := tview.NewTableCell(iec_client.QualityString(e.Quality))
+		if e.Quality != 0 {
+			cell.SetTextColor(tcell.ColorRed)
+		}
+		return cell
+	}
+}
+
+// reload fetches the events from the client if there are new ones, or
+// always when force is set, and sorts them
+func (l *soeList) reload(force bool) {
+	count := l.w.client.EventCount()
+	if !force && count == l.count {
+		return
+	}
+	l.count = count
+	l.events = l.w.client.Events()
+	l.sort()
+}
+
+// sort orders the events by the selected key, keeping arrival order for ties
+func (l *soeList) sort() {
+	name := func(e iec_client.Event) string {
+		return l.w.profile.PointName(e.Type.PointType(), e.Address)
+	}
+	less := func(a, b iec_client.Event) bool {
+		switch soeSortKeys[l.sortBy] {
+		case "Received":
+			return a.Received.Before(b.Received)
+		case "IOA":
+			return a.Address < b.Address
+		case "Name":
+			return name(a) < name(b)
+		default:
+			return eventTime(a).Before(eventTime(b))
+		}
+	}
+	sort.SliceStable(l.events, func(i, j int) bool {
+		if l.descending {
+			return less(l.events[j], l.events[i])
+		}
+		return less(l.events[i], l.events[j])
+	})
+}
+
+// eventTime returns the device timestamp of an event, or the receive
+// time for values without a time tag
+func eventTime(e iec_client.Event) time.Time {
+	if e.Timestamp.IsZero() {
+		return e.Received
+	}
+	return e.Timestamp
+}
+
+// setupEventsView creates the sequence-of-events table
+func (w *workspace) setupEventsView() {
+	w.events = &soeList{w: w, descending: true}
+	w.eventsTable = tview.NewTable().
+		SetContent(w.events).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	w.eventsTable.SetBorder(true)
+	w.updateEventsTitle()
+
+	w.eventsTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 's':
+			w.events.sortBy = (w.events.sortBy + 1) % len(soeSortKeys)
+		case 'r':
+			w.events.descending = !w.events.descending
+		case 'e':
+			w.exportEvents()
+			return nil
+		case 'c':
+			w.client.ClearEvents()
+		default:
+			return event
+		}
+		w.events.reload(true)
+		w.updateEventsTitle()
+		return nil
+	})
+}
+
+// updateEventsTitle shows the event count and sort order in the border
+func (w *workspace) updateEventsTitle() {
+	order := "ascending"
+	if w.events.descending {
+		order = "descending"
+	}
+	w.eventsTable.SetTitle(fmt.Sprintf("Events: %d, by %s %s (s: sort, r: reverse, e: export, c: clear)",
+		len(w.events.events), soeSortKeys[w.events.sortBy], order))
+}
+
+// refreshEvents picks up new events while the view is shown
+func (w *workspace) refreshEvents() {
+	if !w.eventsMode {
+		return
+	}
+	w.events.reload(false)
+	w.updateEventsTitle()
+}
+
+// toggleEvents switches between the sequence-of-events view and the data view
+func (w *workspace) toggleEvents() {
+	w.eventsMode = !w.eventsMode
+	w.alarmsMode = false
+	w.monitorMode = false
+	w.statsMode = false
+	if w.eventsMode {
+		w.events.reload(true)
+		w.updateEventsTitle()
+	}
+	w.showView()
+}
+
+// showView shows the page of the current view mode and focuses it
+func (w *workspace) showView() {
+	switch {
+	case w.eventsMode:
+		w.view.SwitchToPage("events")
+	case w.alarmsMode:
+		w.view.SwitchToPage("alarms")
+	case w.monitorMode:
+		w.view.SwitchToPage("monitor")
+	case w.statsMode:
+		w.view.SwitchToPage("stats")
+	case w.listMode:
+		w.view.SwitchToPage("list")
+	default:
+		w.view.SwitchToPage("grid")
+	}
+	w.ui.app.SetFocus(w.focusTarget())
+	w.ui.updateTabBar()
+}
+
+// exportEvents writes the events as currently sorted to a CSV file
+func (w *workspace) exportEvents() {
+	path := fmt.Sprintf("%s-soe-%s.csv", w.profile.Name, time.Now().Format("20060102-150405"))
+	f, err := os.Create(path)
+	if err != nil {
+		w.logger.Errorf("Error exporting events: %v", err)
+		return
+	}
+	defer f.Close()
+
+	writer := csv.NewWriter(f)
+	_ = writer.Write([]string{"device_time", "received", "type", "ioa", "name", "kind", "event", "value", "cot", "quality"})
+	for _, e := range w.events.events {
+		deviceTime := ""
+		if !e.Timestamp.IsZero() {
+			deviceTime = e.Timestamp.Format(time.RFC3339Nano)
+		}
+		_ = writer.Write([]string{
+			deviceTime,
+			e.Received.Format(time.RFC3339Nano),
+			e.Type.String(),
+			strconv.Itoa(e.Address),
+			w.profile.PointName(e.Type.PointType(), e.Address),
+			e.Kind.String(),
+			e.Message,
+			strconv.FormatFloat(e.Value, 'g', -1, 64),
+			iec_client.CauseString(e.Cause),
+			iec_client.QualityString(e.Quality),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		w.logger.Errorf("Error exporting events: %v", err)
+		return
+	}
+	w.logger.Infof("Exported %d events to %s", len(w.events.events), path)
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"iec104/iec_client"
+)
+
+// asduHeaders are the columns of the ASDU counter table
+var asduHeaders = []string{"Dir", "Type", "COT", "Count"}
+
+// setupStatsView creates the link statistics page: the counters above a
+// table of the ASDUs by type and cause
+func (w *workspace) setupStatsView() {
+	w.statsText = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	w.statsText.SetBorder(true).SetTitle("Link statistics (r: reset)")
+	w.statsTable = tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	w.statsTable.SetBorder(true).SetTitle("ASDUs by type and cause")
+	w.stats = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(w.statsText, 17, 0, false).
+		AddItem(w.statsTable, 0, 1, true)
+
+	w.statsTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'r' {
+			w.client.ResetStats()
+			w.logger.Infof("Link statistics reset")
+			w.refreshStats()
+			return nil
+		}
+		return event
+	})
+}
+
+// refreshStats redraws the statistics while they are shown
+func (w *workspace) refreshStats() {
+	if !w.statsMode {
+		return
+	}
+	s := w.client.Stats()
+	now := time.Now()
+
+	var b strings.Builder
+	if !s.ConnectedSince.IsZero() {
+		fmt.Fprintf(&b, "[yellow]State[white]          [green]Connected[white] since %s (%s)\n",
+			s.ConnectedSince.Format("2006-01-02 15:04:05"), now.Sub(s.ConnectedSince).Round(time.Second))
+	} else {
+		b.WriteString("[yellow]State[white]          [red]Disconnected[white]\n")
+	}
+	fmt.Fprintf(&b, "[yellow]Connects[white]       %d, %d reconnects, %d disconnects\n", s.Connects, s.Reconnects, s.Disconnects)
+	fmt.Fprintf(&b, "[yellow]Last received[white]  %s\n", sinceText(s.LastReceived, now))
+	fmt.Fprintf(&b, "[yellow]Last sent[white]      %s\n\n", sinceText(s.LastSent, now))
+
+	fmt.Fprintf(&b, "[yellow]Frames         %8s %8s %8s %8s[white]\n", "I", "S", "U", "Total")
+	for _, r := range []struct {
+		name   string
+		counts iec_client.FrameCounts
+	}{{"Received", s.Received}, {"Sent", s.Sent}} {
+		fmt.Fprintf(&b, "  %-12s %8d %8d %8d %8d\n", r.name, r.counts.I, r.counts.S, r.counts.U, r.counts.Total())
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "[yellow]Interrogations[white] %d", s.Interrogations)
+	if s.Interrogations > 0 {
+		fmt.Fprintf(&b, ", last %s, max %s", roundDuration(s.LastInterrogation), roundDuration(s.MaxInterrogation))
+	}
+	b.WriteString("\n")
+	rtt := s.CommandRoundTrip
+	fmt.Fprintf(&b, "[yellow]Command RTT[white]    %d confirmed", rtt.Count)
+	if rtt.Count > 0 {
+		fmt.Fprintf(&b, ", last %s, average %s, max %s", roundDuration(rtt.Last), roundDuration(rtt.Average), roundDuration(rtt.Max))
+	}
+	b.WriteString("\n")
+	negColor := "white"
+	if s.NegativeConfirmations > 0 {
+		negColor = "red"
+	}
+	fmt.Fprintf(&b, "[yellow]Negative[white]       [%s]%d[white] negative confirmations received\n", negColor, s.NegativeConfirmations)
+	b.WriteString("[yellow]Commands[white]      ")
+	if len(s.Commands) == 0 {
+		b.WriteString(" none")
+	}
+	for _, c := range s.Commands {
+		fmt.Fprintf(&b, " %s %s %d", c.Type, c.Outcome, c.Count)
+	}
+	fmt.Fprintf(&b, "\n\n[gray]Counters since %s[white]", s.Since.Format("2006-01-02 15:04:05"))
+	w.statsText.SetText(b.String())
+
+	row, _ := w.statsTable.GetSelection()
+	w.statsTable.Clear()
+	for col, h := range asduHeaders {
+		w.statsTable.SetCell(0, col, tview.NewTableCell(h).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+	for i, a := range s.ASDUs {
+		color := tcell.ColorWhite
+		if a.Direction == iec_client.FrameSent {
+			color = tcell.ColorDarkCyan
+		}
+		cells := []string{a.Direction.String(), a.Type.String(), iec_client.CauseString(a.Cause), strconv.FormatUint(a.Count, 10)}
+		for col, text := range cells {
+			cell := tview.NewTableCell(text).SetTextColor(color).SetExpansion(1)
+			if col == 3 {
+				cell.SetAlign(tview.AlignRight)
+			}
+			w.statsTable.SetCell(i+1, col, cell)
+		}
+	}
+	if row >= 1 && row <= len(s.ASDUs) {
+		w.statsTable.Select(row, 0)
+	}
+}
+
+// sinceText shows a time and how long ago it was
+func sinceText(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return fmt.Sprintf("%s (%s ago)", t.Format("15:04:05.000"), roundDuration(now.Sub(t)))
+}
+
+// roundDuration rounds a duration for display
+func roundDuration(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(100 * time.Millisecond)
+	}
+	return d.Round(time.Millisecond)
+}
+
+// toggleStats switches between the link statistics and the data view
+func (w *workspace) toggleStats() {
+	w.statsMode = !w.statsMode
+	w.eventsMode = false
+	w.alarmsMode = false
+	w.monitorMode = false
+	w.refreshStats()
+	w.showView()
+}
@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"iec104/config"
+	"iec104/iec_client"
+)
+
+// trendWindows are the selectable time spans of the trend chart
+var trendWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute, time.Hour}
+
+// trendColors are assigned to the plotted points in order
+var trendColors = []tcell.Color{tcell.ColorGreen, tcell.ColorYellow, tcell.ColorAqua, tcell.ColorFuchsia, tcell.ColorOrange, tcell.ColorWhite}
+
+// trendHeight is the height of the trend pane including its border
+const trendHeight = 14
+
+// trendAxisWidth is the width of the value labels left of the plot
+const trendAxisWidth = 10
+
+// trendChart plots the recent history of selected telemetry points using
+// braille characters, which give 2x4 dots per terminal cell
+type trendChart struct {
+	*tview.Box
+
+	w      *workspace
+	ioas   []int
+	window int
+}
+
+// newTrendChart creates an empty trend chart for a workspace
+func newTrendChart(w *workspace) *trendChart {
+	t := &trendChart{Box: tview.NewBox(), w: w, window: 1}
+	t.SetBorder(true)
+	t.updateTitle()
+	return t
+}
+
+// updateTitle shows the selected window in the border
+func (t *trendChart) updateTitle() {
+	t.SetTitle(fmt.Sprintf("Trend (%s) - t: add/remove point, w: window, F9: hide", trendWindows[t.window]))
+}
+
+// toggle adds a telemetry point to the chart or removes it, and reports
+// whether the point is plotted afterwards
+func (t *trendChart) toggle(ioa int) bool {
+	for i, v := range t.ioas {
+		if v == ioa {
+			t.ioas = append(t.ioas[:i], t.ioas[i+1:]...)
+			return false
+		}
+	}
+	if len(t.ioas) >= len(trendColors) {
+		t.w.logger.Errorf("At most %d points can be plotted", len(trendColors))
+		return false
+	}
+	t.ioas = append(t.ioas, ioa)
+	return true
+}
+
+// has reports whether a point is plotted
+func (t *trendChart) has(ioa int) bool {
+	for _, v := range t.ioas {
+		if v == ioa {
+			return true
+		}
+	}
+	return false
+}
+
+// cycleWindow selects the next time window
+func (t *trendChart) cycleWindow() {
+	t.window = (t.window + 1) % len(trendWindows)
+	t.updateTitle()
+}
+
+// Draw renders the chart and a legend line with statistics per point
+func (t *trendChart) Draw(screen tcell.Screen) {
+	t.DrawForSubclass(screen, t)
+	x, y, width, height := t.GetInnerRect()
+
+	if len(t.ioas) == 0 {
+		tview.Print(screen, "No points selected. Press t on a telemetry point or tick \"Trend\" in its dialog.",
+			x, y, width, tview.AlignLeft, tcell.ColorGray)
+		return
+	}
+
+	plotHeight := height - len(t.ioas)
+	plotWidth := width - trendAxisWidth - 1
+	if plotHeight < 2 || plotWidth < 4 {
+		return
+	}
+
+	now := time.Now()
+	window := trendWindows[t.window]
+	start := now.Add(-window)
+
+	series := make([][]iec_client.Sample, len(t.ioas))
+	min, max := math.Inf(1), math.Inf(-1)
+	for i, ioa := range t.ioas {
+		series[i] = t.w.client.History(ioa, start)
+		for _, s := range series[i] {
+			min = math.Min(min, s.Value)
+			max = math.Max(max, s.Value)
+		}
+	}
+	if math.IsInf(min, 1) {
+		min, max = 0, 1
+	} else if min == max {
+		min, max = min-1, max+1
+	}
+
+	// Plot into a dot grid, then map every cell to a braille character
+	dotsX, dotsY := plotWidth*2, plotHeight*4
+	cells := make([]uint8, plotWidth*plotHeight)
+	colors := make([]tcell.Color, plotWidth*plotHeight)
+	plot := func(dx, dy int, color tcell.Color) {
+		if dx < 0 || dx >= dotsX || dy < 0 || dy >= dotsY {
+			return
+		}
+		i := dy/4*plotWidth + dx/2
+		cells[i] |= brailleBit(dx%2, dy%4)
+		colors[i] = color
+	}
+	toDots := func(at time.Time, v float64) (int, int) {
+		dx := int(float64(at.Sub(start)) / float64(window) * float64(dotsX-1))
+		dy := int(math.Round((max - v) / (max - min) * float64(dotsY-1)))
+		return dx, dy
+	}
+
+	for i, samples := range series {
+		color := trendColors[i]
+		for j, s := range samples {
+			x0, y0 := toDots(s.Time, s.Value)
+			// Values are reported on change, so hold each one until the next
+			x1 := dotsX - 1
+			if j+1 < len(samples) {
+				x1, _ = toDots(samples[j+1].Time, s.Value)
+			}
+			for dx := x0; dx <= x1; dx++ {
+				plot(dx, y0, color)
+			}
+			if j+1 < len(samples) {
+				_, y1 := toDots(samples[j+1].Time, samples[j+1].Value)
+				for dy := y0; dy != y1; dy += sign(y1 - y0) {
+					plot(x1, dy, color)
+				}
+			}
+		}
+	}
+
+	plotX := x + trendAxisWidth + 1
+	for row := 0; row < plotHeight; row++ {
+		screen.SetContent(plotX-1, y+row, tview.BoxDrawingsLightVertical, nil, tcell.StyleDefault.Foreground(tcell.ColorGray))
+		for col := 0; col < plotWidth; col++ {
+			i := row*plotWidth + col
+			if cells[i] == 0 {
+				continue
+			}
+			screen.SetContent(plotX+col, y+row, rune(0x2800+int(cells[i])), nil, tcell.StyleDefault.Foreground(colors[i]))
+		}
+	}
+
+	first := t.w.profile.FindPoint(config.PointTelemetry, t.ioas[0])
+	label := func(v float64) string {
+		return fmt.Sprintf("%.*f", first.Precision(), v)
+	}
+	tview.Print(screen, label(max), x, y, trendAxisWidth, tview.AlignRight, tcell.ColorGray)
+	tview.Print(screen, label((min+max)/2), x, y+plotHeight/2, trendAxisWidth, tview.AlignRight, tcell.ColorGray)
+	tview.Print(screen, label(min), x, y+plotHeight-1, trendAxisWidth, tview.AlignRight, tcell.ColorGray)
+
+	for i, ioa := range t.ioas {
+		tview.Print(screen, t.legend(ioa, series[i]), x, y+plotHeight+i, width, tview.AlignLeft, trendColors[i])
+	}
+}
+
+// legend describes a plotted point with its current, min, max and average value
+func (t *trendChart) legend(ioa int, samples []iec_client.Sample) string {
+	point := t.w.profile.FindPoint(config.PointTelemetry, ioa)
+	name := fmt.Sprintf("%d", ioa)
+	if point != nil && point.Name != "" {
+		name = fmt.Sprintf("%s (%d)", point.Name, ioa)
+	}
+	if len(samples) == 0 {
+		return fmt.Sprintf("● %s: no data", tview.Escape(name))
+	}
+
+	min, max, sum := math.Inf(1), math.Inf(-1), 0.0
+	for _, s := range samples {
+		min = math.Min(min, s.Value)
+		max = math.Max(max, s.Value)
+		sum += s.Value
+	}
+	avg := sum / float64(len(samples))
+	current := samples[len(samples)-1].Value
+
+	return tview.Escape(fmt.Sprintf("● %s: cur %s  min %s  max %s  avg %s  (%d samples)",
+		name, point.FormatValue(current), point.FormatValue(min), point.FormatValue(max), point.FormatValue(avg), len(samples)))
+}
+
+// brailleBit returns the bit of a dot within a braille character
+func brailleBit(dx, dy int) uint8 {
+	if dx == 0 {
+		return [4]uint8{0x01, 0x02, 0x04, 0x40}[dy]
+	}
+	return [4]uint8{0x08, 0x10, 0x20, 0x80}[dy]
+}
+
+func sign(v int) int {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
+
+// toggleTrend shows or hides the trend pane below the data view
+func (w *workspace) toggleTrend() {
+	w.trendVisible = !w.trendVisible
+	if w.trendVisible {
+		w.root.ResizeItem(w.trend, trendHeight, 0)
+	} else {
+		w.root.ResizeItem(w.trend, 0, 0)
+	}
+}
+
+// toggleTrendPoint adds or removes a telemetry point from the trend chart,
+// showing the pane if needed
+func (w *workspace) toggleTrendPoint(ioa int) {
+	if w.trend.toggle(ioa) {
+		w.logger.Infof("Plotting telemetry %d", ioa)
+		if !w.trendVisible {
+			w.toggleTrend()
+		}
+	} else {
+		w.logger.Infof("Removed telemetry %d from the trend", ioa)
+	}
+}
+
+// selectedAddress returns the address of the selected point in the
+// current view, or false if nothing is selected
+func (w *workspace) selectedAddress() (int, bool) {
+	base := w.currentTab.PointType().BaseAddress()
+	if w.listMode {
+		row, _ := w.listTable.GetSelection()
+		if row < 1 || row > len(w.list.rows) {
+			return 0, false
+		}
+		return w.list.rows[row-1], true
+	}
+
+	if selectable, _ := w.dataTable.GetSelectable(); !selectable {
+		return 0, false
+	}
+	row, col := w.dataTable.GetSelection()
+	if row < 1 || col < 1 {
+		return 0, false
+	}
+	switch w.currentTab {
+	case iec_client.Telemetry, iec_client.Teleindication:
+		return base + (row-1)/2*10 + col - 1, true
+	default:
+		return base + (row-1)*10 + col - 1, true
+	}
+}
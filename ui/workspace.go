@@ -0,0 +1,908 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/thinkgos/go-iecp5/asdu"
+	"iec104/capture"
+	"iec104/config"
+	"iec104/iec_client"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// workspace is one connection with its own client, data table and status
+type workspace struct {
+	ui         *App
+	id         int
+	profile    *config.Profile
+	client     *iec_client.IEC104Client
+	logger     *workspaceLogger
+	dataTable  *tview.Table
+	currentTab iec_client.DataType
+
+	// root stacks the filter bar on top of the grid and list views
+	root      *tview.Flex
+	filterBar *tview.InputField
+	filter    pointFilter
+	view      *tview.Pages
+	// trend is the chart pane below the views, hidden until trendVisible
+	trend        *trendChart
+	trendVisible bool
+	list         *pointList
+	listTable    *tview.Table
+	listMode     bool
+	// events is the sequence-of-events view, shown instead of the data in eventsMode
+	events      *soeList
+	eventsTable *tview.Table
+	eventsMode  bool
+	// alarms is the alarm list, shown instead of the data in alarmsMode;
+	// alarmsSeen is the newest alarm the bell was rung for
+	alarms      *alarmList
+	alarmsTable *tview.Table
+	alarmsMode  bool
+	alarmsSeen  time.Time
+	// frames is the protocol monitor, shown instead of the data in monitorMode
+	frames      *frameList
+	framesTable *tview.Table
+	frameDetail *tview.TextView
+	monitor     *tview.Flex
+	monitorMode bool
+	// stats is the link statistics page, shown instead of the data in statsMode
+	stats      *tview.Flex
+	statsText  *tview.TextView
+	statsTable *tview.Table
+	statsMode  bool
+	// capture is the file the frames are recorded to while capturePath is set
+	capture     capture.Writer
+	capturePath string
+
+	started   atomic.Bool
+	replaying atomic.Bool
+}
+
+// newWorkspace creates a workspace and its client for the given profile
+func newWorkspace(ui *App, id int, profile *config.Profile) *workspace {
+	w := &workspace{
+		ui:         ui,
+		id:         id,
+		profile:    profile,
+		client:     iec_client.NewIEC104Client(profile),
+		currentTab: iec_client.Telemetry,
+	}
+	w.logger = &workspaceLogger{logger: ui.logger, ws: w}
+
+	w.setupDataTable()
+	w.setupListView()
+	w.setupEventsView()
+	w.setupAlarmsView()
+	w.setupMonitorView()
+	w.setupStatsView()
+	w.setupFilterBar()
+	w.view = tview.NewPages().
+		AddPage("grid", w.dataTable, true, true).
+		AddPage("list", w.listTable, true, false).
+		AddPage("events", w.eventsTable, true, false).
+		AddPage("alarms", w.alarmsTable, true, false).
+		AddPage("monitor", w.monitor, true, false).
+		AddPage("stats", w.stats, true, false)
+	w.trend = newTrendChart(w)
+	w.root = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(w.filterBar, 1, 0, false).
+		AddItem(w.view, 0, 1, true).
+		AddItem(w.trend, 0, 0, false)
+
+	w.client.RegisterConnectionStateHandler(func(b bool) {
+		w.ui.app.QueueUpdateDraw(func() {
+			w.ui.updateStatusBar()
+			w.ui.updateWorkspaceBar()
+		})
+	})
+	w.client.RegisterDataHandler(w.handleData)
+	w.client.Logger = w.logger
+	w.client.LibraryDebug = ui.options.LogLevel == LoggerLevelDebug
+
+	return w
+}
+
+// pageName returns the name of the workspace's page in the data pages
+func (w *workspace) pageName() string {
+	return fmt.Sprintf("workspace-%d", w.id)
+}
+
+// close stops the client of the workspace
+func (w *workspace) close() {
+	w.stopCapture()
+	w.client.Close()
+}
+
+// setProfile reloads the client and data table for another profile
+func (w *workspace) setProfile(profile *config.Profile) {
+	wasStarted := w.started.Load()
+	if wasStarted {
+		w.toggleConnection()
+	}
+
+	w.profile = profile
+	w.client.ClearData()
+	w.client.ResetStats()
+	w.client.UpdateConfig(profile)
+	w.updateTableHeaders()
+	w.refreshData()
+
+	if wasStarted {
+		w.toggleConnection()
+	}
+}
+
+// handleData updates the table cell for a received point. It runs on the
+// network goroutine, so the workspace is only looked at in the UI goroutine.
+func (w *workspace) handleData(typ iec_client.DataType, iot int, data interface{}) {
+	w.ui.app.QueueUpdateDraw(func() {
+		w.showData(typ, iot, data)
+	})
+}
+
+// showData updates the table cell for a received point
+func (w *workspace) showData(typ iec_client.DataType, iot int, data interface{}) {
+	if typ != w.currentTab {
+		return
+	}
+	if w.listMode {
+		w.list.ensure(iot)
+		return
+	}
+
+	var (
+		rowMax  int
+		address int
+	)
+
+	switch typ {
+	case iec_client.Telemetry:
+		rowMax = int(math.Ceil(float64(w.profile.TelemetryCount) / 10))
+		address = iot - 0x4000 - 1
+		if address > w.profile.TelemetryCount {
+			return
+		}
+	case iec_client.Teleindication:
+		rowMax = int(math.Ceil(float64(w.profile.TeleindCount) / 10))
+		address = iot - 1
+		if address > w.profile.TeleindCount {
+			return
+		}
+	default:
+		return
+	}
+	if address < 0 {
+		w.logger.Errorf("Invalid address: %d", address)
+		return
+	}
+
+	row := (address/10 + 1) * 2
+	col := address%10 + 1
+
+	if row > rowMax*2 {
+		return
+	}
+
+	switch val := data.(type) {
+	case float64:
+		w.dataTable.SetCell(row, col, w.highlightMatch(w.valueCell(config.PointTelemetry, iot, val), iot))
+	case bool:
+		w.dataTable.SetCell(row, col, w.highlightMatch(w.stateCell(config.PointTeleindication, iot, val), iot))
+	case asdu.DoublePoint:
+		w.dataTable.SetCell(row, col, w.highlightMatch(w.doubleStateCell(iot, val), iot))
+	}
+}
+
+// valueCell renders an analog value with the point's unit and precision,
+// highlighting values outside its configured range or in alarm
+func (w *workspace) valueCell(typ config.PointType, ioa int, value float64) *tview.TableCell {
+	point := w.profile.FindPoint(typ, ioa)
+	cell := tview.NewTableCell(point.FormatValue(value))
+	if !point.InRange(value) {
+		cell.SetTextColor(tcell.ColorRed)
+	}
+	if typ == config.PointTelemetry {
+		w.alarmHighlight(cell, iec_client.Telemetry, ioa)
+	}
+	return cell
+}
+
+// stateCell renders a digital state with the point's configured label and color
+func (w *workspace) stateCell(typ config.PointType, ioa int, on bool) *tview.TableCell {
+	cell := labelCell(w.profile.FindPoint(typ, ioa).StateLabel(on))
+	if typ == config.PointTeleindication {
+		w.alarmHighlight(cell, iec_client.Teleindication, ioa)
+	}
+	return cell
+}
+
+// doubleStateCell renders a double point indication state
+func (w *workspace) doubleStateCell(ioa int, state asdu.DoublePoint) *tview.TableCell {
+	cell := labelCell(w.profile.FindPoint(config.PointTeleindication, ioa).DoubleStateLabel(int(state)))
+	return w.alarmHighlight(cell, iec_client.Teleindication, ioa)
+}
+
+func labelCell(text, color string) *tview.TableCell {
+	cell := tview.NewTableCell(text)
+	if color != "" {
+		cell.SetTextColor(tcell.GetColor(color))
+	}
+	return cell
+}
+
+// setupDataTable creates the data table
+func (w *workspace) setupDataTable() {
+	w.dataTable = tview.NewTable().SetBorders(true)
+	w.dataTable.SetBorder(true).SetTitle("Data")
+
+	// Make headers fixed so they don't disappear when scrolling
+	w.dataTable.SetFixed(1, 0)
+	w.dataTable.SetInputCapture(w.handleViewKey)
+	w.dataTable.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			w.dataTable.SetSelectable(true, true)
+		}
+	})
+
+	// Initialize table headers
+	w.updateTableHeaders()
+
+	// Initialize table data
+	w.updateTableData()
+
+	// Also keep the selected func for double-clicks
+	w.dataTable.SetSelectedFunc(func(row, column int) {
+		if row == 0 {
+			// Header row, do nothing
+			return
+		}
+
+		// Handle cell selection based on current tab
+		switch w.currentTab {
+		case iec_client.Telecontrol, iec_client.Teleregulation:
+			w.logger.Infof("Selected %s row %d, column %d", w.currentTab, row-1, column-1)
+			w.showPointDialog((row-1)*10 + column - 1)
+		case iec_client.Telemetry, iec_client.Teleindication:
+			w.logger.Infof("Selected %s row %d, column %d", w.currentTab, row-1, column-1)
+			w.showPointDialog((row-1)/2*10 + column - 1)
+		}
+
+		w.dataTable.SetSelectable(false, false)
+	})
+}
+
+// updateTableHeaders updates the table headers based on the current tab
+func (w *workspace) updateTableHeaders() {
+	w.dataTable.Clear()
+
+	for col := 0; col < 10; col++ {
+		w.dataTable.SetCell(0, col+1, tview.NewTableCell(fmt.Sprintf("%-10d", col)).SetAlign(tview.AlignCenter).SetSelectable(false).SetTextColor(tcell.ColorYellow))
+	}
+}
+
+// updateTableData updates the table data based on the current tab
+func (w *workspace) updateTableData() {
+	w.logger.Debugf("Config: %+v", w.profile)
+	// Clear existing data rows but keep headers
+	for row := 1; row < w.dataTable.GetRowCount(); row++ {
+		for col := 0; col < w.dataTable.GetColumnCount(); col++ {
+			w.dataTable.SetCell(row, col, tview.NewTableCell(""))
+		}
+	}
+
+	// Populate data based on current tab
+	switch w.currentTab {
+	case iec_client.Telemetry:
+		rowMax := int(math.Ceil(float64(w.profile.TelemetryCount) / 10))
+		for row := 0; row < rowMax; row++ {
+			w.dataTable.SetCell((row)*2+1, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
+			w.dataTable.SetCell((row+1)*2, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
+		}
+		for _, point := range w.profile.PointsOf(config.PointTelemetry) {
+			index, desc := point.Address-config.TelemetryBaseAddress, point.Name
+			if index < 0 {
+				continue
+			}
+			row := (index/10+1)*2 - 1
+			col := index%10 + 1
+			if row > rowMax*2 {
+				continue
+			}
+			if row < 1 {
+				w.logger.Errorf("Invalid telemetry row: %d", row)
+				continue
+			}
+			w.dataTable.SetCell(row, col, tview.NewTableCell(desc).SetTextColor(tcell.ColorGreen).SetSelectable(false))
+		}
+		for _, point := range w.client.Points(iec_client.Telemetry) {
+			ioa := point.Address
+			address := ioa - 0x4000 - 1
+			if address < 0 {
+				w.logger.Errorf("Invalid telemetry address: %d", address)
+				continue
+			}
+			if address >= w.profile.TelemetryCount {
+				continue
+			}
+			row := (address/10 + 1) * 2
+			col := address%10 + 1
+
+			if row > rowMax*2 {
+				continue
+			}
+
+			w.dataTable.SetCell(row, col, w.highlightMatch(w.valueCell(config.PointTelemetry, ioa, point.Value), ioa))
+		}
+	case iec_client.Teleindication:
+		rowMax := int(math.Ceil(float64(w.profile.TeleindCount) / 10))
+		for row := 0; row < rowMax; row++ {
+			w.dataTable.SetCell((row)*2+1, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
+			w.dataTable.SetCell((row+1)*2, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
+		}
+		for _, point := range w.profile.PointsOf(config.PointTeleindication) {
+			index, desc := point.Address-config.TeleindBaseAddress, point.Name
+			if index < 0 {
+				continue
+			}
+			row := (index/10+1)*2 - 1
+			col := index%10 + 1
+			if row > rowMax*2 {
+				continue
+			}
+			if row < 1 {
+				w.logger.Errorf("Invalid teleindication row: %d", row)
+				continue
+			}
+			w.dataTable.SetCell(row, col, tview.NewTableCell(desc).SetTextColor(tcell.ColorGreen).SetSelectable(false))
+		}
+		for _, point := range w.client.Points(iec_client.Teleindication) {
+			ioa := point.Address
+			address := ioa - 1
+			if address < 0 {
+				w.logger.Errorf("Invalid teleindication address: %d", address)
+				continue
+			}
+			if address >= w.profile.TeleindCount {
+				continue
+			}
+
+			row := (address/10 + 1) * 2
+			col := address%10 + 1
+
+			if row > rowMax*2 {
+				continue
+			}
+
+			if point.Double {
+				w.dataTable.SetCell(row, col, w.highlightMatch(w.doubleStateCell(ioa, point.DoubleState), ioa))
+			} else {
+				w.dataTable.SetCell(row, col, w.highlightMatch(w.stateCell(config.PointTeleindication, ioa, point.State), ioa))
+			}
+		}
+	case iec_client.Telecontrol:
+		rowMax := 10
+		// Add sample telecontrol points or actual ones
+		for row := 0; row < rowMax+1; row++ {
+			w.dataTable.SetCell(row+1, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
+			for col := 0; col < 11; col++ {
+				if row == 0 || col == 0 {
+					continue
+				}
+				index := (row-1)*10 + col - 1
+				ioa := config.TelecontrolBaseAddress + index
+				if v, ok := w.client.Point(iec_client.Telecontrol, ioa); ok {
+					w.dataTable.SetCell(row, col, w.highlightMatch(w.stateCell(config.PointTelecontrol, ioa, v.State), ioa))
+				} else {
+					w.dataTable.SetCell(row, col, w.highlightMatch(w.stateCell(config.PointTelecontrol, ioa, false), ioa))
+				}
+			}
+		}
+	case iec_client.Teleregulation:
+		// Add sample teleregulation points or actual ones
+		rowMax := 10
+		for row := 0; row < rowMax+1; row++ {
+			w.dataTable.SetCell(row+1, 0, tview.NewTableCell(strconv.Itoa(row*10)).SetSelectable(false))
+			for col := 0; col < 11; col++ {
+				if row == 0 || col == 0 {
+					continue
+				}
+				index := (row-1)*10 + col - 1
+				ioa := config.TeleregulationBaseAddress + index
+				if v, ok := w.client.Point(iec_client.Teleregulation, ioa); ok {
+					w.dataTable.SetCell(row, col, w.highlightMatch(w.valueCell(config.PointTeleregulation, ioa, v.Value), ioa))
+				} else {
+					w.dataTable.SetCell(row, col, w.highlightMatch(w.valueCell(config.PointTeleregulation, ioa, 0), ioa))
+				}
+			}
+		}
+	}
+}
+
+// toggleConnection toggles the connection state
+func (w *workspace) toggleConnection() {
+	if w.replaying.Load() {
+		w.logger.Infof("Cannot connect while a capture is replayed")
+		return
+	}
+	if w.started.Load() {
+		err := w.client.Disconnect()
+		if err != nil {
+			w.logger.Infof("Error disconnecting: %v", err)
+			return
+		}
+		w.logger.Infof("Disconnected from server")
+		w.started.Store(false)
+	} else {
+		err := w.client.Connect()
+		if err != nil {
+			w.logger.Infof("Error connecting: %v", err)
+			return
+		}
+		w.logger.Infof("Connecting to server %s:%d", w.profile.IPAddress, w.profile.Port)
+		w.started.Store(true)
+	}
+
+	w.ui.updateConnectButton()
+}
+
+// showPointDialog opens the dialog matching the current tab for the point
+// at the given offset from the tab's base address
+func (w *workspace) showPointDialog(index int) {
+	if index < 0 {
+		return
+	}
+	switch w.currentTab {
+	case iec_client.Telecontrol:
+		w.showTelecontrolDialog(index)
+	case iec_client.Teleregulation:
+		w.showTeleregulationDialog(index)
+	default:
+		w.showDescriptionDialog(index)
+	}
+}
+
+// showTelecontrolDialog shows a dialog for sending telecontrol commands
+func (w *workspace) showTelecontrolDialog(index int) {
+	// Create form for telecontrol
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle("Send Telecontrol Command")
+
+	// Add address field (read-only)
+	form.AddInputField("Offset", fmt.Sprintf("%d", index), 10, nil, nil).
+		SetFieldBackgroundColor(tcell.ColorDarkGray)
+
+	// Add value field, labelled with the point's states
+	ioa := config.TelecontrolBaseAddress + index
+	point := w.profile.FindPoint(config.PointTelecontrol, ioa)
+	offText, _ := point.StateLabel(false)
+	onText, _ := point.StateLabel(true)
+	value := false
+	form.AddDropDown("Value", []string{offText, onText}, 0, func(_ string, option int) {
+		value = option == 1
+	})
+
+	// Add buttons
+	form.AddButton("Send", func() {
+		w.logger.Infof("Sending telecontrol command to address %d, value: %v", index, value)
+		go func() {
+			err := w.client.SendTelecontrol(index, value)
+			w.ui.app.QueueUpdateDraw(func() {
+				if err != nil {
+					w.logger.Infof("Error sending telecontrol: %v", err)
+					return
+				}
+				text, _ := point.StateLabel(value)
+				w.logger.Infof("Telecontrol command confirmed for address %d, value: %s", index, text)
+				w.refreshData()
+			})
+		}()
+		w.ui.pages.RemovePage("dialog")
+	})
+	form.AddButton("Cancel", func() {
+		w.ui.pages.RemovePage("dialog")
+	})
+
+	// Create a modal for the form
+	modal := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			SetDirection(tview.FlexColumn).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 40, 1, true).
+			AddItem(nil, 0, 1, false),
+			10, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	// Add the page and show it
+	w.ui.pages.AddPage("dialog", modal, true, true)
+}
+
+// showTeleregulationDialog shows a dialog for sending teleregulation setpoints
+func (w *workspace) showTeleregulationDialog(index int) {
+	// Create form for teleregulation
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle("Send Teleregulation Setpoint")
+
+	// Add address field (read-only)
+	form.AddInputField("Offset", fmt.Sprintf("%d", index), 10, nil, nil).
+		SetFieldBackgroundColor(tcell.ColorDarkGray)
+
+	point := w.profile.FindPoint(config.PointTeleregulation, config.TeleregulationBaseAddress+index)
+	label := "Value"
+	if point != nil && point.Unit != "" {
+		label = fmt.Sprintf("Value (%s)", point.Unit)
+	}
+	if point.HasRange() {
+		form.AddInputField("Range", fmt.Sprintf("%s to %s", point.FormatValue(point.Min), point.FormatValue(point.Max)), 24, nil, nil).
+			SetFieldBackgroundColor(tcell.ColorDarkGray)
+	}
+
+	// Add value field
+	valueStr := "0.0"
+	form.AddInputField(label, valueStr, 10, nil, func(text string) {
+		valueStr = text
+	})
+
+	// Add buttons
+	form.AddButton("Send", func() {
+		var value float64
+		fmt.Sscanf(valueStr, "%f", &value)
+		w.logger.Infof("Sending teleregulation setpoint to address %d, value: %v", index, value)
+		go func() {
+			err := w.client.SendTelemetry(index, value)
+			w.ui.app.QueueUpdateDraw(func() {
+				if err != nil {
+					w.logger.Infof("Error sending teleregulation: %v", err)
+					return
+				}
+				w.logger.Infof("Teleregulation setpoint confirmed for address %d, value: %s", index, point.FormatValue(value))
+				w.refreshData()
+			})
+		}()
+		w.ui.pages.RemovePage("dialog")
+	})
+	form.AddButton("Cancel", func() {
+		w.ui.pages.RemovePage("dialog")
+	})
+
+	// Create a modal for the form
+	modal := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			SetDirection(tview.FlexColumn).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 40, 1, true).
+			AddItem(nil, 0, 1, false),
+			12, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	// Add the page and show it
+	w.ui.pages.AddPage("dialog", modal, true, true)
+}
+
+// showDescriptionDialog shows a dialog for editing point descriptions,
+// for telemetry also the unit, scaling, display range and alarm limits,
+// and for teleindications the alarm state
+func (w *workspace) showDescriptionDialog(index int) {
+
+	pointType := w.currentTab.PointType()
+	ioa := pointType.BaseAddress() + index
+	edited := config.Point{Address: ioa, Type: pointType}
+	if point := w.profile.FindPoint(pointType, ioa); point != nil {
+		edited = *point
+	}
+	analog := pointType == config.PointTelemetry
+
+	// Create form for description
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle("Edit Point Description")
+
+	// Add address field (read-only)
+	form.AddInputField("Offset", fmt.Sprintf("%d", index), 10, nil, nil).
+		SetFieldBackgroundColor(tcell.ColorDarkGray)
+
+	// Add description field
+	form.AddInputField("Description", edited.Name, 40, nil, func(text string) {
+		edited.Name = text
+	})
+
+	// numbers are parsed on Save, so a typo is reported instead of saved as 0
+	texts := make(map[string]string)
+	numbers := []struct {
+		label string
+		value *float64
+	}{{"Scale", &edited.Scale}, {"Offset (value)", &edited.Offset}, {"Min", &edited.Min}, {"Max", &edited.Max}, {"Deadband", &edited.Deadband}}
+	limits := []struct {
+		label string
+		value **float64
+	}{{"Alarm LL", &edited.LowLow}, {"Alarm L", &edited.Low}, {"Alarm H", &edited.High}, {"Alarm HH", &edited.HighHigh}}
+
+	trend := w.trend.has(ioa)
+	decimals := ""
+	if edited.Decimals != nil {
+		decimals = strconv.Itoa(*edited.Decimals)
+	}
+	if analog {
+		form.AddInputField("Unit", edited.Unit, 10, nil, func(text string) {
+			edited.Unit = text
+		})
+		for _, field := range numbers {
+			field := field
+			form.AddInputField(field.label, formatFloat(*field.value), 12, nil, func(text string) {
+				texts[field.label] = text
+			})
+			if field.label == "Offset (value)" {
+				form.AddInputField("Decimals", decimals, 4, tview.InputFieldInteger, func(text string) {
+					decimals = text
+				})
+			}
+		}
+		for _, limit := range limits {
+			limit := limit
+			form.AddInputField(limit.label, formatLimit(*limit.value), 12, nil, func(text string) {
+				texts[limit.label] = text
+			})
+		}
+		form.AddCheckbox("Trend", trend, func(checked bool) {
+			trend = checked
+		})
+	}
+	if pointType == config.PointTeleindication {
+		onText, _ := edited.StateLabel(!edited.Invert)
+		offText, _ := edited.StateLabel(edited.Invert)
+		states := []string{"", config.AlarmOn, config.AlarmOff}
+		current := 0
+		for i, state := range states {
+			if state == edited.AlarmState {
+				current = i
+			}
+		}
+		form.AddDropDown("Alarm State", []string{"None", onText, offText}, current, func(_ string, index int) {
+			if index >= 0 {
+				edited.AlarmState = states[index]
+			}
+		})
+	}
+
+	// Add buttons
+	form.AddButton("Save", func() {
+		for _, field := range numbers {
+			text, ok := texts[field.label]
+			if !ok {
+				continue
+			}
+			v, err := parseFloat(text)
+			if err != nil {
+				w.logger.Errorf("Invalid %s: %q is not a number", strings.ToLower(field.label), text)
+				return
+			}
+			*field.value = v
+		}
+		if texts["Scale"] != "" && edited.Scale == 0 {
+			w.logger.Errorf("Invalid scale: must not be 0, leave it empty for 1")
+			return
+		}
+		for _, limit := range limits {
+			text, ok := texts[limit.label]
+			if !ok {
+				continue
+			}
+			*limit.value = nil
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			v, err := parseFloat(text)
+			if err != nil {
+				w.logger.Errorf("Invalid %s: %q is not a number", strings.ToLower(limit.label), text)
+				return
+			}
+			*limit.value = &v
+		}
+		edited.Decimals = nil
+		if d, err := strconv.Atoi(decimals); err == nil && d >= 0 {
+			edited.Decimals = &d
+		}
+		if err := edited.ValidateLimits(); err != nil {
+			w.logger.Errorf("Invalid alarm limits: %v", err)
+			return
+		}
+
+		// 保存描述
+		w.profile.SetPoint(edited)
+		if analog && trend != w.trend.has(ioa) {
+			w.toggleTrendPoint(ioa)
+		}
+
+		// 保存配置
+		if err := w.ui.config.Save(); err != nil {
+			w.logger.Errorf("Error saving description: %v", err)
+		} else {
+			w.logger.Infof("Description saved for offset %d", index)
+			w.client.Rescale()
+			w.refreshData()
+		}
+		w.ui.pages.RemovePage("dialog")
+	})
+	form.AddButton("Cancel", func() {
+		w.ui.pages.RemovePage("dialog")
+	})
+
+	height := 12
+	if analog {
+		height = 34
+	}
+
+	// Create a modal for the form
+	modal := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			SetDirection(tview.FlexColumn).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 60, 1, true).
+			AddItem(nil, 0, 1, false),
+			height, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	// Add the page and show it
+	w.ui.pages.AddPage("dialog", modal, true, true)
+}
+
+// formatLimit formats an optional alarm limit for an input field
+func formatLimit(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'g', -1, 64)
+}
+
+// parseFloat parses an optional number of an input field, zero when empty
+func parseFloat(text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+// formatFloat formats an optional number for an input field, empty when zero
+func formatFloat(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// openWorkspace opens a profile in a new workspace and activates it
+func (a *App) openWorkspace(profile *config.Profile) {
+	for _, w := range a.workspaces {
+		if w.profile == profile {
+			a.activateWorkspace(w)
+			return
+		}
+	}
+
+	a.nextWorkspaceID++
+	w := newWorkspace(a, a.nextWorkspaceID, profile)
+	a.workspaces = append(a.workspaces, w)
+	a.dataPages.AddPage(w.pageName(), w.root, true, false)
+	if a.exporter != nil {
+		a.exporter.Add(w.client)
+	}
+	if a.apiServer != nil {
+		a.apiServer.Add(w.client)
+	}
+	if a.rpcServer != nil {
+		a.rpcServer.Add(w.client)
+	}
+	a.activateWorkspace(w)
+	a.logger.Infof("Opened workspace for profile %s", profile.Name)
+}
+
+// closeWorkspace stops and removes a workspace. The last workspace stays open.
+func (a *App) closeWorkspace(w *workspace) {
+	if len(a.workspaces) <= 1 {
+		a.logger.Errorf("Cannot close the last workspace")
+		return
+	}
+
+	index := 0
+	for i, ws := range a.workspaces {
+		if ws == w {
+			index = i
+			break
+		}
+	}
+	w.close()
+	if a.exporter != nil {
+		a.exporter.Remove(w.client)
+	}
+	if a.apiServer != nil {
+		a.apiServer.Remove(w.client)
+	}
+	if a.rpcServer != nil {
+		a.rpcServer.Remove(w.client)
+	}
+	a.workspaces = append(a.workspaces[:index], a.workspaces[index+1:]...)
+	a.dataPages.RemovePage(w.pageName())
+	a.logger.Infof("Closed workspace for profile %s", w.profile.Name)
+
+	if index >= len(a.workspaces) {
+		index = len(a.workspaces) - 1
+	}
+	a.activateWorkspace(a.workspaces[index])
+}
+
+// activateWorkspace shows the workspace's data table and status
+func (a *App) activateWorkspace(w *workspace) {
+	a.active = w
+	a.showOverview = false
+	if err := a.config.Select(w.profile.Name); err != nil {
+		a.logger.Errorf("Error selecting profile: %v", err)
+	}
+
+	a.dataPages.SwitchToPage(w.pageName())
+	a.app.SetFocus(w.focusTarget())
+	a.updateWorkspaceBar()
+	a.updateTabBar()
+	a.updateStatusBar()
+	a.updateConnectButton()
+	a.updateCaptureButton()
+}
+
+// cycleWorkspace activates the previous or next workspace
+func (a *App) cycleWorkspace(step int) {
+	for i, w := range a.workspaces {
+		if w == a.active {
+			next := (i + step + len(a.workspaces)) % len(a.workspaces)
+			a.activateWorkspace(a.workspaces[next])
+			return
+		}
+	}
+}
+
+// workspaceOpen reports whether the profile is open in any workspace
+func (a *App) workspaceOpen(profile *config.Profile) bool {
+	for _, w := range a.workspaces {
+		if w.profile == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// setupWorkspaceBar creates the bar listing open workspaces
+func (a *App) setupWorkspaceBar() {
+	a.workspaceBar = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+}
+
+// updateWorkspaceBar updates the workspace bar
+func (a *App) updateWorkspaceBar() {
+	a.workspaceBar.Clear()
+	for i, w := range a.workspaces {
+		color := "red"
+		if w.client.Connected.Load() {
+			color = "green"
+		}
+		fmt.Fprintf(a.workspaceBar, "%s [%s]●%s %d %s %s|",
+			getTabHighlight(!a.showOverview && w == a.active),
+			color,
+			getTabHighlight(!a.showOverview && w == a.active),
+			i+1, tview.Escape(w.profile.Name),
+			getTabHighlight(false))
+	}
+	fmt.Fprintf(a.workspaceBar, "%s F5 Overview %s | F6/F7 Previous/Next Workspace",
+		getTabHighlight(a.showOverview), getTabHighlight(false))
+}